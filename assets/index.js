@@ -97,6 +97,29 @@ function animateRemove(selector) {
 	}, 150);
 }
 
+function formatCountdown(ms) {
+	if (ms <= 0) return "Starting soon";
+	const total = Math.floor(ms / 1000);
+	const d = Math.floor(total / 86400);
+	const h = Math.floor((total % 86400) / 3600);
+	const m = Math.floor((total % 3600) / 60);
+	const s = total % 60;
+	const pad = (n) => String(n).padStart(2, "0");
+	const clock = `${pad(h)}:${pad(m)}:${pad(s)}`;
+	return d > 0 ? `${d}d ${clock}` : clock;
+}
+
+function updateCountdowns() {
+	document.querySelectorAll("[data-countdown]").forEach((el) => {
+		const start = Date.parse(el.dataset.countdown);
+		if (isNaN(start)) return;
+		el.textContent = formatCountdown(start - Date.now());
+	});
+}
+
+document.addEventListener("DOMContentLoaded", updateCountdowns);
+setInterval(updateCountdowns, 1000);
+
 function showFormError(modalId, errorText) {
 	const modal = document.getElementById(modalId);
 	if (!modal) return;
@@ -1,4 +1,4 @@
-const CACHE_NAME = "ytrssil-v3";
+const CACHE_NAME = "ytrssil-v4";
 const ASSETS_TO_CACHE = [
 	"/",
 	"/assets/vendor/bootstrap.min.css",
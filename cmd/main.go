@@ -20,6 +20,9 @@ import (
 	"github.com/TheEdgeOfRage/ytrssil-api/httpserver/ytrssil"
 	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
 	"github.com/TheEdgeOfRage/ytrssil-api/lib/downloader"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/ratelimit"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/websub"
 )
 
 func init() {
@@ -27,22 +30,6 @@ func init() {
 	time.Local = time.UTC
 }
 
-func fetcherRoutine(ctx context.Context, l *slog.Logger, h handler.Handler, fetchInterval time.Duration) {
-	ticker := time.NewTicker(fetchInterval)
-	for {
-		select {
-		case <-ctx.Done():
-			l.Info("Fetcher context done, stopping fetcher")
-			return
-		case <-ticker.C:
-			err := h.FetchVideos(context.Background())
-			if err != nil {
-				l.Error("Failed to fetch videos", "call", "handler.FetchVideos", "err", err)
-			}
-		}
-	}
-}
-
 func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
@@ -69,20 +56,24 @@ func main() {
 	}
 	defer db.Close()
 
-	parser := feedparser.NewParser(logger)
-	youTubeClient := youtube.NewYouTubeClient(logger, cfg.YouTubeAPIKey)
+	parser := feedparser.NewParser(logger, ratelimit.NewHostLimiter(cfg.FetchRateLimit), cfg.FetchTimeout)
+	ledger := quota.NewLedger(logger, db, cfg.YouTubeQuotaBudget)
+	youTubeClient := youtube.NewYouTubeClient(logger, cfg.YouTubeAPIKey, cfg.Region, ledger)
 	downloader := downloader.NewYtdlpDownloader(logger)
 	if err := downloader.ValidateInstallation(); err != nil {
 		logger.Error("yt-dlp validation failed", "error", err)
 		return
 	}
 
+	webSubClient := websub.NewClient(logger, cfg.WebSubHubURL)
+
 	handler := handler.New(
 		logger,
 		db,
 		parser,
 		youTubeClient,
 		downloader,
+		webSubClient,
 		cfg,
 	)
 	if cfg.Dev {
@@ -109,11 +100,32 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	// start periodic fetch videos routine
-	fetcherContext, cancelFetcher := context.WithCancel(context.Background())
+	// start the per-channel poll scheduler
+	pollContext, cancelPoll := context.WithCancel(context.Background())
+	if !cfg.Dev {
+		wg.Go(func() {
+			handler.PollRoutine(pollContext)
+		})
+	}
+
+	webSubContext, cancelWebSub := context.WithCancel(context.Background())
+	if !cfg.Dev {
+		wg.Go(func() {
+			handler.WebSubRoutine(webSubContext)
+		})
+	}
+
+	availabilityContext, cancelAvailability := context.WithCancel(context.Background())
+	if !cfg.Dev {
+		wg.Go(func() {
+			handler.AvailabilityRoutine(availabilityContext)
+		})
+	}
+
+	channelMetadataContext, cancelChannelMetadata := context.WithCancel(context.Background())
 	if !cfg.Dev {
 		wg.Go(func() {
-			fetcherRoutine(fetcherContext, logger, handler, cfg.FetchInterval)
+			handler.ChannelMetadataRoutine(channelMetadataContext)
 		})
 	}
 
@@ -144,7 +156,10 @@ func main() {
 			"error", err,
 		)
 	}
-	cancelFetcher()
+	cancelPoll()
+	cancelWebSub()
+	cancelAvailability()
+	cancelChannelMetadata()
 	if !cfg.Dev {
 		cancelCleanup()
 	}
@@ -10,15 +10,35 @@ import (
 )
 
 type Config struct {
-	Dev             bool          `long:"dev" env:"DEV"`
-	Port            int           `long:"port" env:"PORT" default:"8080"`
-	DBURI           string        `long:"db-uri" env:"DB_URI"`
-	AuthToken       string        `long:"auth-token" env:"AUTH_TOKEN"`
-	YouTubeAPIKey   string        `long:"youtube-api-key" env:"YOUTUBE_API_KEY"`
-	DownloadsDir    string        `long:"downloads-dir" env:"DOWNLOADS_DIR" default:"/var/lib/ytrssil/downloads"`
-	FetchInterval   time.Duration `long:"fetch-interval" env:"FETCH_INTERVAL" default:"5m"`
-	CleanupInterval time.Duration `long:"cleanup-interval" env:"CLEANUP_INTERVAL" default:"1h"`
-	CleanupAge      time.Duration `long:"cleanup-age" env:"CLEANUP_AGE" default:"48h"`
+	Dev                  bool          `long:"dev" env:"DEV"`
+	Port                 int           `long:"port" env:"PORT" default:"8080"`
+	DBURI                string        `long:"db-uri" env:"DB_URI"`
+	AuthToken            string        `long:"auth-token" env:"AUTH_TOKEN"`
+	YouTubeAPIKey        string        `long:"youtube-api-key" env:"YOUTUBE_API_KEY"`
+	Region               string        `long:"region" env:"REGION"`
+	YouTubeQuotaBudget   int           `long:"youtube-quota-budget" env:"YOUTUBE_QUOTA_BUDGET" default:"9000"`
+	DownloadsDir         string        `long:"downloads-dir" env:"DOWNLOADS_DIR" default:"/var/lib/ytrssil/downloads"`
+	AvatarsDir           string        `long:"avatars-dir" env:"AVATARS_DIR" default:"/var/lib/ytrssil/avatars"`
+	FetchInterval        time.Duration `long:"fetch-interval" env:"FETCH_INTERVAL" default:"5m"`
+	MaxFetchInterval     time.Duration `long:"max-fetch-interval" env:"MAX_FETCH_INTERVAL" default:"12h"`
+	FetchWorkers         int           `long:"fetch-workers" env:"FETCH_WORKERS" default:"8"`
+	FetchRateLimit       float64       `long:"fetch-rate-limit" env:"FETCH_RATE_LIMIT" default:"5"`
+	FetchTimeout         time.Duration `long:"fetch-timeout" env:"FETCH_TIMEOUT" default:"30s"`
+	CleanupInterval      time.Duration `long:"cleanup-interval" env:"CLEANUP_INTERVAL" default:"1h"`
+	CleanupAge           time.Duration `long:"cleanup-age" env:"CLEANUP_AGE" default:"48h"`
+	UnwatchedExpiry      time.Duration `long:"unwatched-expiry" env:"UNWATCHED_EXPIRY" default:"0"`
+	AvailabilityInterval time.Duration `long:"availability-interval" env:"AVAILABILITY_INTERVAL" default:"24h"`
+	DiscardUnavailable   bool          `long:"discard-unavailable" env:"DISCARD_UNAVAILABLE"`
+	ChannelRefreshAge    time.Duration `long:"channel-refresh-age" env:"CHANNEL_REFRESH_AGE" default:"24h"`
+	PublicURL            string        `long:"public-url" env:"PUBLIC_URL"`
+	WebSubHubURL         string        `long:"websub-hub-url" env:"WEBSUB_HUB_URL" default:"https://pubsubhubbub.appspot.com/"`
+	WebSubSecret         string        `long:"websub-secret" env:"WEBSUB_SECRET"`
+}
+
+// WebSubEnabled reports whether push notifications should be requested from the WebSub hub, which
+// requires the server to be reachable from the internet
+func (c Config) WebSubEnabled() bool {
+	return c.PublicURL != ""
 }
 
 func getenvOrDefault(key string, defaultValue string) string {
@@ -49,9 +69,28 @@ func Parse() (Config, error) {
 	if config.DownloadsDir == "" {
 		return config, fmt.Errorf("missing DOWNLOADS_DIR env var")
 	}
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+	config.Region = strings.ToUpper(config.Region)
+	if config.WebSubEnabled() && config.WebSubSecret == "" {
+		return config, fmt.Errorf("missing WEBSUB_SECRET env var, required when PUBLIC_URL is set")
+	}
+
+	if config.MaxFetchInterval < config.FetchInterval {
+		return config, fmt.Errorf("MAX_FETCH_INTERVAL must not be smaller than FETCH_INTERVAL")
+	}
+	if config.YouTubeQuotaBudget < 0 {
+		return config, fmt.Errorf("YOUTUBE_QUOTA_BUDGET must not be negative")
+	}
+	if config.FetchWorkers < 1 {
+		return config, fmt.Errorf("FETCH_WORKERS must be at least 1")
+	}
+
 	if err := os.MkdirAll(config.DownloadsDir, 0o755); err != nil {
 		return config, fmt.Errorf("failed to create downloads directory: %w", err)
 	}
+	if err := os.MkdirAll(config.AvatarsDir, 0o755); err != nil {
+		return config, fmt.Errorf("failed to create avatars directory: %w", err)
+	}
 
 	return config, nil
 }
@@ -64,13 +103,20 @@ func TestConfig() Config {
 	}
 
 	config := Config{
-		Port:            8080,
-		DBURI:           dbURI,
-		AuthToken:       "foo",
-		DownloadsDir:    "/tmp/ytrssil-test-downloads",
-		FetchInterval:   5 * time.Minute,
-		CleanupInterval: 1 * time.Hour,
-		CleanupAge:      48 * time.Hour,
+		Port:                 8080,
+		DBURI:                dbURI,
+		AuthToken:            "foo",
+		DownloadsDir:         "/tmp/ytrssil-test-downloads",
+		AvatarsDir:           "/tmp/ytrssil-test-avatars",
+		FetchInterval:        5 * time.Minute,
+		MaxFetchInterval:     12 * time.Hour,
+		FetchWorkers:         4,
+		FetchTimeout:         30 * time.Second,
+		CleanupInterval:      1 * time.Hour,
+		CleanupAge:           48 * time.Hour,
+		AvailabilityInterval: 24 * time.Hour,
+		ChannelRefreshAge:    24 * time.Hour,
+		YouTubeQuotaBudget:   9000,
 	}
 
 	return config
@@ -3,17 +3,29 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 func (db *postgresDB) SubscribeToChannel(ctx context.Context, channel models.Channel) error {
+	// Resubscribing picks up the current name, keeping the previous one as a revision if it changed
 	const query = `
-		INSERT INTO channels (id, name, subscribed, image_url, enable_shorts) VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (id) DO UPDATE SET subscribed = $3, image_url = $4, enable_shorts = $5
+		WITH revision AS (
+			INSERT INTO channel_revisions (channel_id, name, handle)
+			SELECT id, name, handle FROM channels
+			WHERE id = $1 AND $2 <> '' AND name <> $2
+		)
+		INSERT INTO channels (id, name, subscribed, image_url, enable_shorts, source_type, feed_url, site_url)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''))
+		ON CONFLICT (id) DO UPDATE SET
+			name = COALESCE(NULLIF($2, ''), channels.name),
+			subscribed = $3,
+			image_url = COALESCE(NULLIF($4, ''), channels.image_url),
+			enable_shorts = $5
 	`
 	resp, err := db.db.Exec(ctx, query, channel.ID, channel.Name, channel.Subscribed,
-		channel.ImageURL, channel.EnableShorts)
+		channel.ImageURL, channel.EnableShorts, channel.SourceType, channel.FeedURL, channel.SiteURL)
 	if err != nil {
 		db.l.Error("Failed to subscribe to channel", "call", "sql.ExecContext", "error", err)
 		return err
@@ -27,19 +39,91 @@ func (db *postgresDB) SubscribeToChannel(ctx context.Context, channel models.Cha
 	return nil
 }
 
+func (db *postgresDB) AddChannels(ctx context.Context, channels []models.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO channels (id, name, subscribed, image_url, enable_shorts, source_type)
+		SELECT id, name, false, NULLIF(image_url, ''), enable_shorts, source_type
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::boolean[], $5::text[])
+			AS new_channels (id, name, image_url, enable_shorts, source_type)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	var (
+		ids          = make([]string, len(channels))
+		names        = make([]string, len(channels))
+		imageURLs    = make([]string, len(channels))
+		enableShorts = make([]bool, len(channels))
+		sourceTypes  = make([]string, len(channels))
+	)
+	for i, channel := range channels {
+		ids[i] = channel.ID
+		names[i] = channel.Name
+		imageURLs[i] = channel.ImageURL
+		enableShorts[i] = channel.EnableShorts
+		sourceTypes[i] = channel.SourceType
+	}
+
+	_, err := db.db.Exec(ctx, query, ids, names, imageURLs, enableShorts, sourceTypes)
+	if err != nil {
+		db.l.Error("Failed to add channels", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (db *postgresDB) ListChannels(ctx context.Context) ([]models.Channel, error) {
 	const query = `
 		SELECT
 			channels.id,
 			channels.name,
+			COALESCE((
+				SELECT name FROM channel_revisions
+				WHERE channel_id = channels.id AND name <> channels.name
+				ORDER BY revised_at DESC, id DESC LIMIT 1
+			), '') as former_name,
+			COALESCE(channels.handle, '') as handle,
+			COALESCE(channels.description, '') as description,
+			COALESCE(channels.banner_url, '') as banner_url,
 			channels.subscribed,
 			COALESCE(channels.image_url, '') as image_url,
 			COALESCE(channels.enable_shorts, true) as enable_shorts,
-			COUNT(videos.id) FILTER (WHERE videos.watch_timestamp IS NULL AND videos.is_discarded = false) as unwatched_count
+			channels.title_include,
+			channels.title_exclude,
+			COALESCE(channels.feed_etag, '') as feed_etag,
+			COALESCE(channels.feed_last_modified, '') as feed_last_modified,
+			channels.websub_lease_expires_at,
+			channels.source_type,
+			COALESCE(channels.feed_url, '') as feed_url,
+			COALESCE(channels.site_url, '') as site_url,
+			channels.last_fetch_success_at,
+			COALESCE(channels.last_fetch_error, '') as last_fetch_error,
+			channels.last_fetch_error_at,
+			channels.consecutive_failures,
+			channels.last_new_video_at,
+			channels.gone_at,
+			COALESCE(unwatched.count, 0) as unwatched_count
 		FROM channels
-		LEFT JOIN videos ON channels.id = videos.channel_id
+		LEFT JOIN (
+			-- Videos count for the channel that uploaded them and for the playlists they are in
+			SELECT owner_id, COUNT(*) as count
+			FROM (
+				SELECT channel_id as owner_id
+				FROM videos
+				WHERE watch_timestamp IS NULL AND is_discarded = false
+				UNION ALL
+				SELECT playlist_videos.playlist_id
+				FROM playlist_videos
+				JOIN videos ON videos.id = playlist_videos.video_id
+				WHERE videos.watch_timestamp IS NULL AND videos.is_discarded = false
+			) unwatched_videos
+			GROUP BY owner_id
+		) unwatched ON unwatched.owner_id = channels.id
 		WHERE channels.subscribed = true
-		GROUP BY channels.id, channels.name, channels.subscribed, channels.image_url, channels.enable_shorts
 		ORDER BY channels.name
 	`
 	rows, err := db.db.Query(ctx, query)
@@ -52,8 +136,12 @@ func (db *postgresDB) ListChannels(ctx context.Context) ([]models.Channel, error
 	channels := make([]models.Channel, 0)
 	for rows.Next() {
 		var channel models.Channel
-		err = rows.Scan(&channel.ID, &channel.Name, &channel.Subscribed, &channel.ImageURL,
-			&channel.EnableShorts, &channel.UnwatchedCount)
+		err = rows.Scan(&channel.ID, &channel.Name, &channel.FormerName, &channel.Handle, &channel.Description,
+			&channel.BannerURL, &channel.Subscribed, &channel.ImageURL,
+			&channel.EnableShorts, &channel.TitleInclude, &channel.TitleExclude, &channel.FeedETag,
+			&channel.FeedLastModified, &channel.WebSubLeaseExpiresAt, &channel.SourceType, &channel.FeedURL,
+			&channel.SiteURL, &channel.Health.LastSuccessAt, &channel.Health.LastError, &channel.Health.LastErrorAt,
+			&channel.Health.ConsecutiveFailures, &channel.Health.LastNewVideoAt, &channel.Health.GoneAt, &channel.UnwatchedCount)
 		if err != nil {
 			db.l.Error("Failed to scan rows to list channels", "call", "sql.Scan", "error", err)
 			return nil, err
@@ -94,20 +182,74 @@ func (db *postgresDB) ToggleChannelShorts(ctx context.Context, channelID string,
 	return nil
 }
 
+func (db *postgresDB) SetChannelTitleFilters(
+	ctx context.Context, channelID string, include []string, exclude []string,
+) error {
+	const query = `UPDATE channels SET title_include = $1, title_exclude = $2 WHERE id = $3`
+	resp, err := db.db.Exec(ctx, query, include, exclude, channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel title filters", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
 func (db *postgresDB) GetChannelByID(ctx context.Context, channelID string) (*models.Channel, error) {
 	const query = `
 		SELECT
 			id,
 			name,
+			COALESCE((
+				SELECT name FROM channel_revisions
+				WHERE channel_id = channels.id AND name <> channels.name
+				ORDER BY revised_at DESC, id DESC LIMIT 1
+			), '') as former_name,
+			COALESCE(handle, '') as handle,
+			COALESCE(description, '') as description,
+			COALESCE(banner_url, '') as banner_url,
 			subscribed,
 			COALESCE(image_url, '') as image_url,
-			COALESCE(enable_shorts, true) as enable_shorts
+			COALESCE(enable_shorts, true) as enable_shorts,
+			title_include,
+			title_exclude,
+			source_type,
+			COALESCE(feed_url, '') as feed_url,
+			COALESCE(site_url, '') as site_url,
+			last_fetch_success_at,
+			COALESCE(last_fetch_error, '') as last_fetch_error,
+			last_fetch_error_at,
+			consecutive_failures,
+			last_new_video_at,
+			gone_at,
+			websub_requested_at,
+			(
+				SELECT COUNT(*) FROM videos
+				WHERE videos.channel_id = channels.id
+					AND videos.watch_timestamp IS NULL
+					AND videos.is_discarded = false
+			) + (
+				SELECT COUNT(*) FROM playlist_videos
+				JOIN videos ON videos.id = playlist_videos.video_id
+				WHERE playlist_videos.playlist_id = channels.id
+					AND videos.watch_timestamp IS NULL
+					AND videos.is_discarded = false
+			) as unwatched_count
 		FROM channels
 		WHERE id = $1
 	`
 	row := db.db.QueryRow(ctx, query, channelID)
 	var channel models.Channel
-	err := row.Scan(&channel.ID, &channel.Name, &channel.Subscribed, &channel.ImageURL, &channel.EnableShorts)
+	err := row.Scan(&channel.ID, &channel.Name, &channel.FormerName, &channel.Handle, &channel.Description,
+		&channel.BannerURL, &channel.Subscribed, &channel.ImageURL, &channel.EnableShorts,
+		&channel.TitleInclude, &channel.TitleExclude, &channel.SourceType, &channel.FeedURL, &channel.SiteURL,
+		&channel.Health.LastSuccessAt,
+		&channel.Health.LastError, &channel.Health.LastErrorAt, &channel.Health.ConsecutiveFailures,
+		&channel.Health.LastNewVideoAt, &channel.Health.GoneAt, &channel.WebSubRequestedAt, &channel.UnwatchedCount)
 	if err != nil {
 		db.l.Error("Failed to query channel by ID", "call", "sql.QueryRowContext", "error", err)
 		return nil, err
@@ -115,3 +257,387 @@ func (db *postgresDB) GetChannelByID(ctx context.Context, channelID string) (*mo
 
 	return &channel, nil
 }
+
+func (db *postgresDB) SetChannelFeedValidators(
+	ctx context.Context, channelID string, etag string, lastModified string,
+) error {
+	const query = `UPDATE channels SET feed_etag = NULLIF($1, ''), feed_last_modified = NULLIF($2, '') WHERE id = $3`
+	resp, err := db.db.Exec(ctx, query, etag, lastModified, channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel feed validators", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) ListChannelsDueForPoll(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+	return db.listChannelPollStates(ctx, now, true)
+}
+
+func (db *postgresDB) ListChannelPollStates(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+	return db.listChannelPollStates(ctx, now, false)
+}
+
+func (db *postgresDB) listChannelPollStates(
+	ctx context.Context, now time.Time, dueOnly bool,
+) ([]models.ChannelPollState, error) {
+	const query = `
+		SELECT
+			channels.id,
+			channels.name,
+			channels.source_type,
+			COALESCE(channels.feed_url, '') as feed_url,
+			COALESCE(channels.enable_shorts, true) as enable_shorts,
+			channels.title_include,
+			channels.title_exclude,
+			COALESCE(channels.feed_etag, '') as feed_etag,
+			COALESCE(channels.feed_last_modified, '') as feed_last_modified,
+			channels.consecutive_failures,
+			channels.gone_at,
+			COALESCE(channels.min_poll_interval, 0) as min_poll_interval,
+			COALESCE(channels.max_poll_interval, 0) as max_poll_interval,
+			COALESCE(channels.websub_lease_expires_at > $1, false) as has_websub_lease,
+			stats.last_published,
+			COALESCE(stats.average_upload_interval, 0) as average_upload_interval
+		FROM channels
+		LEFT JOIN LATERAL (
+			SELECT
+				MAX(published_timestamp) as last_published,
+				EXTRACT(EPOCH FROM MAX(published_timestamp) - MIN(published_timestamp))
+					/ NULLIF(COUNT(1) - 1, 0) as average_upload_interval
+			FROM (
+				(
+					SELECT published_timestamp
+					FROM videos
+					WHERE videos.channel_id = channels.id
+					ORDER BY published_timestamp DESC
+					LIMIT 10
+				)
+				UNION ALL
+				(
+					SELECT videos.published_timestamp
+					FROM playlist_videos
+					JOIN videos ON videos.id = playlist_videos.video_id
+					WHERE playlist_videos.playlist_id = channels.id
+					ORDER BY videos.published_timestamp DESC
+					LIMIT 10
+				)
+				ORDER BY published_timestamp DESC
+				LIMIT 10
+			) recent
+		) stats ON true
+		WHERE channels.subscribed = true
+			AND (NOT $2 OR channels.next_poll_at IS NULL OR channels.next_poll_at <= $1)
+		ORDER BY channels.next_poll_at NULLS FIRST
+	`
+	rows, err := db.db.Query(ctx, query, now, dueOnly)
+	if err != nil {
+		db.l.Error("Failed to list channel poll states", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make([]models.ChannelPollState, 0)
+	for rows.Next() {
+		var (
+			state                 models.ChannelPollState
+			minInterval           int
+			maxInterval           int
+			averageUploadInterval float64
+		)
+		err = rows.Scan(
+			&state.ID,
+			&state.Name,
+			&state.SourceType,
+			&state.FeedURL,
+			&state.EnableShorts,
+			&state.TitleInclude,
+			&state.TitleExclude,
+			&state.FeedETag,
+			&state.FeedLastModified,
+			&state.ConsecutiveFailures,
+			&state.Health.GoneAt,
+			&minInterval,
+			&maxInterval,
+			&state.HasWebSubLease,
+			&state.LastPublished,
+			&averageUploadInterval,
+		)
+		if err != nil {
+			db.l.Error("Failed to scan rows to list channel poll states", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		state.Subscribed = true
+		state.MinPollInterval = time.Duration(minInterval) * time.Second
+		state.MaxPollInterval = time.Duration(maxInterval) * time.Second
+		state.AverageUploadInterval = time.Duration(averageUploadInterval * float64(time.Second))
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (db *postgresDB) SetChannelPollResult(ctx context.Context, channelID string, nextPollAt time.Time) error {
+	const query = `UPDATE channels SET next_poll_at = $1, last_polled_at = NOW() WHERE id = $2`
+	resp, err := db.db.Exec(ctx, query, nextPollAt, channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel poll result", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) RecordChannelFetch(
+	ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int,
+) error {
+	const query = `
+		UPDATE channels
+		SET
+			last_fetch_success_at = CASE WHEN $1 = '' THEN NOW() ELSE last_fetch_success_at END,
+			last_fetch_error = CASE WHEN $1 = '' THEN last_fetch_error ELSE $1 END,
+			last_fetch_error_at = CASE WHEN $1 = '' THEN last_fetch_error_at ELSE NOW() END,
+			consecutive_failures = CASE WHEN $1 = '' THEN 0 ELSE consecutive_failures + 1 END,
+			consecutive_not_found = CASE WHEN $2 THEN consecutive_not_found + 1 ELSE 0 END,
+			gone_at = CASE
+				WHEN $2 AND consecutive_not_found + 1 >= $4 THEN COALESCE(gone_at, NOW())
+				WHEN $1 = '' THEN NULL
+				ELSE gone_at
+			END,
+			last_new_video_at = CASE WHEN $3 > 0 THEN NOW() ELSE last_new_video_at END
+		WHERE id = $5
+	`
+	resp, err := db.db.Exec(ctx, query, outcome.Error, outcome.NotFound, outcome.NewVideos, goneAfter, channelID)
+	if err != nil {
+		db.l.Error("Failed to record channel fetch", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) SetChannelPollIntervals(
+	ctx context.Context, channelID string, minInterval time.Duration, maxInterval time.Duration,
+) error {
+	const query = `
+		UPDATE channels
+		SET
+			min_poll_interval = NULLIF($1, 0),
+			max_poll_interval = NULLIF($2, 0),
+			next_poll_at = NULL
+		WHERE id = $3
+	`
+	resp, err := db.db.Exec(ctx, query, int(minInterval.Seconds()), int(maxInterval.Seconds()), channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel poll intervals", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) ListChannelsForWebSubRenewal(
+	ctx context.Context, expiringBefore time.Time, requestedBefore time.Time,
+) ([]models.Channel, error) {
+	const query = `
+		SELECT id, name
+		FROM channels
+		WHERE subscribed = true
+			AND source_type = 'youtube'
+			AND (websub_lease_expires_at IS NULL OR websub_lease_expires_at < $1)
+			AND (websub_requested_at IS NULL OR websub_requested_at < $2)
+		ORDER BY websub_lease_expires_at NULLS FIRST
+	`
+	rows, err := db.db.Query(ctx, query, expiringBefore, requestedBefore)
+	if err != nil {
+		db.l.Error("Failed to list channels for WebSub renewal", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]models.Channel, 0)
+	for rows.Next() {
+		var channel models.Channel
+		err = rows.Scan(&channel.ID, &channel.Name)
+		if err != nil {
+			db.l.Error("Failed to scan rows to list channels for WebSub renewal", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+func (db *postgresDB) SetChannelWebSubRequested(ctx context.Context, channelID string) error {
+	const query = `UPDATE channels SET websub_requested_at = NOW() WHERE id = $1`
+	resp, err := db.db.Exec(ctx, query, channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel WebSub request time", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) SetChannelWebSubLease(ctx context.Context, channelID string, expiresAt *time.Time) error {
+	const query = `UPDATE channels SET websub_lease_expires_at = $1, websub_requested_at = NULL WHERE id = $2`
+	resp, err := db.db.Exec(ctx, query, expiresAt, channelID)
+	if err != nil {
+		db.l.Error("Failed to set channel WebSub lease", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	if resp.RowsAffected() != 1 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+func (db *postgresDB) SetChannelsMetadataRefreshed(ctx context.Context, channelIDs []string) error {
+	const query = `UPDATE channels SET metadata_refreshed_at = NOW() WHERE id = ANY($1)`
+	_, err := db.db.Exec(ctx, query, channelIDs)
+	if err != nil {
+		db.l.Error("Failed to set channel metadata refresh time", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *postgresDB) ListChannelsForMetadataRefresh(
+	ctx context.Context, refreshedBefore time.Time,
+) ([]models.Channel, error) {
+	const query = `
+		SELECT id, name, COALESCE(handle, ''), COALESCE(image_url, '')
+		FROM channels
+		WHERE subscribed = true
+			AND source_type = 'youtube'
+			AND (metadata_refreshed_at IS NULL OR metadata_refreshed_at < $1)
+		ORDER BY metadata_refreshed_at NULLS FIRST
+	`
+	rows, err := db.db.Query(ctx, query, refreshedBefore)
+	if err != nil {
+		db.l.Error("Failed to list channels for metadata refresh", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]models.Channel, 0)
+	for rows.Next() {
+		var channel models.Channel
+		err = rows.Scan(&channel.ID, &channel.Name, &channel.Handle, &channel.ImageURL)
+		if err != nil {
+			db.l.Error("Failed to scan rows to list channels for metadata refresh", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+func (db *postgresDB) UpdateChannelMetadata(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	// The previous name and handle are kept as a revision whenever the channel was rebranded. Empty values
+	// leave the stored ones alone, and the first handle seen for a channel is stored without counting as a
+	// rebrand.
+	const query = `
+		WITH incoming AS (
+			SELECT * FROM unnest(
+				$1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[]
+			) AS incoming (id, name, handle, description, image_url, banner_url)
+		), previous AS (
+			SELECT channels.id, channels.name, channels.handle
+			FROM channels
+			JOIN incoming ON incoming.id = channels.id
+			FOR UPDATE OF channels
+		), revision AS (
+			INSERT INTO channel_revisions (channel_id, name, handle)
+			SELECT previous.id, previous.name, previous.handle
+			FROM previous
+			JOIN incoming ON incoming.id = previous.id
+			WHERE (incoming.name <> '' AND previous.name <> incoming.name)
+				OR (incoming.handle <> '' AND previous.handle IS NOT NULL AND previous.handle <> incoming.handle)
+			RETURNING channel_id
+		)
+		UPDATE channels SET
+			name = COALESCE(NULLIF(incoming.name, ''), channels.name),
+			handle = COALESCE(NULLIF(incoming.handle, ''), channels.handle),
+			description = COALESCE(NULLIF(incoming.description, ''), channels.description),
+			image_url = COALESCE(NULLIF(incoming.image_url, ''), channels.image_url),
+			banner_url = COALESCE(NULLIF(incoming.banner_url, ''), channels.banner_url),
+			metadata_refreshed_at = NOW()
+		FROM incoming
+		WHERE channels.id = incoming.id
+		RETURNING channels.id, channels.id IN (SELECT channel_id FROM revision)
+	`
+
+	var (
+		ids          = make([]string, len(metadata))
+		names        = make([]string, len(metadata))
+		handles      = make([]string, len(metadata))
+		descriptions = make([]string, len(metadata))
+		imageURLs    = make([]string, len(metadata))
+		bannerURLs   = make([]string, len(metadata))
+	)
+	for i, channel := range metadata {
+		ids[i] = channel.ChannelID
+		names[i] = channel.Name
+		handles[i] = channel.Handle
+		descriptions[i] = channel.Description
+		imageURLs[i] = channel.AvatarURL
+		bannerURLs[i] = channel.BannerURL
+	}
+
+	rows, err := db.db.Query(ctx, query, ids, names, handles, descriptions, imageURLs, bannerURLs)
+	if err != nil {
+		db.l.Error("Failed to update channel metadata", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	renamed := make([]string, 0)
+	for rows.Next() {
+		var id string
+		var isRenamed bool
+		if err := rows.Scan(&id, &isRenamed); err != nil {
+			db.l.Error("Failed to scan updated channel", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		if isRenamed {
+			renamed = append(renamed, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		db.l.Error("Failed to update channel metadata", "call", "sql.Rows", "error", err)
+		return nil, err
+	}
+
+	return renamed, nil
+}
@@ -21,25 +21,91 @@ type DB interface {
 	ListChannels(ctx context.Context) ([]models.Channel, error)
 	// SubscribeToChannel will start fetching new videos from that channel
 	SubscribeToChannel(ctx context.Context, channel models.Channel) error
+	// AddChannels stores channels without subscribing to them in a single statement, leaving existing channels
+	// untouched
+	AddChannels(ctx context.Context, channels []models.Channel) error
 	// UnsubscribeToChannel will stop fetching videos from that channel
 	UnsubscribeFromChannel(ctx context.Context, channelID string) error
 	// ToggleChannelShorts enables or disables shorts for a channel
 	ToggleChannelShorts(ctx context.Context, channelID string, enableShorts bool) error
+	// SetChannelTitleFilters sets the title include and exclude patterns of a channel
+	SetChannelTitleFilters(ctx context.Context, channelID string, include []string, exclude []string) error
 	// GetChannelByID returns a channel by its ID
 	GetChannelByID(ctx context.Context, channelID string) (*models.Channel, error)
+	// SetChannelFeedValidators stores the HTTP cache validators of the last processed channel feed
+	SetChannelFeedValidators(ctx context.Context, channelID string, etag string, lastModified string) error
+	// ListChannelsDueForPoll returns the subscribed channels whose next scheduled poll is at or before now
+	ListChannelsDueForPoll(ctx context.Context, now time.Time) ([]models.ChannelPollState, error)
+	// ListChannelPollStates returns the poll state of every subscribed channel, whether it's due or not
+	ListChannelPollStates(ctx context.Context, now time.Time) ([]models.ChannelPollState, error)
+	// SetChannelPollResult records that the channel was polled and schedules the next poll
+	SetChannelPollResult(ctx context.Context, channelID string, nextPollAt time.Time) error
+	// RecordChannelFetch updates the fetch health of the channel with the outcome of a fetch. The channel is
+	// flagged as gone once its feed returned 404 goneAfter times in a row, until the next successful fetch.
+	RecordChannelFetch(ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int) error
+	// SetChannelPollIntervals sets the per-channel poll interval overrides, zero values clear them
+	SetChannelPollIntervals(ctx context.Context, channelID string, minInterval, maxInterval time.Duration) error
+	// ListChannelsForWebSubRenewal returns subscribed channels whose WebSub lease is missing or expires before
+	// expiringBefore, and for which no subscription was requested since requestedBefore
+	ListChannelsForWebSubRenewal(
+		ctx context.Context, expiringBefore time.Time, requestedBefore time.Time,
+	) ([]models.Channel, error)
+	// SetChannelWebSubRequested records that a WebSub subscription was just requested for the channel
+	SetChannelWebSubRequested(ctx context.Context, channelID string) error
+	// SetChannelWebSubLease sets or clears the expiry of the channel's verified WebSub lease and clears the
+	// pending request
+	SetChannelWebSubLease(ctx context.Context, channelID string, expiresAt *time.Time) error
+	// ListChannelsForMetadataRefresh returns the subscribed YouTube channels whose metadata wasn't refreshed
+	// since refreshedBefore, the ones never refreshed first
+	ListChannelsForMetadataRefresh(ctx context.Context, refreshedBefore time.Time) ([]models.Channel, error)
+	// UpdateChannelMetadata updates the name, handle, description, image and banner of stored channels in a
+	// single statement, keeping the previous name and handle as a revision if they changed. Empty values leave
+	// the stored ones alone. It returns the IDs of the channels that were renamed.
+	UpdateChannelMetadata(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error)
+	// SetChannelsMetadataRefreshed marks the metadata of the channels as refreshed without changing it, for
+	// channels the API returned nothing for
+	SetChannelsMetadataRefreshed(ctx context.Context, channelIDs []string) error
 
-	// GetNewVideos returns a list of unwatched videos from all subscribed channels
-	GetNewVideos(ctx context.Context, sortDesc bool) ([]models.Video, error)
+	// GetNewVideos returns a list of unwatched videos from all subscribed channels. sortDesc orders by newest
+	// first, which also breaks ties between equally viewed videos when sorting by views.
+	GetNewVideos(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error)
+	// GetUnwatchedChannelVideos returns the unwatched videos of a channel that are, or aren't, discarded
+	GetUnwatchedChannelVideos(ctx context.Context, channelID string, discarded bool) ([]models.Video, error)
 	// GetWatchedVideos returns a list of all watched videos
 	GetWatchedVideos(ctx context.Context, sortDesc bool, limit int, offset int) ([]models.Video, error)
 	// HasVideo returns true if the video with the given ID exists in the DB
 	HasVideo(ctx context.Context, videoID string) (bool, error)
-	// AddVideo adds a newly published video to the database
-	AddVideo(ctx context.Context, video models.Video, channelID string, isDiscarded bool) error
-	// DiscardVideo marks a video as discarded (e.g., shorts filtered out)
-	DiscardVideo(ctx context.Context, videoID string) error
+	// GetKnownVideoIDs returns which of the given video IDs exist in the DB
+	GetKnownVideoIDs(ctx context.Context, videoIDs []string) (map[string]bool, error)
+	// GetLastPublishedBefore returns the publish time of the channel's newest video published before the given
+	// time, or nil if there is none
+	GetLastPublishedBefore(ctx context.Context, channelID string, before time.Time) (*time.Time, error)
+	// AddVideo adds a newly published video to the database, discarded if a discard reason is given
+	AddVideo(ctx context.Context, video models.Video, channelID string, discardReason models.DiscardReason) error
+	// AddVideos adds newly published videos in a single statement, each to its ChannelID and discarded if it
+	// has a DiscardReason. Videos that already exist are skipped. It returns the IDs of the added videos.
+	AddVideos(ctx context.Context, videos []models.Video) ([]string, error)
+	// UpdateKnownVideos updates the title, thumbnail, description, view count and rating of stored videos from
+	// their feed in a single statement, keeping the previous title and thumbnail as a revision if they changed.
+	// Empty values leave the stored ones alone. It returns the IDs of the videos that were revised.
+	UpdateKnownVideos(ctx context.Context, videos []models.Video) ([]string, error)
+	// AddPlaylistVideos records that videos are part of a subscribed playlist in a single statement
+	AddPlaylistVideos(ctx context.Context, playlistID string, videoIDs []string) error
+	// DiscardVideo takes a video out of the feed for the given reason
+	DiscardVideo(ctx context.Context, videoID string, reason models.DiscardReason) error
+	// ExpireUnwatchedVideos discards the unwatched videos published before the given time and returns how
+	// many there were
+	ExpireUnwatchedVideos(ctx context.Context, publishedBefore time.Time) (int64, error)
+	// GetDiscardedVideos returns a page of unwatched discarded videos, newest first
+	GetDiscardedVideos(ctx context.Context, limit int, offset int) ([]models.Video, error)
+	// SetVideoShortOverride classifies a video as a short, or not, by hand
+	SetVideoShortOverride(ctx context.Context, videoID string, isShort bool) error
+	// RestoreVideo brings a discarded video back into the feed
+	RestoreVideo(ctx context.Context, videoID string) error
 	// SetVideoWatchTime sets or unsets the watch timestamp of a video
 	SetVideoWatchTime(ctx context.Context, videoID string, watchTime *time.Time) error
+	// SetVideoWatchTimes sets the watch timestamps of videos by their IDs in a single statement
+	SetVideoWatchTimes(ctx context.Context, watchTimes map[string]time.Time) error
 	// SetVideoProgress sets or unsets the watch progress of a video
 	SetVideoProgress(ctx context.Context, videoID string, progress int) (*models.Video, error)
 	// GetVideo returns a single video by ID
@@ -52,12 +118,26 @@ type DB interface {
 	SetVideoDownloadFailed(ctx context.Context, videoID string, errorMsg string) error
 	// GetVideosForCleanup returns videos that were downloaded and watched older than the given duration
 	GetVideosForCleanup(ctx context.Context, olderThan time.Duration) ([]models.Video, error)
-	// GetLiveVideos returns unwatched videos that are currently live
+	// GetVideosForAvailabilityCheck returns the unwatched YouTube videos that are still in the feed
+	GetVideosForAvailabilityCheck(ctx context.Context) ([]models.Video, error)
+	// SetVideoAvailability marks a video as unavailable for the given reason, or as available again
+	SetVideoAvailability(ctx context.Context, videoID string, reason models.UnavailableReason) error
+	// GetLiveVideos returns unwatched videos that are currently live or upcoming, along with their stored state
 	GetLiveVideos(ctx context.Context) ([]models.Video, error)
-	// UpdateVideoLiveStatus updates the live status and duration of a video
-	UpdateVideoLiveStatus(ctx context.Context, videoID string, isLive bool, duration int) error
+	// UpdateVideoLiveStatus updates the live and upcoming status, scheduled start time and duration of a video
+	UpdateVideoLiveStatus(ctx context.Context, video models.Video) error
 	// DeleteVideoFile clears the download fields for a video
 	DeleteVideoFile(ctx context.Context, videoID string) error
+	// GetVideosPendingDuration returns the videos that were added without looking up their duration, newest first
+	GetVideosPendingDuration(ctx context.Context) ([]models.Video, error)
+	// SetVideoDuration stores the looked up duration, short classification, live status and availability of a
+	// video and takes it off the pending list
+	SetVideoDuration(ctx context.Context, video models.Video) error
+
+	// GetQuotaUsage returns the YouTube API quota spent per method on the given day (YYYY-MM-DD)
+	GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error)
+	// AddQuotaUsage records a request to a YouTube API method spending the given units on the given day
+	AddQuotaUsage(ctx context.Context, day string, call string, units int) error
 
 	// Close closes the DB connection
 	Close()
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func (db *postgresDB) GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error) {
+	const query = `SELECT call, requests, units FROM quota_usage WHERE day = $1::date`
+	rows, err := db.db.Query(ctx, query, day)
+	if err != nil {
+		db.l.Error("Failed to get quota usage", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]models.QuotaCallUsage, 0)
+	for rows.Next() {
+		var call models.QuotaCallUsage
+		if err := rows.Scan(&call.Call, &call.Requests, &call.Units); err != nil {
+			db.l.Error("Failed to scan quota usage", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		usage = append(usage, call)
+	}
+
+	return usage, nil
+}
+
+func (db *postgresDB) AddQuotaUsage(ctx context.Context, day string, call string, units int) error {
+	const query = `
+		INSERT INTO quota_usage (day, call, requests, units)
+		VALUES ($1::date, $2, 1, $3)
+		ON CONFLICT (day, call) DO UPDATE SET
+			requests = quota_usage.requests + 1,
+			units = quota_usage.units + EXCLUDED.units
+	`
+	_, err := db.db.Exec(ctx, query, day, call, units)
+	if err != nil {
+		db.l.Error("Failed to add quota usage", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -7,14 +7,19 @@ import (
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
-func (db *postgresDB) GetNewVideos(ctx context.Context, sortDesc bool) ([]models.Video, error) {
+func (db *postgresDB) GetNewVideos(
+	ctx context.Context, sortBy models.VideoSort, sortDesc bool,
+) ([]models.Video, error) {
 	query := `
 		SELECT
 			videos.id
 			, title
 			, published_timestamp
 			, is_short
+			, COALESCE(short_signal, '')
 			, is_live
+			, is_upcoming
+			, scheduled_start_time
 			, duration
 			, progress
 			, is_discarded
@@ -24,14 +29,31 @@ func (db *postgresDB) GetNewVideos(ctx context.Context, sortDesc bool) ([]models
 			, download_error
 			, channels.name
 			, channels.id
+			, COALESCE(channels.site_url, channels.feed_url, '')
+			, COALESCE(url, '')
+			, COALESCE(thumbnail_url, '')
+			, COALESCE(videos.description, '')
+			, COALESCE(view_count, 0)
+			, COALESCE(rating_average, 0)
+			, COALESCE(rating_count, 0)
+			, COALESCE(unavailable_reason, '')
+			, COALESCE(NULLIF((
+				SELECT title FROM video_revisions WHERE video_id = videos.id ORDER BY revised_at, id LIMIT 1
+			), videos.title), '')
 		FROM videos
 		LEFT JOIN channels ON videos.channel_id=channels.id
 		WHERE watch_timestamp IS NULL
 			AND videos.is_discarded = false
-		ORDER BY published_timestamp
 	`
+	direction := ""
 	if sortDesc {
-		query += " DESC"
+		direction = " DESC"
+	}
+	switch sortBy {
+	case models.SortViews:
+		query += " ORDER BY view_count DESC NULLS LAST, published_timestamp" + direction
+	default:
+		query += " ORDER BY published_timestamp" + direction
 	}
 
 	rows, err := db.db.Query(ctx, query)
@@ -49,7 +71,10 @@ func (db *postgresDB) GetNewVideos(ctx context.Context, sortDesc bool) ([]models
 			&video.Title,
 			&video.PublishedTime,
 			&video.IsShort,
+			&video.ShortSignal,
 			&video.IsLive,
+			&video.IsUpcoming,
+			&video.ScheduledStartTime,
 			&video.DurationSeconds,
 			&video.ProgressSeconds,
 			&video.IsDiscarded,
@@ -59,6 +84,15 @@ func (db *postgresDB) GetNewVideos(ctx context.Context, sortDesc bool) ([]models
 			&video.DownloadError,
 			&video.ChannelName,
 			&video.ChannelID,
+			&video.ChannelSiteURL,
+			&video.URL,
+			&video.Thumbnail,
+			&video.Description,
+			&video.Views,
+			&video.RatingAverage,
+			&video.RatingCount,
+			&video.UnavailableReason,
+			&video.OriginalTitle,
 		)
 		if err != nil {
 			db.l.Error("Failed to scan rows for get new videos", "call", "sql.Scan", "error", err)
@@ -70,6 +104,149 @@ func (db *postgresDB) GetNewVideos(ctx context.Context, sortDesc bool) ([]models
 	return videos, nil
 }
 
+func (db *postgresDB) GetUnwatchedChannelVideos(
+	ctx context.Context, channelID string, discarded bool,
+) ([]models.Video, error) {
+	const query = `
+		SELECT
+			videos.id
+			, title
+			, published_timestamp
+			, is_short
+			, COALESCE(discard_reason, '')
+		FROM videos
+		WHERE videos.id IN (
+				SELECT id FROM videos WHERE channel_id = $1
+				UNION ALL
+				SELECT video_id FROM playlist_videos WHERE playlist_id = $1
+			)
+			AND watch_timestamp IS NULL
+			AND videos.is_discarded = $2
+		ORDER BY published_timestamp DESC
+	`
+	rows, err := db.db.Query(ctx, query, channelID, discarded)
+	if err != nil {
+		db.l.Error("Failed to query unwatched channel videos", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]models.Video, 0)
+	for rows.Next() {
+		var video models.Video
+		err = rows.Scan(&video.ID, &video.Title, &video.PublishedTime, &video.IsShort, &video.DiscardReason)
+		if err != nil {
+			db.l.Error("Failed to scan rows for unwatched channel videos", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+func (db *postgresDB) GetDiscardedVideos(ctx context.Context, limit int, offset int) ([]models.Video, error) {
+	const query = `
+		SELECT
+			videos.id
+			, title
+			, published_timestamp
+			, is_short
+			, is_live
+			, is_upcoming
+			, scheduled_start_time
+			, duration
+			, progress
+			, is_discarded
+			, COALESCE(discard_reason, '')
+			, downloaded_at
+			, file_path
+			, download_status
+			, download_error
+			, channels.name
+			, channels.id
+			, COALESCE(channels.site_url, channels.feed_url, '')
+			, COALESCE(url, '')
+			, COALESCE(thumbnail_url, '')
+			, COALESCE(videos.description, '')
+			, COALESCE(view_count, 0)
+			, COALESCE(rating_average, 0)
+			, COALESCE(rating_count, 0)
+			, COALESCE(unavailable_reason, '')
+			, COALESCE(NULLIF((
+				SELECT title FROM video_revisions WHERE video_id = videos.id ORDER BY revised_at, id LIMIT 1
+			), videos.title), '')
+		FROM videos
+		LEFT JOIN channels ON videos.channel_id=channels.id
+		WHERE watch_timestamp IS NULL
+			AND videos.is_discarded = true
+		ORDER BY published_timestamp DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := db.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		db.l.Error("Failed to query discarded videos", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]models.Video, 0)
+	for rows.Next() {
+		var video models.Video
+		err = rows.Scan(
+			&video.ID,
+			&video.Title,
+			&video.PublishedTime,
+			&video.IsShort,
+			&video.IsLive,
+			&video.IsUpcoming,
+			&video.ScheduledStartTime,
+			&video.DurationSeconds,
+			&video.ProgressSeconds,
+			&video.IsDiscarded,
+			&video.DiscardReason,
+			&video.DownloadedAt,
+			&video.FilePath,
+			&video.DownloadStatus,
+			&video.DownloadError,
+			&video.ChannelName,
+			&video.ChannelID,
+			&video.ChannelSiteURL,
+			&video.URL,
+			&video.Thumbnail,
+			&video.Description,
+			&video.Views,
+			&video.RatingAverage,
+			&video.RatingCount,
+			&video.UnavailableReason,
+			&video.OriginalTitle,
+		)
+		if err != nil {
+			db.l.Error("Failed to scan rows for discarded videos", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+func (db *postgresDB) ExpireUnwatchedVideos(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	const query = `
+		UPDATE videos SET is_discarded = true, discard_reason = $2
+		WHERE watch_timestamp IS NULL
+			AND is_discarded = false
+			AND published_timestamp < $1
+	`
+	resp, err := db.db.Exec(ctx, query, publishedBefore, models.DiscardExpired)
+	if err != nil {
+		db.l.Error("Failed to expire unwatched videos", "call", "sql.Exec", "error", err)
+		return 0, err
+	}
+
+	return resp.RowsAffected(), nil
+}
+
 func (db *postgresDB) GetWatchedVideos(
 	ctx context.Context, sortDesc bool, limit int, offset int,
 ) ([]models.Video, error) {
@@ -81,6 +258,8 @@ func (db *postgresDB) GetWatchedVideos(
 			, watch_timestamp
 			, is_short
 			, is_live
+			, is_upcoming
+			, scheduled_start_time
 			, duration
 			, progress
 			, is_discarded
@@ -90,6 +269,17 @@ func (db *postgresDB) GetWatchedVideos(
 			, download_error
 			, channels.name
 			, channels.id
+			, COALESCE(channels.site_url, channels.feed_url, '')
+			, COALESCE(url, '')
+			, COALESCE(thumbnail_url, '')
+			, COALESCE(videos.description, '')
+			, COALESCE(view_count, 0)
+			, COALESCE(rating_average, 0)
+			, COALESCE(rating_count, 0)
+			, COALESCE(unavailable_reason, '')
+			, COALESCE(NULLIF((
+				SELECT title FROM video_revisions WHERE video_id = videos.id ORDER BY revised_at, id LIMIT 1
+			), videos.title), '')
 		FROM videos
 		LEFT JOIN channels ON channels.id=videos.channel_id
 		WHERE watch_timestamp IS NOT NULL
@@ -118,6 +308,8 @@ func (db *postgresDB) GetWatchedVideos(
 			&video.WatchTime,
 			&video.IsShort,
 			&video.IsLive,
+			&video.IsUpcoming,
+			&video.ScheduledStartTime,
 			&video.DurationSeconds,
 			&video.ProgressSeconds,
 			&video.IsDiscarded,
@@ -127,6 +319,15 @@ func (db *postgresDB) GetWatchedVideos(
 			&video.DownloadError,
 			&video.ChannelName,
 			&video.ChannelID,
+			&video.ChannelSiteURL,
+			&video.URL,
+			&video.Thumbnail,
+			&video.Description,
+			&video.Views,
+			&video.RatingAverage,
+			&video.RatingCount,
+			&video.UnavailableReason,
+			&video.OriginalTitle,
 		)
 		if err != nil {
 			db.l.Error("Failed to scan rows for watched videos", "call", "sql.Scan", "error", err)
@@ -152,7 +353,50 @@ func (db *postgresDB) HasVideo(ctx context.Context, videoID string) (bool, error
 	return count == 1, nil
 }
 
-func (db *postgresDB) AddVideo(ctx context.Context, video models.Video, channelID string, isDiscarded bool) error {
+func (db *postgresDB) GetKnownVideoIDs(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	if len(videoIDs) == 0 {
+		return known, nil
+	}
+
+	const query = `SELECT id FROM videos WHERE id = ANY($1)`
+	rows, err := db.db.Query(ctx, query, videoIDs)
+	if err != nil {
+		db.l.Error("Failed to query known videos", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			db.l.Error("Failed to scan known video", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		known[id] = true
+	}
+
+	return known, nil
+}
+
+func (db *postgresDB) GetLastPublishedBefore(
+	ctx context.Context, channelID string, before time.Time,
+) (*time.Time, error) {
+	const query = `SELECT MAX(published_timestamp) FROM videos WHERE channel_id = $1 AND published_timestamp < $2`
+	row := db.db.QueryRow(ctx, query, channelID, before)
+	var lastPublished *time.Time
+	err := row.Scan(&lastPublished)
+	if err != nil {
+		db.l.Error("Failed to get last published video", "call", "sql.QueryRowContext", "error", err)
+		return nil, err
+	}
+
+	return lastPublished, nil
+}
+
+func (db *postgresDB) AddVideo(
+	ctx context.Context, video models.Video, channelID string, discardReason models.DiscardReason,
+) error {
 	query := `
 		INSERT INTO videos (
 			id
@@ -163,7 +407,21 @@ func (db *postgresDB) AddVideo(ctx context.Context, video models.Video, channelI
 			, is_live
 			, channel_id
 			, is_discarded
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			, discard_reason
+			, url
+			, thumbnail_url
+			, short_signal
+			, is_upcoming
+			, scheduled_start_time
+			, description
+			, view_count
+			, rating_average
+			, rating_count
+			, duration_pending
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), $13, $14,
+			NULLIF($15, ''), NULLIF($16, 0), NULLIF($17, 0), NULLIF($18, 0), $19
+		)
 		ON CONFLICT DO NOTHING
 	`
 
@@ -177,7 +435,18 @@ func (db *postgresDB) AddVideo(ctx context.Context, video models.Video, channelI
 		video.IsShort,
 		video.IsLive,
 		channelID,
-		isDiscarded,
+		discardReason != models.NotDiscarded,
+		discardReason,
+		video.URL,
+		video.Thumbnail,
+		video.ShortSignal,
+		video.IsUpcoming,
+		video.ScheduledStartTime,
+		video.Description,
+		video.Views,
+		video.RatingAverage,
+		video.RatingCount,
+		video.DurationPending,
 	)
 	if err != nil {
 		db.l.Error("Failed to add video", "call", "sql.Exec", "error", err)
@@ -190,9 +459,255 @@ func (db *postgresDB) AddVideo(ctx context.Context, video models.Video, channelI
 	return nil
 }
 
-func (db *postgresDB) DiscardVideo(ctx context.Context, videoID string) error {
-	query := `UPDATE videos SET is_discarded = true WHERE id = $1`
-	_, err := db.db.Exec(ctx, query, videoID)
+func (db *postgresDB) AddVideos(ctx context.Context, videos []models.Video) ([]string, error) {
+	if len(videos) == 0 {
+		return nil, nil
+	}
+
+	// A single multi-row insert, so all of a channel's new videos are stored in one round trip
+	const query = `
+		INSERT INTO videos (
+			id
+			, title
+			, published_timestamp
+			, duration
+			, is_short
+			, is_live
+			, channel_id
+			, is_discarded
+			, discard_reason
+			, url
+			, thumbnail_url
+			, short_signal
+			, is_upcoming
+			, scheduled_start_time
+			, description
+			, view_count
+			, rating_average
+			, rating_count
+			, duration_pending
+		)
+		SELECT
+			id
+			, title
+			, published_timestamp
+			, duration
+			, is_short
+			, is_live
+			, channel_id
+			, discard_reason <> ''
+			, NULLIF(discard_reason, '')
+			, NULLIF(url, '')
+			, NULLIF(thumbnail_url, '')
+			, NULLIF(short_signal, '')
+			, is_upcoming
+			, scheduled_start_time
+			, NULLIF(description, '')
+			, NULLIF(view_count, 0)
+			, NULLIF(rating_average, 0)
+			, NULLIF(rating_count, 0)
+			, duration_pending
+		FROM unnest(
+			$1::text[], $2::text[], $3::timestamptz[], $4::integer[], $5::boolean[], $6::boolean[], $7::text[],
+			$8::text[], $9::text[], $10::text[], $11::text[], $12::boolean[], $13::timestamptz[], $14::text[],
+			$15::bigint[], $16::double precision[], $17::bigint[], $18::boolean[]
+		) AS new_videos (
+			id, title, published_timestamp, duration, is_short, is_live, channel_id, discard_reason, url,
+			thumbnail_url, short_signal, is_upcoming, scheduled_start_time, description, view_count,
+			rating_average, rating_count, duration_pending
+		)
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`
+
+	var (
+		ids                 = make([]string, len(videos))
+		titles              = make([]string, len(videos))
+		publishedTimes      = make([]time.Time, len(videos))
+		durations           = make([]int, len(videos))
+		isShorts            = make([]bool, len(videos))
+		isLives             = make([]bool, len(videos))
+		channelIDs          = make([]string, len(videos))
+		discardReasons      = make([]string, len(videos))
+		urls                = make([]string, len(videos))
+		thumbnails          = make([]string, len(videos))
+		shortSignals        = make([]string, len(videos))
+		isUpcomings         = make([]bool, len(videos))
+		scheduledStartTimes = make([]*time.Time, len(videos))
+		descriptions        = make([]string, len(videos))
+		views               = make([]int64, len(videos))
+		ratingAverages      = make([]float64, len(videos))
+		ratingCounts        = make([]int64, len(videos))
+		durationPendings    = make([]bool, len(videos))
+	)
+	for i, video := range videos {
+		ids[i] = video.ID
+		titles[i] = video.Title
+		publishedTimes[i] = video.PublishedTime
+		durations[i] = video.DurationSeconds
+		isShorts[i] = video.IsShort
+		isLives[i] = video.IsLive
+		channelIDs[i] = video.ChannelID
+		discardReasons[i] = string(video.DiscardReason)
+		urls[i] = video.URL
+		thumbnails[i] = video.Thumbnail
+		shortSignals[i] = string(video.ShortSignal)
+		isUpcomings[i] = video.IsUpcoming
+		scheduledStartTimes[i] = video.ScheduledStartTime
+		descriptions[i] = video.Description
+		views[i] = video.Views
+		ratingAverages[i] = video.RatingAverage
+		ratingCounts[i] = video.RatingCount
+		durationPendings[i] = video.DurationPending
+	}
+
+	rows, err := db.db.Query(
+		ctx, query, ids, titles, publishedTimes, durations, isShorts, isLives, channelIDs, discardReasons, urls,
+		thumbnails, shortSignals, isUpcomings, scheduledStartTimes, descriptions, views, ratingAverages, ratingCounts,
+		durationPendings,
+	)
+	if err != nil {
+		db.l.Error("Failed to add videos", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	added := make([]string, 0, len(videos))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			db.l.Error("Failed to scan added video", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		added = append(added, id)
+	}
+	if err := rows.Err(); err != nil {
+		db.l.Error("Failed to add videos", "call", "sql.Rows", "error", err)
+		return nil, err
+	}
+
+	return added, nil
+}
+
+func (db *postgresDB) UpdateKnownVideos(ctx context.Context, videos []models.Video) ([]string, error) {
+	if len(videos) == 0 {
+		return nil, nil
+	}
+
+	// The previous title and thumbnail are kept as a revision whenever the feed shows new ones. Empty values
+	// leave the stored ones alone, and the first thumbnail seen for a video is stored without counting as a
+	// change. Rows the feed has nothing new for aren't touched.
+	const query = `
+		WITH incoming AS (
+			SELECT * FROM unnest(
+				$1::text[], $2::text[], $3::text[], $4::text[], $5::bigint[], $6::double precision[], $7::bigint[]
+			) AS incoming (id, title, thumbnail_url, description, view_count, rating_average, rating_count)
+		), previous AS (
+			SELECT videos.id, videos.title, videos.thumbnail_url
+			FROM videos
+			JOIN incoming ON incoming.id = videos.id
+			FOR UPDATE OF videos
+		), revision AS (
+			INSERT INTO video_revisions (video_id, title, thumbnail_url)
+			SELECT previous.id, previous.title, previous.thumbnail_url
+			FROM previous
+			JOIN incoming ON incoming.id = previous.id
+			WHERE (incoming.title <> '' AND previous.title <> incoming.title)
+				OR (
+					incoming.thumbnail_url <> ''
+					AND previous.thumbnail_url IS NOT NULL
+					AND previous.thumbnail_url <> incoming.thumbnail_url
+				)
+			RETURNING video_id
+		)
+		UPDATE videos SET
+			title = COALESCE(NULLIF(incoming.title, ''), videos.title),
+			thumbnail_url = COALESCE(NULLIF(incoming.thumbnail_url, ''), videos.thumbnail_url),
+			description = COALESCE(NULLIF(incoming.description, ''), videos.description),
+			view_count = COALESCE(NULLIF(incoming.view_count, 0), videos.view_count),
+			rating_average = COALESCE(NULLIF(incoming.rating_average, 0), videos.rating_average),
+			rating_count = COALESCE(NULLIF(incoming.rating_count, 0), videos.rating_count)
+		FROM incoming
+		WHERE videos.id = incoming.id
+			AND (
+				videos.id IN (SELECT video_id FROM revision)
+				OR (incoming.thumbnail_url <> '' AND videos.thumbnail_url IS NULL)
+				OR (incoming.description <> '' AND videos.description IS DISTINCT FROM incoming.description)
+				OR (incoming.view_count <> 0 AND videos.view_count IS DISTINCT FROM incoming.view_count)
+				OR (incoming.rating_average <> 0 AND videos.rating_average IS DISTINCT FROM incoming.rating_average)
+				OR (incoming.rating_count <> 0 AND videos.rating_count IS DISTINCT FROM incoming.rating_count)
+			)
+		RETURNING videos.id, videos.id IN (SELECT video_id FROM revision)
+	`
+
+	var (
+		ids            = make([]string, len(videos))
+		titles         = make([]string, len(videos))
+		thumbnails     = make([]string, len(videos))
+		descriptions   = make([]string, len(videos))
+		views          = make([]int64, len(videos))
+		ratingAverages = make([]float64, len(videos))
+		ratingCounts   = make([]int64, len(videos))
+	)
+	for i, video := range videos {
+		ids[i] = video.ID
+		titles[i] = video.Title
+		thumbnails[i] = video.Thumbnail
+		descriptions[i] = video.Description
+		views[i] = video.Views
+		ratingAverages[i] = video.RatingAverage
+		ratingCounts[i] = video.RatingCount
+	}
+
+	rows, err := db.db.Query(ctx, query, ids, titles, thumbnails, descriptions, views, ratingAverages, ratingCounts)
+	if err != nil {
+		db.l.Error("Failed to update known videos", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revised := make([]string, 0)
+	for rows.Next() {
+		var id string
+		var isRevised bool
+		if err := rows.Scan(&id, &isRevised); err != nil {
+			db.l.Error("Failed to scan updated video", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		if isRevised {
+			revised = append(revised, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		db.l.Error("Failed to update known videos", "call", "sql.Rows", "error", err)
+		return nil, err
+	}
+
+	return revised, nil
+}
+
+func (db *postgresDB) AddPlaylistVideos(ctx context.Context, playlistID string, videoIDs []string) error {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO playlist_videos (playlist_id, video_id)
+		SELECT $1, video_id FROM unnest($2::text[]) AS video_id
+		ON CONFLICT DO NOTHING
+	`
+	_, err := db.db.Exec(ctx, query, playlistID, videoIDs)
+	if err != nil {
+		db.l.Error("Failed to add playlist videos", "call", "sql.Exec", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *postgresDB) DiscardVideo(ctx context.Context, videoID string, reason models.DiscardReason) error {
+	query := `UPDATE videos SET is_discarded = true, discard_reason = $2 WHERE id = $1`
+	_, err := db.db.Exec(ctx, query, videoID, reason)
 	if err != nil {
 		db.l.Error("Failed to discard video", "call", "sql.Exec", "error", err)
 		return err
@@ -200,6 +715,26 @@ func (db *postgresDB) DiscardVideo(ctx context.Context, videoID string) error {
 	return nil
 }
 
+func (db *postgresDB) SetVideoShortOverride(ctx context.Context, videoID string, isShort bool) error {
+	const query = `UPDATE videos SET is_short = $2, short_signal = $3 WHERE id = $1`
+	_, err := db.db.Exec(ctx, query, videoID, isShort, models.ShortSignalOverride)
+	if err != nil {
+		db.l.Error("Failed to override video short classification", "call", "sql.Exec", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (db *postgresDB) RestoreVideo(ctx context.Context, videoID string) error {
+	query := `UPDATE videos SET is_discarded = false, discard_reason = NULL WHERE id = $1`
+	_, err := db.db.Exec(ctx, query, videoID)
+	if err != nil {
+		db.l.Error("Failed to restore video", "call", "sql.Exec", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (db *postgresDB) SetVideoWatchTime(
 	ctx context.Context,
 	videoID string,
@@ -215,6 +750,32 @@ func (db *postgresDB) SetVideoWatchTime(
 	return nil
 }
 
+func (db *postgresDB) SetVideoWatchTimes(ctx context.Context, watchTimes map[string]time.Time) error {
+	if len(watchTimes) == 0 {
+		return nil
+	}
+
+	const query = `
+		UPDATE videos SET watch_timestamp = watched.watch_timestamp
+		FROM unnest($1::text[], $2::timestamptz[]) AS watched (id, watch_timestamp)
+		WHERE videos.id = watched.id
+	`
+	ids := make([]string, 0, len(watchTimes))
+	times := make([]time.Time, 0, len(watchTimes))
+	for id, watchTime := range watchTimes {
+		ids = append(ids, id)
+		times = append(times, watchTime)
+	}
+
+	_, err := db.db.Exec(ctx, query, ids, times)
+	if err != nil {
+		db.l.Error("Failed to set video watch times", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (db *postgresDB) SetVideoProgress(ctx context.Context, videoID string, progress int) (*models.Video, error) {
 	const query = `
 		WITH updated AS (
@@ -225,17 +786,32 @@ func (db *postgresDB) SetVideoProgress(ctx context.Context, videoID string, prog
 			, title
 			, published_timestamp
 			, is_short
+			, COALESCE(short_signal, '')
 			, is_live
+			, is_upcoming
+			, scheduled_start_time
 			, duration
 			, progress
 			, watch_timestamp
 			, is_discarded
+			, COALESCE(discard_reason, '')
 			, downloaded_at
 			, file_path
 			, download_status
 			, download_error
 			, channels.name
 			, channels.id
+			, COALESCE(channels.site_url, channels.feed_url, '')
+			, COALESCE(url, '')
+			, COALESCE(thumbnail_url, '')
+			, COALESCE(updated.description, '')
+			, COALESCE(view_count, 0)
+			, COALESCE(rating_average, 0)
+			, COALESCE(rating_count, 0)
+			, COALESCE(unavailable_reason, '')
+			, COALESCE(NULLIF((
+				SELECT title FROM video_revisions WHERE video_id = updated.id ORDER BY revised_at, id LIMIT 1
+			), updated.title), '')
 		FROM updated
 		LEFT JOIN channels ON updated.channel_id = channels.id
 	`
@@ -247,17 +823,30 @@ func (db *postgresDB) SetVideoProgress(ctx context.Context, videoID string, prog
 		&video.Title,
 		&video.PublishedTime,
 		&video.IsShort,
+		&video.ShortSignal,
 		&video.IsLive,
+		&video.IsUpcoming,
+		&video.ScheduledStartTime,
 		&video.DurationSeconds,
 		&video.ProgressSeconds,
 		&video.WatchTime,
 		&video.IsDiscarded,
+		&video.DiscardReason,
 		&video.DownloadedAt,
 		&video.FilePath,
 		&video.DownloadStatus,
 		&video.DownloadError,
 		&video.ChannelName,
 		&video.ChannelID,
+		&video.ChannelSiteURL,
+		&video.URL,
+		&video.Thumbnail,
+		&video.Description,
+		&video.Views,
+		&video.RatingAverage,
+		&video.RatingCount,
+		&video.UnavailableReason,
+		&video.OriginalTitle,
 	)
 	if err != nil {
 		db.l.Error("Failed to scan rows for get new videos", "call", "sql.Scan", "error", err)
@@ -274,17 +863,32 @@ func (db *postgresDB) GetVideo(ctx context.Context, videoID string) (*models.Vid
 			, title
 			, published_timestamp
 			, is_short
+			, COALESCE(short_signal, '')
 			, is_live
+			, is_upcoming
+			, scheduled_start_time
 			, duration
 			, progress
 			, watch_timestamp
 			, is_discarded
+			, COALESCE(discard_reason, '')
 			, downloaded_at
 			, file_path
 			, download_status
 			, download_error
 			, channels.name
 			, channels.id
+			, COALESCE(channels.site_url, channels.feed_url, '')
+			, COALESCE(url, '')
+			, COALESCE(thumbnail_url, '')
+			, COALESCE(videos.description, '')
+			, COALESCE(view_count, 0)
+			, COALESCE(rating_average, 0)
+			, COALESCE(rating_count, 0)
+			, COALESCE(unavailable_reason, '')
+			, COALESCE(NULLIF((
+				SELECT title FROM video_revisions WHERE video_id = videos.id ORDER BY revised_at, id LIMIT 1
+			), videos.title), '')
 		FROM videos
 		LEFT JOIN channels ON videos.channel_id=channels.id
 		WHERE videos.id = $1
@@ -297,17 +901,30 @@ func (db *postgresDB) GetVideo(ctx context.Context, videoID string) (*models.Vid
 		&video.Title,
 		&video.PublishedTime,
 		&video.IsShort,
+		&video.ShortSignal,
 		&video.IsLive,
+		&video.IsUpcoming,
+		&video.ScheduledStartTime,
 		&video.DurationSeconds,
 		&video.ProgressSeconds,
 		&video.WatchTime,
 		&video.IsDiscarded,
+		&video.DiscardReason,
 		&video.DownloadedAt,
 		&video.FilePath,
 		&video.DownloadStatus,
 		&video.DownloadError,
 		&video.ChannelName,
 		&video.ChannelID,
+		&video.ChannelSiteURL,
+		&video.URL,
+		&video.Thumbnail,
+		&video.Description,
+		&video.Views,
+		&video.RatingAverage,
+		&video.RatingCount,
+		&video.UnavailableReason,
+		&video.OriginalTitle,
 	)
 	if err != nil {
 		db.l.Error("Failed to query video", "call", "sql.QueryRow", "error", err)
@@ -394,8 +1011,66 @@ func (db *postgresDB) GetVideosForCleanup(ctx context.Context, olderThan time.Du
 	return videos, nil
 }
 
+func (db *postgresDB) GetVideosForAvailabilityCheck(ctx context.Context) ([]models.Video, error) {
+	const query = `
+		SELECT
+			videos.id
+			, COALESCE(unavailable_reason, '')
+			, downloaded_at
+			, file_path
+			, download_status
+		FROM videos
+		LEFT JOIN channels ON videos.channel_id=channels.id
+		WHERE watch_timestamp IS NULL
+			AND videos.is_discarded = false
+			AND channels.source_type = $1
+	`
+	rows, err := db.db.Query(ctx, query, models.SourceYouTube)
+	if err != nil {
+		db.l.Error("Failed to query videos for availability check", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]models.Video, 0)
+	for rows.Next() {
+		var video models.Video
+		err = rows.Scan(
+			&video.ID, &video.UnavailableReason, &video.DownloadedAt, &video.FilePath, &video.DownloadStatus,
+		)
+		if err != nil {
+			db.l.Error("Failed to scan rows for availability check", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+func (db *postgresDB) SetVideoAvailability(
+	ctx context.Context, videoID string, reason models.UnavailableReason,
+) error {
+	const query = `
+		UPDATE videos SET
+			unavailable_reason = NULLIF($2, ''),
+			unavailable_since = CASE WHEN $2 = '' THEN NULL ELSE COALESCE(unavailable_since, NOW()) END
+		WHERE id = $1
+	`
+	_, err := db.db.Exec(ctx, query, videoID, reason)
+	if err != nil {
+		db.l.Error("Failed to set video availability", "call", "sql.Exec", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (db *postgresDB) GetLiveVideos(ctx context.Context) ([]models.Video, error) {
-	query := `SELECT id FROM videos WHERE is_live = true AND watch_timestamp IS NULL`
+	query := `
+		SELECT id, is_live, is_upcoming, scheduled_start_time
+		FROM videos
+		WHERE (is_live = true OR is_upcoming = true) AND watch_timestamp IS NULL
+	`
 
 	rows, err := db.db.Query(ctx, query)
 	if err != nil {
@@ -407,7 +1082,7 @@ func (db *postgresDB) GetLiveVideos(ctx context.Context) ([]models.Video, error)
 	videos := make([]models.Video, 0)
 	for rows.Next() {
 		var video models.Video
-		err = rows.Scan(&video.ID)
+		err = rows.Scan(&video.ID, &video.IsLive, &video.IsUpcoming, &video.ScheduledStartTime)
 		if err != nil {
 			db.l.Error("Failed to scan live video", "error", err)
 			return nil, err
@@ -418,9 +1093,19 @@ func (db *postgresDB) GetLiveVideos(ctx context.Context) ([]models.Video, error)
 	return videos, nil
 }
 
-func (db *postgresDB) UpdateVideoLiveStatus(ctx context.Context, videoID string, isLive bool, duration int) error {
-	query := `UPDATE videos SET is_live = $1, duration = $2 WHERE id = $3`
-	_, err := db.db.Exec(ctx, query, isLive, duration, videoID)
+func (db *postgresDB) UpdateVideoLiveStatus(ctx context.Context, video models.Video) error {
+	query := `
+		UPDATE videos
+		SET
+			is_live = $1,
+			is_upcoming = $2,
+			scheduled_start_time = $3,
+			duration = $4
+		WHERE id = $5
+	`
+	_, err := db.db.Exec(
+		ctx, query, video.IsLive, video.IsUpcoming, video.ScheduledStartTime, video.DurationSeconds, video.ID,
+	)
 	if err != nil {
 		db.l.Error("Failed to update video live status", "error", err)
 		return err
@@ -445,3 +1130,72 @@ func (db *postgresDB) DeleteVideoFile(ctx context.Context, videoID string) error
 	}
 	return nil
 }
+
+func (db *postgresDB) GetVideosPendingDuration(ctx context.Context) ([]models.Video, error) {
+	const query = `
+		SELECT
+			videos.id
+			, videos.channel_id
+			, videos.title
+			, videos.watch_timestamp
+			, videos.is_short
+			, COALESCE(videos.short_signal, '')
+			, videos.is_discarded
+			, COALESCE(videos.discard_reason, '')
+		FROM videos
+		WHERE videos.duration_pending
+		ORDER BY videos.published_timestamp DESC
+	`
+	rows, err := db.db.Query(ctx, query)
+	if err != nil {
+		db.l.Error("Failed to get videos pending duration", "call", "sql.QueryContext", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := make([]models.Video, 0)
+	for rows.Next() {
+		var video models.Video
+		var shortSignal, discardReason string
+		err = rows.Scan(
+			&video.ID, &video.ChannelID, &video.Title, &video.WatchTime, &video.IsShort, &shortSignal,
+			&video.IsDiscarded, &discardReason,
+		)
+		if err != nil {
+			db.l.Error("Failed to scan video pending duration", "call", "sql.Scan", "error", err)
+			return nil, err
+		}
+		video.ShortSignal = models.ShortSignal(shortSignal)
+		video.DiscardReason = models.DiscardReason(discardReason)
+		video.DurationPending = true
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+func (db *postgresDB) SetVideoDuration(ctx context.Context, video models.Video) error {
+	const query = `
+		UPDATE videos
+		SET
+			duration = $1,
+			is_short = $2,
+			short_signal = NULLIF($3, ''),
+			is_live = $4,
+			is_upcoming = $5,
+			scheduled_start_time = $6,
+			unavailable_reason = NULLIF($7, ''),
+			duration_pending = false
+		WHERE id = $8
+	`
+	_, err := db.db.Exec(
+		ctx, query, video.DurationSeconds, video.IsShort, string(video.ShortSignal), video.IsLive, video.IsUpcoming,
+		video.ScheduledStartTime, string(video.UnavailableReason), video.ID,
+	)
+	if err != nil {
+		db.l.Error("Failed to set video duration", "call", "sql.ExecContext", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,44 +1,94 @@
 package feedparser
 
 import (
-	"encoding/xml"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"net/url"
+	"time"
 
-	"github.com/paulrosania/go-charset/charset"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/ratelimit"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 var (
-	ErrInvalidChannelID = errors.New("invalid channel ID")
-	ErrParseFailed      = errors.New("failed to parse feed")
+	ErrInvalidChannelID     = errors.New("invalid channel ID")
+	ErrParseFailed          = errors.New("failed to parse feed")
+	ErrNotModified          = errors.New("feed not modified")
+	ErrUnsupportedSource    = errors.New("unsupported source type")
+	ErrInvalidFeedReference = errors.New("invalid feed reference")
 )
 
-var urlFormat = "https://www.youtube.com/feeds/videos.xml?channel_id=%s"
-
 type Parser interface {
-	Parse(channelID string) (*Channel, error)
+	// Parse fetches and parses the feed of a channel unconditionally
+	Parse(ctx context.Context, channel models.Channel) (*Channel, error)
+	// ParseIfModified fetches the feed of a channel using the given cache validators and returns
+	// ErrNotModified if the feed hasn't changed since they were issued
+	ParseIfModified(ctx context.Context, channel models.Channel, validators CacheValidators) (*Channel, error)
+	// ParseReader parses a feed of a channel that was already retrieved, e.g. pushed by a WebSub hub
+	ParseReader(channel models.Channel, reader io.Reader) (*Channel, error)
+}
+
+// source knows where to find the feed of a channel of one source type and how to map its entries to videos
+type source interface {
+	feedURL(channel models.Channel) string
+	decode(channel models.Channel, reader io.Reader) (*Channel, error)
 }
 
 type parser struct {
-	log *slog.Logger
+	log     *slog.Logger
+	sources map[string]source
+	// limiter spaces out requests to the same host, nil to send them right away
+	limiter *ratelimit.HostLimiter
+	// timeout bounds each feed request including reading its body, 0 for no limit besides the context
+	timeout time.Duration
 }
 
-func NewParser(l *slog.Logger) *parser {
+// NewParser returns a parser that waits for the limiter before each feed request and gives up on requests
+// that take longer than timeout
+func NewParser(l *slog.Logger, limiter *ratelimit.HostLimiter, timeout time.Duration) *parser {
 	return &parser{
-		log: l,
+		log:     l,
+		limiter: limiter,
+		timeout: timeout,
+		sources: map[string]source{
+			models.SourceYouTube:         youTubeSource{log: l},
+			models.SourceYouTubePlaylist: youTubeSource{log: l, playlist: true},
+			models.SourcePeerTube:        rssSource{log: l, idPrefix: "pt"},
+			models.SourceGenericRSS:      rssSource{log: l, idPrefix: "rss"},
+		},
+	}
+}
+
+func (p *parser) source(channel models.Channel) (source, error) {
+	sourceType := channel.SourceType
+	if sourceType == "" {
+		sourceType = models.SourceYouTube
+	}
+
+	src, ok := p.sources[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSource, sourceType)
 	}
+
+	return src, nil
 }
 
-func (p *parser) fetch(url string) (io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (p *parser) fetch(ctx context.Context, url string, validators CacheValidators) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		p.log.Error("Failed to create request", "call", "http.NewRequest", "error", err)
 		return nil, err
 	}
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
 
 	response, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -46,36 +96,81 @@ func (p *parser) fetch(url string) (io.ReadCloser, error) {
 		return nil, err
 	}
 
-	if response.StatusCode == http.StatusNotFound {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response, nil
+	case http.StatusNotModified:
+		response.Body.Close()
+		return nil, ErrNotModified
+	case http.StatusNotFound:
+		response.Body.Close()
 		return nil, fmt.Errorf("%w: %s", ErrInvalidChannelID, url)
-	} else if response.StatusCode != http.StatusOK {
+	default:
+		response.Body.Close()
 		return nil, fmt.Errorf("failed to get feed with status %d", response.StatusCode)
 	}
+}
+
+// waitForHost waits until the rate limit of the feed's host allows another request
+func (p *parser) waitForHost(ctx context.Context, feedURL string) error {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFeedReference, err.Error())
+	}
+
+	return p.limiter.Wait(ctx, u.Host)
+}
 
-	return response.Body, nil
+// Parse parses the feed of a channel
+func (p *parser) Parse(ctx context.Context, channel models.Channel) (*Channel, error) {
+	return p.ParseIfModified(ctx, channel, CacheValidators{})
 }
 
-// Parse parses a YouTube channel XML feed from a channel ID
-func (p *parser) Parse(channelID string) (*Channel, error) {
-	url := fmt.Sprintf(urlFormat, channelID)
-	reader, err := p.fetch(url)
+// ParseIfModified parses the feed of a channel, sending a conditional request if validators from a previous
+// fetch are available
+func (p *parser) ParseIfModified(
+	ctx context.Context, channel models.Channel, validators CacheValidators,
+) (*Channel, error) {
+	src, err := p.source(channel)
 	if err != nil {
 		return nil, err
 	}
 
-	defer reader.Close()
-	xmlDecoder := xml.NewDecoder(reader)
-	xmlDecoder.CharsetReader = charset.NewReader
+	feedURL := src.feedURL(channel)
+	if err := p.waitForHost(ctx, feedURL); err != nil {
+		return nil, err
+	}
+	// The timeout starts once the request may be sent, so waiting for the rate limit doesn't count
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	response, err := p.fetch(ctx, feedURL, validators)
+	if err != nil {
+		return nil, err
+	}
 
-	var channel Channel
-	if err := xmlDecoder.Decode(&channel); err != nil {
-		p.log.Error("Failed to decode XML for RSS feed", "call", "xml.Decode", "error", err)
-		return nil, fmt.Errorf("%w: %s", ErrParseFailed, err.Error())
+	defer response.Body.Close()
+	parsedChannel, err := src.decode(channel, response.Body)
+	if err != nil {
+		return nil, err
 	}
-	channel.ID = channelID
-	for _, video := range channel.Videos {
-		video.IsShort = strings.Contains(video.Link.Href, "/shorts/")
+	parsedChannel.Validators = CacheValidators{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}
+
+	return parsedChannel, nil
+}
+
+// ParseReader parses the feed of a channel from a reader
+func (p *parser) ParseReader(channel models.Channel, reader io.Reader) (*Channel, error) {
+	src, err := p.source(channel)
+	if err != nil {
+		return nil, err
 	}
 
-	return &channel, nil
+	return src.decode(channel, reader)
 }
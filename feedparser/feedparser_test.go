@@ -0,0 +1,230 @@
+package feedparser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+const testFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed
+	xmlns:yt="http://www.youtube.com/xml/schemas/2015"
+	xmlns:media="http://search.yahoo.com/mrss/"
+	xmlns="http://www.w3.org/2005/Atom"
+>
+	<title>Test Channel</title>
+	<entry>
+		<id>yt:video:dQw4w9WgXcQ</id>
+		<yt:channelId>UCuploader</yt:channelId>
+		<author><name>Uploader</name></author>
+		<title>Test Video</title>
+		<link rel="alternate" href="https://www.youtube.com/shorts/dQw4w9WgXcQ"/>
+		<published>2024-01-01T00:00:00+00:00</published>
+		<media:group>
+			<media:title>Test Video</media:title>
+			<media:thumbnail url="https://i3.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"/>
+			<media:description>First line
+Second line</media:description>
+			<media:community>
+				<media:starRating count="678" average="5.00" min="1" max="5"/>
+				<media:statistics views="12345"/>
+			</media:community>
+		</media:group>
+	</entry>
+</feed>`
+
+func TestParseIfModified(t *testing.T) {
+	const etag = `"abc123"`
+	const lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	oldURLFormat := urlFormat
+	urlFormat = server.URL + "/?channel_id=%s"
+	defer func() { urlFormat = oldURLFormat }()
+
+	p := NewParser(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, 0)
+
+	channel, err := p.ParseIfModified(context.Background(), models.Channel{ID: "UC123"}, CacheValidators{})
+	require.NoError(t, err)
+	assert.Equal(t, "UC123", channel.ID)
+	assert.Equal(t, "Test Channel", channel.Name)
+	assert.Equal(t, etag, channel.Validators.ETag)
+	assert.Equal(t, lastModified, channel.Validators.LastModified)
+	require.Len(t, channel.Videos, 1)
+	assert.Equal(t, "dQw4w9WgXcQ", channel.Videos[0].ID)
+	assert.Equal(t, "UCuploader", channel.Videos[0].ChannelID)
+	assert.Equal(t, "Uploader", channel.Videos[0].ChannelName)
+	assert.True(t, channel.Videos[0].IsShort)
+	assert.Equal(t, "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg", channel.Videos[0].Thumbnail)
+	assert.Equal(t, "First line\nSecond line", channel.Videos[0].Description)
+	assert.Equal(t, int64(12345), channel.Videos[0].Views)
+	assert.Equal(t, 5.0, channel.Videos[0].RatingAverage)
+	assert.Equal(t, int64(678), channel.Videos[0].RatingCount)
+
+	_, err = p.ParseIfModified(context.Background(), models.Channel{ID: "UC123"}, channel.Validators)
+	assert.True(t, errors.Is(err, ErrNotModified))
+}
+
+func TestParseIfModifiedTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	oldURLFormat := urlFormat
+	urlFormat = server.URL + "/?channel_id=%s"
+	defer func() { urlFormat = oldURLFormat }()
+
+	p := NewParser(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, 20*time.Millisecond)
+
+	_, err := p.ParseIfModified(context.Background(), models.Channel{ID: "UC123"}, CacheValidators{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+const testPeerTubeFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+	<channel>
+		<title>PeerTube Channel</title>
+		<link>https://peertube.example.com/c/test</link>
+		<item>
+			<title>PeerTube Video</title>
+			<link>https://peertube.example.com/w/abc</link>
+			<guid>https://peertube.example.com/w/abc</guid>
+			<pubDate>Mon, 01 Jan 2024 00:00:00 GMT</pubDate>
+			<media:group>
+				<media:content url="https://peertube.example.com/abc.mp4" duration="125"/>
+				<media:thumbnail url="https://peertube.example.com/abc.jpg"/>
+			</media:group>
+		</item>
+		<item>
+			<title>Broken Video</title>
+			<pubDate>Mon, 01 Jan 2024 00:00:00 GMT</pubDate>
+		</item>
+	</channel>
+</rss>`
+
+func TestParseReaderRSS(t *testing.T) {
+	p := NewParser(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, 0)
+	feedURL := "https://peertube.example.com/feeds/videos.xml?videoChannelName=test"
+	channel := models.Channel{
+		ID:         FeedChannelID(models.SourcePeerTube, feedURL),
+		SourceType: models.SourcePeerTube,
+		FeedURL:    feedURL,
+	}
+
+	parsed, err := p.ParseReader(channel, strings.NewReader(testPeerTubeFeed))
+	require.NoError(t, err)
+	assert.Equal(t, "PeerTube Channel", parsed.Name)
+	assert.Equal(t, "https://peertube.example.com/c/test", parsed.SiteURL)
+	require.Len(t, parsed.Videos, 1)
+	video := parsed.Videos[0]
+	assert.True(t, strings.HasPrefix(video.ID, "pt-"))
+	assert.Equal(t, "https://peertube.example.com/w/abc", video.URL)
+	assert.Equal(t, "https://peertube.example.com/abc.jpg", video.Thumbnail)
+	assert.Equal(t, 125, video.DurationSeconds)
+	assert.Equal(t, 2024, video.PublishedTime.Year())
+}
+
+const testUnsafeFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Unsafe Channel</title>
+	<link rel="alternate" href="javascript:alert(1)"/>
+	<entry>
+		<id>urn:video:1</id>
+		<title>Option Video</title>
+		<link rel="alternate" href="--exec=touch /tmp/pwned"/>
+		<published>2024-01-01T00:00:00Z</published>
+	</entry>
+	<entry>
+		<id>urn:video:2</id>
+		<title>Safe Video</title>
+		<link rel="alternate" href="https://videos.example.com/watch/2"/>
+		<published>2024-01-01T00:00:00Z</published>
+	</entry>
+</feed>`
+
+func TestParseReaderDropsUnsafeLinks(t *testing.T) {
+	p := NewParser(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, 0)
+	feedURL := "https://videos.example.com/feed.atom"
+	channel := models.Channel{
+		ID:         FeedChannelID(models.SourceGenericRSS, feedURL),
+		SourceType: models.SourceGenericRSS,
+		FeedURL:    feedURL,
+	}
+
+	parsed, err := p.ParseReader(channel, strings.NewReader(testUnsafeFeed))
+	require.NoError(t, err)
+	assert.Empty(t, parsed.SiteURL)
+	require.Len(t, parsed.Videos, 1)
+	assert.Equal(t, "https://videos.example.com/watch/2", parsed.Videos[0].URL)
+}
+
+func TestWebURL(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://example.com/w/abc", "https://example.com/w/abc"},
+		{" http://example.com/w/abc\n", "http://example.com/w/abc"},
+		{"javascript:alert(1)", ""},
+		{"JavaScript:alert(1)", ""},
+		{"data:text/html,hi", ""},
+		{"--exec=touch /tmp/pwned", ""},
+		{"//example.com/w/abc", ""},
+		{"https:///w/abc", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, webURL(tt.link), tt.link)
+	}
+}
+
+func TestPeerTubeFeedURL(t *testing.T) {
+	const channelFeed = "https://tube.example.com/feeds/videos.xml?videoChannelName=chan"
+	const accountFeed = "https://tube.example.com/feeds/videos.xml?accountName=user"
+	tests := []struct {
+		pageURL string
+		want    string
+		wantErr bool
+	}{
+		{"https://tube.example.com/c/chan", channelFeed, false},
+		{"https://tube.example.com/video-channels/chan/videos", channelFeed, false},
+		{"https://tube.example.com/a/user", accountFeed, false},
+		{accountFeed, accountFeed, false},
+		{"https://tube.example.com/w/abc", "", true},
+		{"not a url", "", true},
+	}
+	for _, tt := range tests {
+		got, err := PeerTubeFeedURL(tt.pageURL)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, ErrInvalidFeedReference, tt.pageURL)
+			continue
+		}
+		require.NoError(t, err, tt.pageURL)
+		assert.Equal(t, tt.want, got)
+	}
+}
@@ -1,22 +1,133 @@
 package feedparser
 
+import (
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
 // Link struct for the link element
 type Link struct {
+	Rel  string `xml:"rel,attr"`
 	Href string `xml:"href,attr"`
 }
 
-// Video struct for each video in the feed
+// Author struct for the author element
+type Author struct {
+	Name string `xml:"name"`
+}
+
+// Video struct for each video in a YouTube feed
 type Video struct {
-	ID        string `xml:"id"`
-	Title     string `xml:"title"`
-	Published Date   `xml:"published"`
-	Link      Link   `xml:"link"`
+	ID        string     `xml:"id"`
+	ChannelID string     `xml:"channelId"`
+	Author    Author     `xml:"author"`
+	Title     string     `xml:"title"`
+	Published Date       `xml:"published"`
+	Link      Link       `xml:"link"`
+	Group     MediaGroup `xml:"group"`
 	IsShort   bool
 }
 
-// Channel struct for RSS
-type Channel struct {
-	ID     string
+// youTubeFeed is the Atom feed of a YouTube channel or playlist
+type youTubeFeed struct {
 	Name   string   `xml:"title"`
 	Videos []*Video `xml:"entry"`
 }
+
+// MediaThumbnail is a Media RSS thumbnail element
+type MediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// MediaContent is a Media RSS content element
+type MediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Duration int    `xml:"duration,attr"`
+}
+
+// MediaStarRating is a Media RSS star rating element
+type MediaStarRating struct {
+	Count   int64   `xml:"count,attr"`
+	Average float64 `xml:"average,attr"`
+}
+
+// MediaStatistics is a Media RSS statistics element
+type MediaStatistics struct {
+	Views int64 `xml:"views,attr"`
+}
+
+// MediaCommunity is a Media RSS community element with the audience's reaction to an item
+type MediaCommunity struct {
+	StarRating MediaStarRating `xml:"starRating"`
+	Statistics MediaStatistics `xml:"statistics"`
+}
+
+// MediaGroup groups Media RSS elements describing the same item
+type MediaGroup struct {
+	Description string           `xml:"description"`
+	Thumbnails  []MediaThumbnail `xml:"thumbnail"`
+	Contents    []MediaContent   `xml:"content"`
+	Community   MediaCommunity   `xml:"community"`
+}
+
+// rssItem is an item of an RSS 2.0 feed
+type rssItem struct {
+	GUID       string           `xml:"guid"`
+	Title      string           `xml:"title"`
+	Link       string           `xml:"link"`
+	PubDate    string           `xml:"pubDate"`
+	Thumbnails []MediaThumbnail `xml:"thumbnail"`
+	Contents   []MediaContent   `xml:"content"`
+	Group      MediaGroup       `xml:"group"`
+}
+
+// rssFeed is an RSS 2.0 feed
+type rssFeed struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Link  string `xml:"link"`
+		Image struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// atomEntry is an entry of a generic Atom feed
+type atomEntry struct {
+	ID         string           `xml:"id"`
+	Title      string           `xml:"title"`
+	Links      []Link           `xml:"link"`
+	Published  string           `xml:"published"`
+	Updated    string           `xml:"updated"`
+	Thumbnails []MediaThumbnail `xml:"thumbnail"`
+	Group      MediaGroup       `xml:"group"`
+}
+
+// atomFeed is a generic Atom feed
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Links   []Link      `xml:"link"`
+	Logo    string      `xml:"logo"`
+	Icon    string      `xml:"icon"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// CacheValidators holds the HTTP cache validators returned alongside a feed
+type CacheValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// Channel is a parsed feed with its entries mapped to videos
+type Channel struct {
+	ID         string
+	SourceType string
+	Name       string
+	// SiteURL is the link to the channel's page as advertised by the feed, if any
+	SiteURL string
+	// ImageURL is the channel image advertised by the feed, if any
+	ImageURL   string
+	Videos     []models.Video
+	Validators CacheValidators
+}
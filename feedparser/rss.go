@@ -0,0 +1,294 @@
+package feedparser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/paulrosania/go-charset/charset"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// rssDateFormats are the date formats found in the wild in RSS and Atom feeds
+var rssDateFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+}
+
+// rssSource handles generic RSS 2.0 and Atom video feeds, including the ones published by PeerTube
+type rssSource struct {
+	log      *slog.Logger
+	idPrefix string
+}
+
+// hashID derives a stable, URL safe ID from a prefix and a unique string
+func hashID(prefix string, unique string) string {
+	sum := sha256.Sum256([]byte(unique))
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(sum[:])[:22])
+}
+
+// FeedChannelID returns the channel ID for a non-YouTube feed
+func FeedChannelID(sourceType string, feedURL string) string {
+	prefix := "rss"
+	if sourceType == models.SourcePeerTube {
+		prefix = "pt"
+	}
+
+	return hashID(prefix, feedURL)
+}
+
+// PeerTubeFeedURL returns the video feed URL of a PeerTube channel or account from the URL of its page
+func PeerTubeFeedURL(pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("%w: %q is not a URL", ErrInvalidFeedReference, pageURL)
+	}
+	if u.Path == "/feeds/videos.xml" {
+		return pageURL, nil
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return "", fmt.Errorf("%w: %q is not a PeerTube channel or account URL", ErrInvalidFeedReference, pageURL)
+	}
+
+	query := url.Values{}
+	switch segments[0] {
+	case "c", "video-channels":
+		query.Set("videoChannelName", segments[1])
+	case "a", "accounts":
+		query.Set("accountName", segments[1])
+	default:
+		return "", fmt.Errorf("%w: %q is not a PeerTube channel or account URL", ErrInvalidFeedReference, pageURL)
+	}
+
+	feedURL := url.URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     "/feeds/videos.xml",
+		RawQuery: query.Encode(),
+	}
+	return feedURL.String(), nil
+}
+
+func parseFeedDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, format := range rssDateFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
+
+// webURL returns the link if it is an absolute http or https URL and an empty string otherwise. Links from
+// feeds end up in pages and yt-dlp arguments, so anything else, like javascript: URLs, is dropped.
+func webURL(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+
+	return u.String()
+}
+
+func firstThumbnail(thumbnailLists ...[]MediaThumbnail) string {
+	for _, thumbnails := range thumbnailLists {
+		for _, thumbnail := range thumbnails {
+			if thumbnailURL := webURL(thumbnail.URL); thumbnailURL != "" {
+				return thumbnailURL
+			}
+		}
+	}
+
+	return ""
+}
+
+func maxDuration(contentLists ...[]MediaContent) int {
+	duration := 0
+	for _, contents := range contentLists {
+		for _, content := range contents {
+			duration = max(duration, content.Duration)
+		}
+	}
+
+	return duration
+}
+
+func (s rssSource) feedURL(channel models.Channel) string {
+	return channel.FeedURL
+}
+
+// decode parses an RSS 2.0 or Atom feed, depending on its root element
+func (s rssSource) decode(channel models.Channel, reader io.Reader) (*Channel, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrParseFailed, err.Error())
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		s.log.Error("Failed to decode XML for feed", "call", "xml.Token", "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrParseFailed, err.Error())
+	}
+
+	switch root {
+	case "rss":
+		return s.decodeRSS(channel, data)
+	case "feed":
+		return s.decodeAtom(channel, data)
+	default:
+		return nil, fmt.Errorf("%w: unknown root element %q", ErrParseFailed, root)
+	}
+}
+
+func newDecoder(data []byte) *xml.Decoder {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(data))
+	xmlDecoder.CharsetReader = charset.NewReader
+	return xmlDecoder
+}
+
+func rootElement(data []byte) (string, error) {
+	xmlDecoder := newDecoder(data)
+	for {
+		token, err := xmlDecoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("no root element")
+			}
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func (s rssSource) decodeRSS(channel models.Channel, data []byte) (*Channel, error) {
+	var feed rssFeed
+	if err := newDecoder(data).Decode(&feed); err != nil {
+		s.log.Error("Failed to decode XML for RSS feed", "call", "xml.Decode", "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrParseFailed, err.Error())
+	}
+
+	parsedChannel := &Channel{
+		ID:         channel.ID,
+		SourceType: channel.SourceType,
+		Name:       feed.Channel.Title,
+		SiteURL:    webURL(feed.Channel.Link),
+		ImageURL:   webURL(feed.Channel.Image.URL),
+		Videos:     make([]models.Video, 0, len(feed.Channel.Items)),
+	}
+	for _, item := range feed.Channel.Items {
+		link := webURL(item.Link)
+		unique := item.GUID
+		if unique == "" {
+			unique = link
+		}
+		if unique == "" || link == "" {
+			s.log.Warn("Skipping feed item without a web link", "channelID", channel.ID, "title", item.Title)
+			continue
+		}
+
+		date, err := parseFeedDate(item.PubDate)
+		if err != nil {
+			s.log.Error("Failed to parse video information", "call", "feedparser.Parse", "err", err)
+			continue
+		}
+
+		parsedChannel.Videos = append(parsedChannel.Videos, models.Video{
+			ID:              hashID(s.idPrefix, unique),
+			Title:           item.Title,
+			URL:             link,
+			Thumbnail:       firstThumbnail(item.Thumbnails, item.Group.Thumbnails),
+			PublishedTime:   date,
+			DurationSeconds: maxDuration(item.Contents, item.Group.Contents),
+			Description:     item.Group.Description,
+			Views:           item.Group.Community.Statistics.Views,
+			RatingAverage:   item.Group.Community.StarRating.Average,
+			RatingCount:     item.Group.Community.StarRating.Count,
+		})
+	}
+
+	return parsedChannel, nil
+}
+
+func alternateLink(links []Link) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
+func (s rssSource) decodeAtom(channel models.Channel, data []byte) (*Channel, error) {
+	var feed atomFeed
+	if err := newDecoder(data).Decode(&feed); err != nil {
+		s.log.Error("Failed to decode XML for Atom feed", "call", "xml.Decode", "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrParseFailed, err.Error())
+	}
+
+	imageURL := feed.Logo
+	if imageURL == "" {
+		imageURL = feed.Icon
+	}
+	parsedChannel := &Channel{
+		ID:         channel.ID,
+		SourceType: channel.SourceType,
+		Name:       feed.Title,
+		SiteURL:    webURL(alternateLink(feed.Links)),
+		ImageURL:   webURL(imageURL),
+		Videos:     make([]models.Video, 0, len(feed.Entries)),
+	}
+	for _, entry := range feed.Entries {
+		link := webURL(alternateLink(entry.Links))
+		unique := entry.ID
+		if unique == "" {
+			unique = link
+		}
+		if unique == "" || link == "" {
+			s.log.Warn("Skipping feed entry without a web link", "channelID", channel.ID, "title", entry.Title)
+			continue
+		}
+
+		published := entry.Published
+		if published == "" {
+			published = entry.Updated
+		}
+		date, err := parseFeedDate(published)
+		if err != nil {
+			s.log.Error("Failed to parse video information", "call", "feedparser.Parse", "err", err)
+			continue
+		}
+
+		parsedChannel.Videos = append(parsedChannel.Videos, models.Video{
+			ID:              hashID(s.idPrefix, unique),
+			Title:           entry.Title,
+			URL:             link,
+			Thumbnail:       firstThumbnail(entry.Thumbnails, entry.Group.Thumbnails),
+			PublishedTime:   date,
+			DurationSeconds: maxDuration(entry.Group.Contents),
+			Description:     entry.Group.Description,
+			Views:           entry.Group.Community.Statistics.Views,
+			RatingAverage:   entry.Group.Community.StarRating.Average,
+			RatingCount:     entry.Group.Community.StarRating.Count,
+		})
+	}
+
+	return parsedChannel, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// AvailabilityRoutine periodically checks whether the unwatched YouTube videos can still be watched
+func (h *handler) AvailabilityRoutine(ctx context.Context) {
+	ticker := time.NewTicker(h.config.AvailabilityInterval)
+	defer ticker.Stop()
+
+	h.log.Info(
+		"Starting availability goroutine",
+		"interval", h.config.AvailabilityInterval,
+		"discard", h.config.DiscardUnavailable,
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.log.Info("Availability context done, stopping availability goroutine")
+			return
+		case <-ticker.C:
+			h.checkAvailability(ctx)
+		}
+	}
+}
+
+// checkAvailability marks the videos that were deleted, made private or blocked in the configured region as
+// unavailable and clears the mark from the ones that came back. Unavailable videos are discarded if enabled,
+// unless they were downloaded.
+func (h *handler) checkAvailability(ctx context.Context) {
+	stored, err := h.db.GetVideosForAvailabilityCheck(ctx)
+	if err != nil {
+		h.log.Error("Failed to get videos for availability check", "error", err)
+		return
+	}
+
+	changed, discarded := 0, 0
+	for batch := range slices.Chunk(stored, durationBatchSize) {
+		videos := make(map[string]*models.Video, len(batch))
+		previous := make(map[string]models.UnavailableReason, len(batch))
+		for i := range batch {
+			videos[batch[i].ID] = &batch[i]
+			previous[batch[i].ID] = batch[i].UnavailableReason
+		}
+		err := h.youTubeClient.GetVideoDurations(ctx, videos)
+		failed := durationErrors(err, videos)
+		if isQuotaError(err) {
+			h.log.Info("Skipping availability check until the quota resets", "error", err)
+		} else if err != nil {
+			h.log.Error("Failed to check video availability", "call", "youtube.GetVideoDurations", "error", err)
+		}
+
+		for _, video := range videos {
+			// The availability of videos that couldn't be looked up is unknown
+			if err := failed[video.ID]; err != nil && !errors.Is(err, youtube.ErrInvalidDuration) {
+				continue
+			}
+			if video.UnavailableReason != previous[video.ID] {
+				err := h.db.SetVideoAvailability(ctx, video.ID, video.UnavailableReason)
+				if err != nil {
+					h.log.Error("Failed to set video availability", "videoID", video.ID, "error", err)
+					continue
+				}
+				changed++
+			}
+
+			if !h.config.DiscardUnavailable || !video.IsUnavailable() || video.IsPreserved() {
+				continue
+			}
+			err := h.db.DiscardVideo(ctx, video.ID, models.DiscardUnavailable)
+			if err != nil {
+				h.log.Error("Failed to discard unavailable video", "videoID", video.ID, "error", err)
+				continue
+			}
+			discarded++
+		}
+		if isAPIUnusable(err) {
+			break
+		}
+	}
+
+	if changed > 0 || discarded > 0 {
+		h.log.Info("Availability check completed", "checked", len(stored), "changed", changed, "discarded", discarded)
+	}
+}
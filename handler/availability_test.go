@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestCheckAvailability(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	completed := "completed"
+	availability := map[string]models.UnavailableReason{}
+	var discarded []string
+	dbMock := &db_mock.DBMock{
+		GetVideosForAvailabilityCheckFunc: func(ctx context.Context) ([]models.Video, error) {
+			return []models.Video{
+				{ID: "available"},
+				{ID: "deleted"},
+				{ID: "downloaded", DownloadStatus: &completed},
+				{ID: "back", UnavailableReason: models.UnavailableRemoved},
+			}, nil
+		},
+		SetVideoAvailabilityFunc: func(ctx context.Context, videoID string, reason models.UnavailableReason) error {
+			availability[videoID] = reason
+			return nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			assert.Equal(t, models.DiscardUnavailable, reason)
+			discarded = append(discarded, videoID)
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			for id, video := range videos {
+				video.UnavailableReason = models.Available
+				if id == "deleted" || id == "downloaded" {
+					video.UnavailableReason = models.UnavailableRemoved
+				}
+			}
+			return nil
+		},
+	}
+	cfg := testConfig
+	cfg.DiscardUnavailable = true
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, cfg)
+
+	h.checkAvailability(context.Background())
+
+	assert.Equal(t, map[string]models.UnavailableReason{
+		"deleted":    models.UnavailableRemoved,
+		"downloaded": models.UnavailableRemoved,
+		"back":       models.Available,
+	}, availability)
+	// The downloaded video is preserved
+	assert.Equal(t, []string{"deleted"}, discarded)
+}
+
+func TestCheckAvailabilitySkipsFailedVideos(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	availability := map[string]models.UnavailableReason{}
+	dbMock := &db_mock.DBMock{
+		GetVideosForAvailabilityCheckFunc: func(ctx context.Context) ([]models.Video, error) {
+			return []models.Video{{ID: "deleted"}, {ID: "failed"}, {ID: "broken"}}, nil
+		},
+		SetVideoAvailabilityFunc: func(ctx context.Context, videoID string, reason models.UnavailableReason) error {
+			availability[videoID] = reason
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			for _, video := range videos {
+				video.UnavailableReason = models.UnavailableRemoved
+			}
+			return youtube.VideoErrors{
+				"failed": fmt.Errorf("%w: backend error", youtube.ErrUnavailable),
+				"broken": fmt.Errorf("%w [PT?]", youtube.ErrInvalidDuration),
+			}
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	h.checkAvailability(context.Background())
+
+	// Videos with an invalid duration were still found, so their availability is known
+	assert.Equal(t, map[string]models.UnavailableReason{
+		"deleted": models.UnavailableRemoved,
+		"broken":  models.UnavailableRemoved,
+	}, availability)
+}
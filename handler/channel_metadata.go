@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/avatars"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// metadataRefreshTick is how often the refresher checks for channels whose metadata is older than
+// ChannelRefreshAge
+const metadataRefreshTick = time.Hour
+
+// ChannelMetadataRoutine periodically refreshes the name, handle, description, avatar and banner of the
+// subscribed YouTube channels, so rebrands show up
+func (h *handler) ChannelMetadataRoutine(ctx context.Context) {
+	ticker := time.NewTicker(metadataRefreshTick)
+	defer ticker.Stop()
+
+	h.log.Info("Starting channel metadata goroutine", "max_age", h.config.ChannelRefreshAge)
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.log.Info("Channel metadata context done, stopping channel metadata goroutine")
+			return
+		case <-ticker.C:
+			h.refreshChannelMetadata(ctx)
+		}
+	}
+}
+
+// refreshChannelMetadata updates the channels whose metadata is due for a refresh from the channels API and
+// caches their avatars when they changed
+func (h *handler) refreshChannelMetadata(ctx context.Context) {
+	channels, err := h.db.ListChannelsForMetadataRefresh(ctx, time.Now().Add(-h.config.ChannelRefreshAge))
+	if err != nil {
+		h.log.Error("Failed to list channels for metadata refresh", "error", err)
+		return
+	}
+
+	refreshed, renamed := 0, 0
+	for batch := range slices.Chunk(channels, youtube.ChannelsPageSize) {
+		ids := make([]string, 0, len(batch))
+		previous := make(map[string]models.Channel, len(batch))
+		for _, channel := range batch {
+			ids = append(ids, channel.ID)
+			previous[channel.ID] = channel
+		}
+
+		metadata, err := h.youTubeClient.GetChannelsMetadata(ctx, ids)
+		if isQuotaError(err) {
+			h.log.Info("Skipping channel metadata refresh until the quota resets", "error", err)
+			return
+		}
+		if err != nil {
+			h.log.Error("Failed to get channel metadata", "call", "youtube.GetChannelsMetadata", "error", err)
+			return
+		}
+		renamedIDs, err := h.db.UpdateChannelMetadata(ctx, metadata)
+		if err != nil {
+			h.log.Error("Failed to update channel metadata", "call", "db.UpdateChannelMetadata", "error", err)
+			return
+		}
+		refreshed += len(metadata)
+		renamed += len(renamedIDs)
+
+		// Terminated and removed channels are left out of the response, they'd be requested again on every
+		// tick without marking them as refreshed
+		missing := slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+			return slices.ContainsFunc(metadata, func(channel models.ChannelMetadata) bool {
+				return channel.ChannelID == id
+			})
+		})
+		if len(missing) > 0 {
+			h.log.Warn("Channels missing from the channels API", "channelIDs", missing)
+			err = h.db.SetChannelsMetadataRefreshed(ctx, missing)
+			if err != nil {
+				h.log.Error(
+					"Failed to mark missing channels as refreshed",
+					"call", "db.SetChannelsMetadataRefreshed",
+					"error", err,
+				)
+				return
+			}
+		}
+
+		for _, channel := range metadata {
+			if slices.Contains(renamedIDs, channel.ChannelID) {
+				h.log.Info(
+					"Channel was rebranded",
+					"channelID", channel.ChannelID,
+					"from", previous[channel.ChannelID].Name,
+					"to", channel.Name,
+				)
+			}
+			h.cacheAvatar(ctx, channel.ChannelID, previous[channel.ChannelID].ImageURL, channel.AvatarURL)
+		}
+	}
+
+	if refreshed > 0 {
+		h.log.Info("Channel metadata refresh completed", "refreshed", refreshed, "renamed", renamed)
+	}
+}
+
+// cacheAvatar downloads the avatar of a channel if it changed or isn't cached yet
+func (h *handler) cacheAvatar(ctx context.Context, channelID string, previousURL string, avatarURL string) {
+	if avatarURL == "" {
+		return
+	}
+	if avatarURL == previousURL {
+		if _, err := h.avatars.Path(channelID); err == nil {
+			return
+		}
+	}
+
+	err := h.avatars.Store(ctx, channelID, avatarURL)
+	if err != nil {
+		h.log.Error("Failed to cache channel avatar", "channelID", channelID, "error", err)
+	}
+}
+
+// ServeChannelAvatar returns the file the channel's avatar is cached in or, if it isn't cached, the URL of the
+// channel's image
+func (h *handler) ServeChannelAvatar(
+	ctx context.Context, channelID string,
+) (filePath string, imageURL string, err error) {
+	filePath, err = h.avatars.Path(channelID)
+	if err == nil {
+		return filePath, "", nil
+	}
+	if !errors.Is(err, avatars.ErrNotCached) && !errors.Is(err, avatars.ErrInvalidChannel) {
+		return "", "", err
+	}
+
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return "", "", err
+	}
+	if channel.ImageURL == "" {
+		return "", "", avatars.ErrNotCached
+	}
+
+	return "", channel.ImageURL, nil
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestRefreshChannelMetadata(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var avatarDownloads []string
+	avatarServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		avatarDownloads = append(avatarDownloads, r.URL.Path)
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer avatarServer.Close()
+
+	// 60 channels take two batches, only the first two have anything worth checking
+	channels := make([]models.Channel, 60)
+	for i := range channels {
+		channels[i] = models.Channel{ID: fmt.Sprintf("UC%02d", i), Name: fmt.Sprintf("Channel %d", i)}
+	}
+	channels[0].ImageURL = avatarServer.URL + "/old.jpg"
+	channels[1].ImageURL = avatarServer.URL + "/same.jpg"
+
+	var updated []models.ChannelMetadata
+	dbMock := &db_mock.DBMock{
+		ListChannelsForMetadataRefreshFunc: func(ctx context.Context, refreshedBefore time.Time) ([]models.Channel, error) {
+			assert.WithinDuration(t, time.Now().Add(-testConfig.ChannelRefreshAge), refreshedBefore, time.Minute)
+			return channels, nil
+		},
+		UpdateChannelMetadataFunc: func(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error) {
+			updated = append(updated, metadata...)
+			for _, channel := range metadata {
+				if channel.ChannelID == "UC00" {
+					return []string{"UC00"}, nil
+				}
+			}
+			return nil, nil
+		},
+		SetChannelsMetadataRefreshedFunc: func(ctx context.Context, channelIDs []string) error {
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		GetChannelsMetadataFunc: func(ctx context.Context, channelIDs []string) ([]models.ChannelMetadata, error) {
+			assert.LessOrEqual(t, len(channelIDs), 50)
+			metadata := make([]models.ChannelMetadata, 0, len(channelIDs))
+			for _, id := range channelIDs {
+				switch id {
+				case "UC00":
+					metadata = append(metadata, models.ChannelMetadata{
+						ChannelID: id, Name: "Rebranded", AvatarURL: avatarServer.URL + "/new.jpg",
+					})
+				case "UC01":
+					metadata = append(metadata, models.ChannelMetadata{
+						ChannelID: id, Name: "Channel 1", AvatarURL: avatarServer.URL + "/same.jpg",
+					})
+				case "UC59":
+					// Terminated channels are left out of the response
+				default:
+					metadata = append(metadata, models.ChannelMetadata{ChannelID: id, Name: "Unchanged"})
+				}
+			}
+			return metadata, nil
+		},
+	}
+	cfg := testConfig
+	cfg.AvatarsDir = t.TempDir()
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, cfg)
+
+	h.refreshChannelMetadata(context.Background())
+
+	assert.Len(t, youTubeMock.GetChannelsMetadataCalls(), 2)
+	assert.Len(t, updated, 59)
+	// The terminated channel isn't requested again on the next tick
+	require.Len(t, dbMock.SetChannelsMetadataRefreshedCalls(), 1)
+	assert.Equal(t, []string{"UC59"}, dbMock.SetChannelsMetadataRefreshedCalls()[0].ChannelIDs)
+	// The changed avatar is downloaded, and so is the unchanged one that wasn't cached yet
+	assert.ElementsMatch(t, []string{"/new.jpg", "/same.jpg"}, avatarDownloads)
+	path, err := h.avatars.Path("UC00")
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "/new.jpg", string(content))
+
+	// Once cached, an unchanged avatar isn't downloaded again
+	avatarDownloads = nil
+	h.refreshChannelMetadata(context.Background())
+	assert.Equal(t, []string{"/new.jpg"}, avatarDownloads)
+}
+
+func TestServeChannelAvatarFallsBackToImageURL(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, ImageURL: "https://yt3.ggpht.com/avatar"}, nil
+		},
+	}
+	cfg := testConfig
+	cfg.AvatarsDir = t.TempDir()
+	h := New(l, dbMock, nil, nil, nil, nil, cfg)
+
+	filePath, imageURL, err := h.ServeChannelAvatar(context.Background(), "UC123")
+	require.NoError(t, err)
+	assert.Empty(t, filePath)
+	assert.Equal(t, "https://yt3.ggpht.com/avatar", imageURL)
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+)
+
+var (
+	ErrInvalidChannelReference = errors.New("not a YouTube channel ID, handle or URL")
+	ErrNotYouTubeURL           = errors.New("not a YouTube URL")
+	ErrUnsupportedChannelURL   = errors.New("YouTube URL doesn't point to a channel or video")
+)
+
+var (
+	handlePattern  = regexp.MustCompile(`^[\p{L}\p{N}._-]{3,30}$`)
+	videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	// legacyNamePattern matches the names of /user/ and /c/ URLs
+	legacyNamePattern = regexp.MustCompile(`^[\p{L}\p{N}._-]+$`)
+)
+
+// reservedPaths are the first path segments of YouTube pages that aren't a legacy vanity URL of a channel
+var reservedPaths = map[string]bool{
+	"feed": true, "results": true, "playlist": true, "account": true, "premium": true, "gaming": true,
+	"hashtag": true, "channels": true, "redirect": true, "signin": true, "logout": true, "about": true,
+}
+
+type channelReferenceKind int
+
+const (
+	// channelRefID is a UC… channel ID
+	channelRefID channelReferenceKind = iota
+	// channelRefHandle is an @handle, without the @
+	channelRefHandle
+	// channelRefUsername is the name of a legacy /user/ URL
+	channelRefUsername
+	// channelRefCustomURL is the name of a legacy /c/ or vanity URL
+	channelRefCustomURL
+	// channelRefVideo is the ID of a video uploaded by the channel
+	channelRefVideo
+)
+
+// channelReference is anything a user may paste to identify a YouTube channel
+type channelReference struct {
+	kind  channelReferenceKind
+	value string
+}
+
+// parseChannelReference recognizes a raw channel ID, an @handle with or without the @, and channel, handle,
+// legacy /c/ and /user/ URLs as well as video URLs
+func parseChannelReference(input string) (channelReference, error) {
+	ref := strings.TrimSpace(input)
+	if isChannelID(ref) {
+		return channelReference{kind: channelRefID, value: ref}, nil
+	}
+	if !strings.Contains(ref, "/") {
+		handle := strings.TrimPrefix(ref, "@")
+		if !handlePattern.MatchString(handle) {
+			return channelReference{}, fmt.Errorf("%w: %q", ErrInvalidChannelReference, input)
+		}
+		return channelReference{kind: channelRefHandle, value: handle}, nil
+	}
+
+	if !strings.Contains(ref, "://") {
+		ref = "https://" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return channelReference{}, fmt.Errorf("%w: %v", ErrInvalidChannelReference, err.Error())
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"www.", "m.", "music."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch host {
+	case "youtu.be":
+		return videoReference(input, segments[0])
+	case "youtube.com":
+	default:
+		return channelReference{}, fmt.Errorf("%w: %q", ErrNotYouTubeURL, input)
+	}
+
+	first := segments[0]
+	second := ""
+	if len(segments) > 1 {
+		second = segments[1]
+	}
+	switch {
+	case strings.HasPrefix(first, "@"):
+		handle := strings.TrimPrefix(first, "@")
+		if !handlePattern.MatchString(handle) {
+			return channelReference{}, fmt.Errorf("%w: %q", ErrInvalidChannelReference, input)
+		}
+		return channelReference{kind: channelRefHandle, value: handle}, nil
+	case first == "channel":
+		if !isChannelID(second) {
+			return channelReference{}, fmt.Errorf("%w: %q has no valid channel ID", ErrInvalidChannelReference, input)
+		}
+		return channelReference{kind: channelRefID, value: second}, nil
+	case first == "user":
+		return legacyReference(input, channelRefUsername, second)
+	case first == "c":
+		return legacyReference(input, channelRefCustomURL, second)
+	case first == "watch":
+		return videoReference(input, u.Query().Get("v"))
+	case first == "shorts" || first == "live" || first == "embed" || first == "v":
+		return videoReference(input, second)
+	case first == "playlist":
+		return channelReference{}, fmt.Errorf(
+			"%w: %q is a playlist, subscribe to it as a playlist instead", ErrUnsupportedChannelURL, input,
+		)
+	case first == "" || reservedPaths[first]:
+		return channelReference{}, fmt.Errorf("%w: %q", ErrUnsupportedChannelURL, input)
+	default:
+		// youtube.com/name is the oldest form of a custom URL
+		return legacyReference(input, channelRefCustomURL, first)
+	}
+}
+
+func legacyReference(input string, kind channelReferenceKind, name string) (channelReference, error) {
+	if !legacyNamePattern.MatchString(name) {
+		return channelReference{}, fmt.Errorf("%w: %q has no valid channel name", ErrInvalidChannelReference, input)
+	}
+
+	return channelReference{kind: kind, value: name}, nil
+}
+
+func videoReference(input string, videoID string) (channelReference, error) {
+	if !videoIDPattern.MatchString(videoID) {
+		return channelReference{}, fmt.Errorf("%w: %q has no valid video ID", ErrInvalidChannelReference, input)
+	}
+
+	return channelReference{kind: channelRefVideo, value: videoID}, nil
+}
+
+// resolveChannelReference looks up the ID of the channel a reference points to
+func (h *handler) resolveChannelReference(ctx context.Context, ref channelReference) (string, error) {
+	switch ref.kind {
+	case channelRefID:
+		return ref.value, nil
+	case channelRefHandle:
+		return h.youTubeClient.ResolveChannelID(ctx, "@"+ref.value)
+	case channelRefUsername:
+		// A few legacy usernames were only kept as the channel's handle
+		channelID, err := h.youTubeClient.ResolveUsername(ctx, ref.value)
+		if !errors.Is(err, youtube.ErrChannelNotFound) {
+			return channelID, err
+		}
+		return h.youTubeClient.ResolveChannelID(ctx, "@"+ref.value)
+	case channelRefCustomURL:
+		// Custom URLs can't be looked up directly, but most became a handle of the same name and the rest
+		// usually match the legacy username
+		channelID, err := h.youTubeClient.ResolveChannelID(ctx, "@"+ref.value)
+		if !errors.Is(err, youtube.ErrChannelNotFound) {
+			return channelID, err
+		}
+		return h.youTubeClient.ResolveUsername(ctx, ref.value)
+	case channelRefVideo:
+		video, err := h.youTubeClient.GetVideoMetadata(ctx, ref.value)
+		if err != nil {
+			return "", err
+		}
+		if video.ChannelID == "" {
+			return "", fmt.Errorf("%w: video %s has no uploader", youtube.ErrChannelNotFound, ref.value)
+		}
+		return video.ChannelID, nil
+	default:
+		return "", fmt.Errorf("%w: unknown reference kind %d", ErrInvalidChannelReference, ref.kind)
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestParseChannelReference(t *testing.T) {
+	const channelID = "UCuAXFkgsw1L7xaCfnd5JJOw"
+	tests := []struct {
+		name    string
+		input   string
+		want    channelReference
+		wantErr error
+	}{
+		{name: "raw channel ID", input: channelID, want: channelReference{channelRefID, channelID}},
+		{name: "handle", input: "@name", want: channelReference{channelRefHandle, "name"}},
+		{name: "bare handle", input: " name.with-dots ", want: channelReference{channelRefHandle, "name.with-dots"}},
+		{
+			name:  "handle URL with tab",
+			input: "https://www.youtube.com/@name/videos",
+			want:  channelReference{channelRefHandle, "name"},
+		},
+		{name: "URL without scheme", input: "youtube.com/@name", want: channelReference{channelRefHandle, "name"}},
+		{
+			name:  "channel URL",
+			input: "https://www.youtube.com/channel/" + channelID + "/featured",
+			want:  channelReference{channelRefID, channelID},
+		},
+		{
+			name:  "mobile channel URL",
+			input: "https://m.youtube.com/channel/" + channelID,
+			want:  channelReference{channelRefID, channelID},
+		},
+		{
+			name:  "legacy custom URL",
+			input: "https://www.youtube.com/c/legacyname",
+			want:  channelReference{channelRefCustomURL, "legacyname"},
+		},
+		{
+			name:  "legacy vanity URL",
+			input: "https://www.youtube.com/legacyname",
+			want:  channelReference{channelRefCustomURL, "legacyname"},
+		},
+		{
+			name:  "legacy user URL",
+			input: "http://youtube.com/user/name/videos",
+			want:  channelReference{channelRefUsername, "name"},
+		},
+		{
+			name:  "watch URL",
+			input: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42",
+			want:  channelReference{channelRefVideo, "dQw4w9WgXcQ"},
+		},
+		{
+			name:  "short video URL",
+			input: "https://youtu.be/dQw4w9WgXcQ?si=abc",
+			want:  channelReference{channelRefVideo, "dQw4w9WgXcQ"},
+		},
+		{
+			name:  "shorts URL",
+			input: "https://www.youtube.com/shorts/dQw4w9WgXcQ",
+			want:  channelReference{channelRefVideo, "dQw4w9WgXcQ"},
+		},
+		{
+			name:  "music URL",
+			input: "https://music.youtube.com/watch?v=dQw4w9WgXcQ",
+			want:  channelReference{channelRefVideo, "dQw4w9WgXcQ"},
+		},
+		{name: "empty", input: "", wantErr: ErrInvalidChannelReference},
+		{name: "garbage", input: "not a channel", wantErr: ErrInvalidChannelReference},
+		{name: "other site", input: "https://vimeo.com/name", wantErr: ErrNotYouTubeURL},
+		{
+			name:    "invalid channel ID in URL",
+			input:   "https://www.youtube.com/channel/name",
+			wantErr: ErrInvalidChannelReference,
+		},
+		{name: "invalid video ID", input: "https://youtu.be/short", wantErr: ErrInvalidChannelReference},
+		{
+			name:    "playlist URL",
+			input:   "https://www.youtube.com/playlist?list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+			wantErr: ErrUnsupportedChannelURL,
+		},
+		{name: "home page", input: "https://www.youtube.com/", wantErr: ErrUnsupportedChannelURL},
+		{name: "feed page", input: "https://www.youtube.com/feed/subscriptions", wantErr: ErrUnsupportedChannelURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChannelReference(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveChannelReference(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handles := map[string]string{"@modern": "UChandle"}
+	usernames := map[string]string{"legacy": "UCusername"}
+	youTubeMock := &youtube_mock.ClientMock{
+		ResolveChannelIDFunc: func(ctx context.Context, handle string) (string, error) {
+			if id, ok := handles[handle]; ok {
+				return id, nil
+			}
+			return "", fmt.Errorf("%w: %s", youtube.ErrChannelNotFound, handle)
+		},
+		ResolveUsernameFunc: func(ctx context.Context, username string) (string, error) {
+			if id, ok := usernames[username]; ok {
+				return id, nil
+			}
+			return "", fmt.Errorf("%w: %s", youtube.ErrChannelNotFound, username)
+		},
+		GetVideoMetadataFunc: func(ctx context.Context, videoID string) (*models.Video, error) {
+			if videoID == "dQw4w9WgXcQ" {
+				return &models.Video{ID: videoID, ChannelID: "UCuploader"}, nil
+			}
+			return nil, fmt.Errorf("%w [%s]", youtube.ErrVideoNotFound, videoID)
+		},
+	}
+	h := New(l, nil, nil, youTubeMock, nil, nil, testConfig)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		ref     channelReference
+		want    string
+		wantErr error
+	}{
+		{name: "channel ID", ref: channelReference{channelRefID, "UCid"}, want: "UCid"},
+		{name: "handle", ref: channelReference{channelRefHandle, "modern"}, want: "UChandle"},
+		{name: "username", ref: channelReference{channelRefUsername, "legacy"}, want: "UCusername"},
+		{name: "username kept as handle", ref: channelReference{channelRefUsername, "modern"}, want: "UChandle"},
+		{name: "custom URL turned handle", ref: channelReference{channelRefCustomURL, "modern"}, want: "UChandle"},
+		{name: "custom URL of username", ref: channelReference{channelRefCustomURL, "legacy"}, want: "UCusername"},
+		{name: "video", ref: channelReference{channelRefVideo, "dQw4w9WgXcQ"}, want: "UCuploader"},
+		{name: "unknown handle", ref: channelReference{channelRefHandle, "gone"}, wantErr: youtube.ErrChannelNotFound},
+		{
+			name:    "unknown custom URL",
+			ref:     channelReference{channelRefCustomURL, "gone"},
+			wantErr: youtube.ErrChannelNotFound,
+		},
+		{name: "unknown video", ref: channelReference{channelRefVideo, "xxxxxxxxxxx"}, wantErr: youtube.ErrVideoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.resolveChannelReference(ctx, tt.ref)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,32 +3,63 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/TheEdgeOfRage/ytrssil-api/db"
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
+var (
+	ErrInvalidPlaylistID = errors.New("invalid playlist ID")
+	ErrSearchTooShort    = errors.New("search query is too short")
+)
+
+// minSearchLength is the shortest query channels are searched for, searches are too expensive in quota to send
+// one for every keystroke
+const minSearchLength = 3
+
+var playlistIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{12,}$`)
+
+// parsePlaylistID extracts the playlist ID from a raw ID or a playlist/watch URL with a list parameter.
+func parsePlaylistID(input string) (string, error) {
+	playlistID := strings.TrimSpace(input)
+	if strings.Contains(playlistID, "/") {
+		u, err := url.Parse(playlistID)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPlaylistID, err.Error())
+		}
+		playlistID = u.Query().Get("list")
+	}
+
+	if !playlistIDPattern.MatchString(playlistID) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlaylistID, input)
+	}
+
+	return playlistID, nil
+}
+
 // isChannelID reports whether s looks like a raw YouTube channel ID (UCxxxxxxxx…).
 func isChannelID(s string) bool {
 	return strings.HasPrefix(s, "UC") && len(s) == 24
 }
 
-func (h *handler) SubscribeToChannel(ctx context.Context, channelID string) (*models.Channel, error) {
-	if !isChannelID(channelID) {
-		// Treat input as a handle; normalise to @handle form for the API.
-		handle := channelID
-		if !strings.HasPrefix(handle, "@") {
-			handle = "@" + handle
-		}
-		resolved, err := h.youTubeClient.ResolveChannelID(ctx, handle)
-		if err != nil {
-			return nil, err
-		}
-		channelID = resolved
+// SubscribeToChannel subscribes to a YouTube channel given as anything parseChannelReference understands
+func (h *handler) SubscribeToChannel(ctx context.Context, reference string) (*models.Channel, error) {
+	ref, err := parseChannelReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	channelID, err := h.resolveChannelReference(ctx, ref)
+	if err != nil {
+		return nil, err
 	}
 
-	parsedChannel, err := h.parser.Parse(channelID)
+	parsedChannel, err := h.parser.Parse(ctx, models.Channel{ID: channelID, SourceType: models.SourceYouTube})
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +72,7 @@ func (h *handler) SubscribeToChannel(ctx context.Context, channelID string) (*mo
 
 	channel := models.Channel{
 		ID:           channelID,
+		SourceType:   models.SourceYouTube,
 		Name:         parsedChannel.Name,
 		Subscribed:   true,
 		ImageURL:     imageURL,
@@ -52,11 +84,138 @@ func (h *handler) SubscribeToChannel(ctx context.Context, channelID string) (*mo
 		return nil, err
 	}
 
+	if h.config.WebSubEnabled() {
+		go func() {
+			if err := h.requestWebSubSubscription(context.Background(), channelID); err != nil {
+				h.log.Error("Failed to request WebSub subscription", "channelID", channelID, "error", err)
+			}
+		}()
+	}
+
+	return &channel, nil
+}
+
+// SearchChannels searches YouTube for channels by name, marking the ones that are already subscribed to
+func (h *handler) SearchChannels(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minSearchLength {
+		return nil, fmt.Errorf("%w: at least %d characters are needed", ErrSearchTooShort, minSearchLength)
+	}
+
+	results, err := h.youTubeClient.SearchChannels(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed, err := h.db.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Subscribed = slices.ContainsFunc(subscribed, func(channel models.Channel) bool {
+			return channel.ID == results[i].ID
+		})
+	}
+
+	return results, nil
+}
+
+// SubscribeToPlaylist subscribes to a YouTube playlist. Its videos are attributed to the channels that
+// uploaded them.
+func (h *handler) SubscribeToPlaylist(ctx context.Context, playlist string) (*models.Channel, error) {
+	playlistID, err := parsePlaylistID(playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedChannel, err := h.parser.Parse(ctx, models.Channel{ID: playlistID, SourceType: models.SourceYouTubePlaylist})
+	if err != nil {
+		return nil, err
+	}
+
+	imageURL, err := h.youTubeClient.GetPlaylistImageURL(ctx, playlistID)
+	if err != nil {
+		h.log.Warn("Failed to fetch playlist image URL", "playlistID", playlistID, "error", err)
+		imageURL = ""
+	}
+
+	channel := models.Channel{
+		ID:           playlistID,
+		SourceType:   models.SourceYouTubePlaylist,
+		Name:         parsedChannel.Name,
+		Subscribed:   true,
+		ImageURL:     imageURL,
+		EnableShorts: true,
+	}
+
+	err = h.db.SubscribeToChannel(ctx, channel)
+	if err != nil && !errors.Is(err, db.ErrChannelExists) {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// SubscribeToFeed subscribes to a channel from a source other than YouTube. For PeerTube, feedURL can also be
+// the URL of the channel's or account's page.
+func (h *handler) SubscribeToFeed(ctx context.Context, sourceType string, feedURL string) (*models.Channel, error) {
+	switch sourceType {
+	case models.SourcePeerTube:
+		var err error
+		feedURL, err = feedparser.PeerTubeFeedURL(feedURL)
+		if err != nil {
+			return nil, err
+		}
+	case models.SourceGenericRSS:
+		u, err := url.Parse(feedURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("%w: %q is not a feed URL", feedparser.ErrInvalidFeedReference, feedURL)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", feedparser.ErrUnsupportedSource, sourceType)
+	}
+
+	channel := models.Channel{
+		ID:           feedparser.FeedChannelID(sourceType, feedURL),
+		SourceType:   sourceType,
+		FeedURL:      feedURL,
+		Subscribed:   true,
+		EnableShorts: true,
+	}
+	parsedChannel, err := h.parser.Parse(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	channel.Name = parsedChannel.Name
+	channel.SiteURL = parsedChannel.SiteURL
+	channel.ImageURL = parsedChannel.ImageURL
+	if channel.Name == "" {
+		channel.Name = feedURL
+	}
+
+	err = h.db.SubscribeToChannel(ctx, channel)
+	if err != nil && !errors.Is(err, db.ErrChannelExists) {
+		return nil, err
+	}
+
 	return &channel, nil
 }
 
 func (h *handler) UnsubscribeFromChannel(ctx context.Context, channelID string) error {
-	return h.db.UnsubscribeFromChannel(ctx, channelID)
+	err := h.db.UnsubscribeFromChannel(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	if h.config.WebSubEnabled() && isChannelID(channelID) {
+		go func() {
+			if err := h.requestWebSubUnsubscription(context.Background(), channelID); err != nil {
+				h.log.Error("Failed to request WebSub unsubscription", "channelID", channelID, "error", err)
+			}
+		}()
+	}
+
+	return nil
 }
 
 func (h *handler) ListChannels(ctx context.Context) ([]models.Channel, error) {
@@ -67,6 +226,46 @@ func (h *handler) GetChannelByID(ctx context.Context, channelID string) (*models
 	return h.db.GetChannelByID(ctx, channelID)
 }
 
+// ToggleChannelShorts enables or disables shorts for a channel and applies the change to its unwatched videos.
+// Disabling discards the shorts, enabling restores the ones that were discarded by the shorts filter, unless the
+// title filters discard them now.
 func (h *handler) ToggleChannelShorts(ctx context.Context, channelID string, enableShorts bool) error {
-	return h.db.ToggleChannelShorts(ctx, channelID, enableShorts)
+	err := h.db.ToggleChannelShorts(ctx, channelID, enableShorts)
+	if err != nil {
+		return err
+	}
+
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	// Enabling looks at the discarded videos and disabling at the ones still in the feed
+	videos, err := h.db.GetUnwatchedChannelVideos(ctx, channelID, enableShorts)
+	if err != nil {
+		return err
+	}
+
+	discardReason := h.discardFilter(*channel)
+	var errs []error
+	for _, video := range videos {
+		if !video.IsShort {
+			continue
+		}
+
+		reason := discardReason(video)
+		switch {
+		case enableShorts && video.DiscardReason == models.DiscardShorts && reason == models.NotDiscarded:
+			err = h.db.RestoreVideo(ctx, video.ID)
+		case !enableShorts && reason == models.DiscardShorts:
+			err = h.db.DiscardVideo(ctx, video.ID, models.DiscardShorts)
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestParsePlaylistID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "plain playlist ID",
+			input: "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+			want:  "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+		},
+		{
+			name:  "playlist URL",
+			input: "https://www.youtube.com/playlist?list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+			want:  "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+		},
+		{
+			name:  "watch URL inside a playlist",
+			input: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+			want:  "PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+		},
+		{
+			name:    "URL without list parameter",
+			input:   "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			input:   "not a playlist",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePlaylistID(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidPlaylistID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToggleChannelShortsAppliesToExistingVideos(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	enabled := true
+	discarded := map[string]models.DiscardReason{
+		"short":          models.NotDiscarded,
+		"video":          models.NotDiscarded,
+		"filteredShort":  models.DiscardTitleFilter,
+		"dismissedShort": models.DiscardDismissed,
+	}
+	dbMock := &db_mock.DBMock{
+		ToggleChannelShortsFunc: func(ctx context.Context, channelID string, enableShorts bool) error {
+			enabled = enableShorts
+			return nil
+		},
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, EnableShorts: enabled, TitleExclude: []string{"trailer"}}, nil
+		},
+		GetUnwatchedChannelVideosFunc: func(
+			ctx context.Context, channelID string, isDiscarded bool,
+		) ([]models.Video, error) {
+			all := []models.Video{
+				{ID: "short", Title: "Quick tip", IsShort: true},
+				{ID: "video", Title: "Full episode"},
+				{ID: "filteredShort", Title: "Trailer", IsShort: true},
+				{ID: "dismissedShort", Title: "Another tip", IsShort: true},
+			}
+			videos := make([]models.Video, 0)
+			for _, video := range all {
+				video.DiscardReason = discarded[video.ID]
+				if (video.DiscardReason != models.NotDiscarded) == isDiscarded {
+					videos = append(videos, video)
+				}
+			}
+			return videos, nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			discarded[videoID] = reason
+			return nil
+		},
+		RestoreVideoFunc: func(ctx context.Context, videoID string) error {
+			discarded[videoID] = models.NotDiscarded
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	require.NoError(t, h.ToggleChannelShorts(context.Background(), "UC123", false))
+	assert.Equal(t, map[string]models.DiscardReason{
+		"short":          models.DiscardShorts,
+		"video":          models.NotDiscarded,
+		"filteredShort":  models.DiscardTitleFilter,
+		"dismissedShort": models.DiscardDismissed,
+	}, discarded)
+
+	// Only the shorts discarded by the shorts filter come back
+	require.NoError(t, h.ToggleChannelShorts(context.Background(), "UC123", true))
+	assert.Equal(t, map[string]models.DiscardReason{
+		"short":          models.NotDiscarded,
+		"video":          models.NotDiscarded,
+		"filteredShort":  models.DiscardTitleFilter,
+		"dismissedShort": models.DiscardDismissed,
+	}, discarded)
+	assert.Len(t, dbMock.RestoreVideoCalls(), 1)
+}
+
+func TestSearchChannelsMarksSubscribed(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		ListChannelsFunc: func(ctx context.Context) ([]models.Channel, error) {
+			return []models.Channel{{ID: "UCsubscribed"}}, nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		SearchChannelsFunc: func(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+			assert.Equal(t, "cooking", query)
+			return []models.ChannelSearchResult{{ID: "UCsubscribed"}, {ID: "UCnew"}}, nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	results, err := h.SearchChannels(context.Background(), " cooking ")
+	require.NoError(t, err)
+	assert.Equal(t, []models.ChannelSearchResult{{ID: "UCsubscribed", Subscribed: true}, {ID: "UCnew"}}, results)
+
+	_, err = h.SearchChannels(context.Background(), "ab")
+	assert.ErrorIs(t, err, ErrSearchTooShort)
+	assert.Len(t, youTubeMock.SearchChannelsCalls(), 1)
+}
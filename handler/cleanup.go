@@ -25,6 +25,8 @@ func (h *handler) CleanupRoutine(ctx context.Context) {
 }
 
 func (h *handler) performCleanup(ctx context.Context) {
+	h.expireUnwatchedVideos(ctx)
+
 	videos, err := h.db.GetVideosForCleanup(ctx, h.config.CleanupAge)
 	if err != nil {
 		h.log.Error("Failed to get videos for cleanup", "error", err)
@@ -56,3 +58,19 @@ func (h *handler) performCleanup(ctx context.Context) {
 		h.log.Info("Cleanup completed", "files_deleted", len(videos))
 	}
 }
+
+// expireUnwatchedVideos discards the videos that stayed unwatched for longer than the configured expiry
+func (h *handler) expireUnwatchedVideos(ctx context.Context) {
+	if h.config.UnwatchedExpiry <= 0 {
+		return
+	}
+
+	expired, err := h.db.ExpireUnwatchedVideos(ctx, time.Now().Add(-h.config.UnwatchedExpiry))
+	if err != nil {
+		h.log.Error("Failed to expire unwatched videos", "error", err)
+		return
+	}
+	if expired > 0 {
+		h.log.Info("Expired unwatched videos", "count", expired)
+	}
+}
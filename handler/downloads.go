@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,8 @@ import (
 	"strings"
 )
 
+var ErrVideoUnavailable = errors.New("video is no longer available")
+
 func sanitizeFilename(title string) string {
 	title = strings.ReplaceAll(title, " ", "_")
 
@@ -31,6 +34,14 @@ func (h *handler) DownloadVideo(ctx context.Context, videoID string, resolution
 		return fmt.Errorf("video not found")
 	}
 
+	video, err := h.db.GetVideo(ctx, videoID)
+	if err != nil {
+		return fmt.Errorf("failed to get video: %w", err)
+	}
+	if video.IsUnavailable() {
+		return fmt.Errorf("%w: %s", ErrVideoUnavailable, video.UnavailableReason.Label())
+	}
+
 	if err := h.db.SetVideoDownloadStatus(ctx, videoID, "pending"); err != nil {
 		return fmt.Errorf("failed to set download status: %w", err)
 	}
@@ -59,7 +70,7 @@ func (h *handler) performDownload(videoID string, resolution string) {
 
 	h.log.Info("Starting video download", "video_id", videoID, "title", video.Title, "resolution", resolution)
 
-	filePath, err := h.downloader.Download(ctx, videoID, video.Title, h.config.DownloadsDir, resolution)
+	filePath, err := h.downloader.Download(ctx, videoID, video.SourceURL(), h.config.DownloadsDir, resolution)
 	if err != nil {
 		h.log.Error("Video download failed", "video_id", videoID, "error", err)
 		if dbErr := h.db.SetVideoDownloadFailed(ctx, videoID, err.Error()); dbErr != nil {
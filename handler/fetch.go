@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+var (
+	ErrFetchJobNotFound = errors.New("fetch job not found")
+	ErrFetchFailed      = errors.New("fetching every channel failed")
+)
+
+// finishedFetchJobsKept is how many finished fetch jobs can still be looked up
+const finishedFetchJobsKept = 10
+
+// fetchJobs runs at most one fetch of all channels and one import per channel at a time and keeps track of
+// their progress
+type fetchJobs struct {
+	// running is held while channels are being fetched, by a fetch job or a scheduled poll, so the two never
+	// overlap
+	running sync.Mutex
+
+	mu       sync.Mutex
+	current  *models.FetchJob
+	imports  map[string]*models.FetchJob
+	finished []*models.FetchJob
+}
+
+// update changes the job while holding the lock, so readers always see a consistent snapshot
+func (j *fetchJobs) update(job *models.FetchJob, change func(job *models.FetchJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	change(job)
+}
+
+// finish marks the job as done, keeping it around to be looked up for a while
+func (j *fetchJobs) finish(job *models.FetchJob, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if j.current == job {
+		j.current = nil
+	}
+	if job.Import != nil {
+		delete(j.imports, job.Import.ChannelID)
+	}
+	j.finished = append(j.finished, job)
+	if len(j.finished) > finishedFetchJobsKept {
+		j.finished = j.finished[1:]
+	}
+}
+
+// StartFetch starts fetching the feeds of all channels in the background and returns the new job. If a fetch
+// is already running, it's returned instead of starting another one.
+func (h *handler) StartFetch(ctx context.Context) models.FetchJob {
+	h.fetchJobs.mu.Lock()
+	defer h.fetchJobs.mu.Unlock()
+
+	if h.fetchJobs.current != nil {
+		return h.fetchJobs.current.Snapshot()
+	}
+
+	job := &models.FetchJob{
+		ID:        ulid.Make().String(),
+		StartedAt: time.Now(),
+	}
+	h.fetchJobs.current = job
+	// The job outlives the request that started it
+	go h.runFetchJob(context.WithoutCancel(ctx), job)
+
+	return job.Snapshot()
+}
+
+// GetFetchJob returns the current state of a running or recently finished fetch job
+func (h *handler) GetFetchJob(jobID string) (*models.FetchJob, error) {
+	h.fetchJobs.mu.Lock()
+	defer h.fetchJobs.mu.Unlock()
+
+	if h.fetchJobs.current != nil && h.fetchJobs.current.ID == jobID {
+		job := h.fetchJobs.current.Snapshot()
+		return &job, nil
+	}
+	for _, running := range h.fetchJobs.imports {
+		if running.ID == jobID {
+			job := running.Snapshot()
+			return &job, nil
+		}
+	}
+	for _, finished := range h.fetchJobs.finished {
+		if finished.ID == jobID {
+			job := finished.Snapshot()
+			return &job, nil
+		}
+	}
+
+	return nil, ErrFetchJobNotFound
+}
+
+func (h *handler) runFetchJob(ctx context.Context, job *models.FetchJob) {
+	h.fetchJobs.running.Lock()
+	defer h.fetchJobs.running.Unlock()
+
+	h.log.Info("Fetching new videos for all channels", "jobID", job.ID)
+	err := h.fetchAllChannels(ctx, job)
+	if err != nil {
+		h.log.Error("Failed to fetch videos", "jobID", job.ID, "error", err)
+	}
+
+	h.fetchJobs.finish(job, err)
+}
+
+// fetchAllChannels fetches the feeds of all channels, recording the progress in the job and scheduling the
+// next poll of every channel like a scheduled poll would. It fails if no channel could be fetched.
+func (h *handler) fetchAllChannels(ctx context.Context, job *models.FetchJob) error {
+	channels, err := h.db.ListChannelPollStates(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	h.fetchJobs.update(job, func(job *models.FetchJob) {
+		job.Channels = len(channels)
+	})
+
+	bounds := h.pollBounds()
+	failed := 0
+	runPool(ctx, h.config.FetchWorkers, channels, nil, func(state models.ChannelPollState) {
+		added, err := h.fetchChannel(ctx, state.Channel)
+		h.schedulePoll(ctx, state, bounds, err)
+		h.fetchJobs.update(job, func(job *models.FetchJob) {
+			job.ChannelsDone++
+			job.NewVideos += added
+			if err != nil {
+				failed++
+				job.Errors = append(job.Errors, models.FetchJobError{
+					ChannelID:   state.ID,
+					ChannelName: state.Name,
+					Error:       err.Error(),
+				})
+			}
+		})
+	})
+
+	h.recheckLiveVideos(ctx)
+
+	if failed > 0 && failed == len(channels) {
+		return ErrFetchFailed
+	}
+
+	return nil
+}
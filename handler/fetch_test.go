@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	parser_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestStartFetchJoinsRunningJob(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	release := make(chan struct{})
+	var mu sync.Mutex
+	scheduled := map[string]time.Time{}
+	dbMock := &db_mock.DBMock{
+		ListChannelPollStatesFunc: func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+			return []models.ChannelPollState{
+				{Channel: models.Channel{ID: "ok", Name: "Working", SourceType: models.SourceGenericRSS}},
+				{Channel: models.Channel{ID: "broken", Name: "Broken", SourceType: models.SourceGenericRSS}},
+			}, nil
+		},
+		SetChannelPollResultFunc: func(ctx context.Context, channelID string, nextPollAt time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			scheduled[channelID] = nextPollAt
+			return nil
+		},
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool { return false }),
+		AddVideosFunc:        addEach(func(video models.Video) {}),
+		RecordChannelFetchFunc: func(
+			ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int,
+		) error {
+			return nil
+		},
+		SetChannelFeedValidatorsFunc: func(ctx context.Context, channelID, etag, lastModified string) error {
+			return nil
+		},
+		GetLiveVideosFunc: func(ctx context.Context) ([]models.Video, error) {
+			return nil, nil
+		},
+	}
+	parserMock := &parser_mock.ParserMock{
+		ParseIfModifiedFunc: func(
+			ctx context.Context, channel models.Channel, validators feedparser.CacheValidators,
+		) (*feedparser.Channel, error) {
+			<-release
+			if channel.ID == "broken" {
+				return nil, errors.New("connection reset")
+			}
+			return &feedparser.Channel{
+				ID:         channel.ID,
+				SourceType: channel.SourceType,
+				Videos:     []models.Video{{ID: "v1"}, {ID: "v2"}},
+			}, nil
+		},
+	}
+	h := New(l, dbMock, parserMock, nil, nil, nil, testConfig)
+	ctx := context.Background()
+
+	first := h.StartFetch(ctx)
+	second := h.StartFetch(ctx)
+	assert.Equal(t, first.ID, second.ID)
+	assert.False(t, second.IsFinished())
+
+	close(release)
+	require.Eventually(t, func() bool {
+		job, err := h.GetFetchJob(first.ID)
+		return err == nil && job.IsFinished()
+	}, time.Second, 5*time.Millisecond)
+
+	job, err := h.GetFetchJob(first.ID)
+	require.NoError(t, err)
+	assert.Empty(t, job.Error)
+	assert.Equal(t, 2, job.Channels)
+	assert.Equal(t, 2, job.ChannelsDone)
+	assert.Equal(t, 2, job.NewVideos)
+	assert.Equal(t, []models.FetchJobError{
+		{ChannelID: "broken", ChannelName: "Broken", Error: "connection reset"},
+	}, job.Errors)
+	assert.Len(t, dbMock.ListChannelPollStatesCalls(), 1)
+	// Both channels are rescheduled, the failing one backing off further than the working one
+	require.Len(t, scheduled, 2)
+	assert.True(t, scheduled["broken"].After(scheduled["ok"]))
+
+	// Once the job is done, a new trigger starts a new one
+	third := h.StartFetch(ctx)
+	assert.NotEqual(t, first.ID, third.ID)
+}
+
+func TestFetchJobFailsWhenEveryChannelFails(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		ListChannelPollStatesFunc: func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+			return []models.ChannelPollState{
+				{Channel: models.Channel{ID: "broken", Name: "Broken", SourceType: models.SourceGenericRSS}},
+			}, nil
+		},
+		RecordChannelFetchFunc: func(
+			ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int,
+		) error {
+			return nil
+		},
+		SetChannelPollResultFunc: func(ctx context.Context, channelID string, nextPollAt time.Time) error {
+			return nil
+		},
+		GetLiveVideosFunc: func(ctx context.Context) ([]models.Video, error) {
+			return nil, nil
+		},
+	}
+	parserMock := &parser_mock.ParserMock{
+		ParseIfModifiedFunc: func(
+			ctx context.Context, channel models.Channel, validators feedparser.CacheValidators,
+		) (*feedparser.Channel, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+	h := New(l, dbMock, parserMock, nil, nil, nil, testConfig)
+
+	started := h.StartFetch(context.Background())
+	require.Eventually(t, func() bool {
+		job, err := h.GetFetchJob(started.ID)
+		return err == nil && job.IsFinished()
+	}, time.Second, 5*time.Millisecond)
+
+	job, err := h.GetFetchJob(started.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ErrFetchFailed.Error(), job.Error)
+	assert.Len(t, job.Errors, 1)
+	assert.Len(t, dbMock.SetChannelPollResultCalls(), 1)
+}
+
+func TestGetFetchJobNotFound(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := New(l, nil, nil, nil, nil, nil, testConfig)
+
+	_, err := h.GetFetchJob("missing")
+	assert.ErrorIs(t, err, ErrFetchJobNotFound)
+}
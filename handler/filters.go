@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+	"github.com/TheEdgeOfRage/ytrssil-api/titlefilter"
+)
+
+// discardFilter returns a function that tells whether a new video of the channel should be discarded because of
+// its title filters or shorts setting, and why
+func (h *handler) discardFilter(channel models.Channel) func(video models.Video) models.DiscardReason {
+	filter, err := titlefilter.New(channel.TitleInclude, channel.TitleExclude)
+	if err != nil {
+		// Patterns are validated when saved, so this only happens if they were edited by hand
+		h.log.Error("Ignoring invalid title filters", "channelID", channel.ID, "error", err)
+	}
+
+	return func(video models.Video) models.DiscardReason {
+		switch {
+		case !filter.Allows(video.Title):
+			return models.DiscardTitleFilter
+		case video.IsShort && !channel.EnableShorts:
+			return models.DiscardShorts
+		default:
+			return models.NotDiscarded
+		}
+	}
+}
+
+// PreviewTitleFilters returns the unwatched videos of a channel that the given title filters would discard
+func (h *handler) PreviewTitleFilters(
+	ctx context.Context, channelID string, include []string, exclude []string,
+) ([]models.Video, error) {
+	filter, err := titlefilter.New(titlefilter.Normalize(include), titlefilter.Normalize(exclude))
+	if err != nil {
+		return nil, err
+	}
+
+	videos, err := h.db.GetUnwatchedChannelVideos(ctx, channelID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	discarded := make([]models.Video, 0)
+	for _, video := range videos {
+		if !filter.Allows(video.Title) {
+			discarded = append(discarded, video)
+		}
+	}
+
+	return discarded, nil
+}
+
+// SetChannelTitleFilters saves the title filters of a channel and discards the unwatched videos they match,
+// as shown by PreviewTitleFilters. Videos discarded by the previous filters that pass the new ones are restored.
+// It returns the number of discarded videos.
+func (h *handler) SetChannelTitleFilters(
+	ctx context.Context, channelID string, include []string, exclude []string,
+) (int, error) {
+	include = titlefilter.Normalize(include)
+	exclude = titlefilter.Normalize(exclude)
+	discarded, err := h.PreviewTitleFilters(ctx, channelID, include, exclude)
+	if err != nil {
+		return 0, err
+	}
+
+	err = h.db.SetChannelTitleFilters(ctx, channelID, include, exclude)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, video := range discarded {
+		if err := h.db.DiscardVideo(ctx, video.ID, models.DiscardTitleFilter); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	count := len(discarded) - len(errs)
+
+	if err := h.restoreTitleFilteredVideos(ctx, channelID); err != nil {
+		errs = append(errs, err)
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// restoreTitleFilteredVideos brings back the unwatched videos of a channel that were discarded by its title
+// filters but pass the current ones. Videos that are now only discarded as shorts keep that reason instead.
+func (h *handler) restoreTitleFilteredVideos(ctx context.Context, channelID string) error {
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	videos, err := h.db.GetUnwatchedChannelVideos(ctx, channelID, true)
+	if err != nil {
+		return err
+	}
+
+	discardReason := h.discardFilter(*channel)
+	var errs []error
+	for _, video := range videos {
+		if video.DiscardReason != models.DiscardTitleFilter {
+			continue
+		}
+
+		switch reason := discardReason(video); reason {
+		case models.NotDiscarded:
+			err = h.db.RestoreVideo(ctx, video.ID)
+		case models.DiscardTitleFilter:
+			continue
+		default:
+			err = h.db.DiscardVideo(ctx, video.ID, reason)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+	"github.com/TheEdgeOfRage/ytrssil-api/titlefilter"
+)
+
+func TestAddVideosForChannelRecordsDiscardReason(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	discarded := map[string]models.DiscardReason{}
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return false
+		}),
+		AddVideosFunc: addEach(func(video models.Video) {
+			discarded[video.ID] = video.DiscardReason
+		}),
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	channel := models.Channel{
+		ID:           "UC123",
+		EnableShorts: false,
+		TitleInclude: []string{"^episode"},
+		TitleExclude: []string{"live"},
+	}
+	_, err := h.addVideosForChannel(context.Background(), channel, &feedparser.Channel{
+		ID:         "UC123",
+		SourceType: models.SourceYouTube,
+		Videos: []models.Video{
+			{ID: "kept", Title: "Episode 12"},
+			{ID: "excluded", Title: "Episode 13 (LIVE)"},
+			{ID: "notIncluded", Title: "Channel update"},
+			{ID: "short", Title: "Episode 12 teaser", IsShort: true},
+			{ID: "excludedShort", Title: "Episode 13 live teaser", IsShort: true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.DiscardReason{
+		"kept":          models.NotDiscarded,
+		"excluded":      models.DiscardTitleFilter,
+		"notIncluded":   models.DiscardTitleFilter,
+		"short":         models.DiscardShorts,
+		"excludedShort": models.DiscardTitleFilter,
+	}, discarded)
+}
+
+func TestSetChannelTitleFilters(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var saved [][]string
+	var discarded []string
+	dbMock := &db_mock.DBMock{
+		GetUnwatchedChannelVideosFunc: func(
+			ctx context.Context, channelID string, discarded bool,
+		) ([]models.Video, error) {
+			if discarded {
+				return nil, nil
+			}
+			return []models.Video{
+				{ID: "a", Title: "Weekly news"},
+				{ID: "b", Title: "Sponsored: weekly news"},
+			}, nil
+		},
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, TitleExclude: saved[1]}, nil
+		},
+		SetChannelTitleFiltersFunc: func(ctx context.Context, channelID string, include []string, exclude []string) error {
+			saved = [][]string{include, exclude}
+			return nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			assert.Equal(t, models.DiscardTitleFilter, reason)
+			discarded = append(discarded, videoID)
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+	ctx := context.Background()
+
+	_, err := h.SetChannelTitleFilters(ctx, "UC123", nil, []string{"(unclosed"})
+	require.ErrorIs(t, err, titlefilter.ErrInvalidPattern)
+	assert.Nil(t, saved)
+
+	preview, err := h.PreviewTitleFilters(ctx, "UC123", nil, []string{"sponsored"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Video{{ID: "b", Title: "Sponsored: weekly news"}}, preview)
+	assert.Empty(t, discarded)
+
+	count, err := h.SetChannelTitleFilters(ctx, "UC123", []string{"", " "}, []string{" sponsored "})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, [][]string{{}, {"sponsored"}}, saved)
+	assert.Equal(t, []string{"b"}, discarded)
+}
+
+func TestSetChannelTitleFiltersRestoresLoosened(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	channel := models.Channel{ID: "UC123", TitleExclude: []string{"live", "teaser"}}
+	reasons := map[string]models.DiscardReason{}
+	dbMock := &db_mock.DBMock{
+		GetUnwatchedChannelVideosFunc: func(
+			ctx context.Context, channelID string, discarded bool,
+		) ([]models.Video, error) {
+			if !discarded {
+				return []models.Video{{ID: "kept", Title: "Episode 12"}}, nil
+			}
+			return []models.Video{
+				{ID: "live", Title: "Episode 13 live", DiscardReason: models.DiscardTitleFilter},
+				{ID: "teaser", Title: "Episode 13 teaser", DiscardReason: models.DiscardTitleFilter},
+				{ID: "liveShort", Title: "Live short", IsShort: true, DiscardReason: models.DiscardTitleFilter},
+				{ID: "dismissed", Title: "Episode 11 live", DiscardReason: models.DiscardDismissed},
+			}, nil
+		},
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			c := channel
+			return &c, nil
+		},
+		SetChannelTitleFiltersFunc: func(ctx context.Context, channelID string, include []string, exclude []string) error {
+			channel.TitleInclude = include
+			channel.TitleExclude = exclude
+			return nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			reasons[videoID] = reason
+			return nil
+		},
+		RestoreVideoFunc: func(ctx context.Context, videoID string) error {
+			reasons[videoID] = models.NotDiscarded
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	count, err := h.SetChannelTitleFilters(context.Background(), "UC123", nil, []string{"teaser"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, map[string]models.DiscardReason{
+		"live":      models.NotDiscarded,
+		"liveShort": models.DiscardShorts,
+	}, reasons)
+}
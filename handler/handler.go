@@ -7,27 +7,51 @@ import (
 	"github.com/TheEdgeOfRage/ytrssil-api/config"
 	"github.com/TheEdgeOfRage/ytrssil-api/db"
 	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/avatars"
 	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
 	"github.com/TheEdgeOfRage/ytrssil-api/lib/downloader"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/websub"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 type Handler interface {
-	SubscribeToChannel(ctx context.Context, channelID string) (*models.Channel, error)
+	SubscribeToChannel(ctx context.Context, reference string) (*models.Channel, error)
+	SubscribeToPlaylist(ctx context.Context, playlist string) (*models.Channel, error)
+	SubscribeToFeed(ctx context.Context, sourceType string, feedURL string) (*models.Channel, error)
+	SearchChannels(ctx context.Context, query string) ([]models.ChannelSearchResult, error)
 	UnsubscribeFromChannel(ctx context.Context, channelID string) error
 	ListChannels(ctx context.Context) ([]models.Channel, error)
 	GetChannelByID(ctx context.Context, channelID string) (*models.Channel, error)
 	ToggleChannelShorts(ctx context.Context, channelID string, enableShorts bool) error
-	GetNewVideos(ctx context.Context, sortDesc bool) ([]models.Video, error)
+	StartImport(ctx context.Context, channelID string, from string, to string, markWatched bool) (models.FetchJob, error)
+	PreviewTitleFilters(
+		ctx context.Context, channelID string, include []string, exclude []string,
+	) ([]models.Video, error)
+	SetChannelTitleFilters(ctx context.Context, channelID string, include []string, exclude []string) (int, error)
+	SetChannelPollInterval(ctx context.Context, channelID string, minInterval, maxInterval string) error
+	GetNewVideos(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error)
 	GetWatchedVideos(ctx context.Context, sortDesc bool, page int) ([]models.Video, error)
-	FetchVideos(ctx context.Context) error
+	GetDiscardedVideos(ctx context.Context, page int) ([]models.Video, error)
+	ServeChannelAvatar(ctx context.Context, channelID string) (filePath string, imageURL string, err error)
+	StartFetch(ctx context.Context) models.FetchJob
+	GetFetchJob(jobID string) (*models.FetchJob, error)
+	GetQuotaUsage(ctx context.Context) models.QuotaUsage
 	MarkVideoAsWatched(ctx context.Context, videoID string) error
 	MarkVideoAsUnwatched(ctx context.Context, videoID string) error
+	DismissVideo(ctx context.Context, videoID string) error
+	SetVideoShortOverride(ctx context.Context, videoID string, isShort bool) (*models.Video, error)
+	RestoreVideo(ctx context.Context, videoID string) error
 	SetVideoProgress(ctx context.Context, videoID string, progressTime string) (*models.Video, error)
 	AddCustomVideo(ctx context.Context, videoID string) error
 	DownloadVideo(ctx context.Context, videoID string, resolution string) error
 	ServeVideoFile(ctx context.Context, videoID string) (filePath string, filename string, err error)
 	CleanupRoutine(ctx context.Context)
+	AvailabilityRoutine(ctx context.Context)
+	ChannelMetadataRoutine(ctx context.Context)
+	PollRoutine(ctx context.Context)
+	VerifyWebSubIntent(ctx context.Context, channelID string, mode string, topic string, leaseSeconds int) error
+	HandleWebSubNotification(ctx context.Context, channelID string, signature string, body []byte) error
+	WebSubRoutine(ctx context.Context)
 }
 
 type handler struct {
@@ -36,7 +60,10 @@ type handler struct {
 	parser        feedparser.Parser
 	youTubeClient youtube.Client
 	downloader    downloader.Downloader
+	webSub        websub.Client
 	config        config.Config
+	fetchJobs     *fetchJobs
+	avatars       *avatars.Cache
 }
 
 func New(
@@ -45,6 +72,7 @@ func New(
 	parser feedparser.Parser,
 	youTubeClient youtube.Client,
 	downloader downloader.Downloader,
+	webSub websub.Client,
 	cfg config.Config,
 ) *handler {
 	return &handler{
@@ -53,6 +81,9 @@ func New(
 		parser:        parser,
 		youTubeClient: youTubeClient,
 		downloader:    downloader,
+		webSub:        webSub,
 		config:        cfg,
+		fetchJobs:     &fetchJobs{},
+		avatars:       avatars.NewCache(cfg.AvatarsDir),
 	}
 }
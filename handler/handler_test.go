@@ -22,12 +22,42 @@ func init() {
 	testConfig = config.TestConfig()
 }
 
+// trustFeedHint classifies videos as shorts exactly when the feed says so
+func trustFeedHint(ctx context.Context, videoID string, feedHint bool, durationSeconds int) (bool, models.ShortSignal) {
+	return feedHint, models.ShortSignalFeed
+}
+
+// knownIDs looks up known videos one ID at a time
+func knownIDs(isKnown func(videoID string) bool) func(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+	return func(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+		known := make(map[string]bool)
+		for _, id := range videoIDs {
+			if isKnown(id) {
+				known[id] = true
+			}
+		}
+		return known, nil
+	}
+}
+
+// addEach adds videos one at a time, reporting all of them as added
+func addEach(add func(video models.Video)) func(ctx context.Context, videos []models.Video) ([]string, error) {
+	return func(ctx context.Context, videos []models.Video) ([]string, error) {
+		ids := make([]string, 0, len(videos))
+		for _, video := range videos {
+			add(video)
+			ids = append(ids, video.ID)
+		}
+		return ids, nil
+	}
+}
+
 func TestGetNewVideos(t *testing.T) {
 	l := slog.New(slog.NewTextHandler(io.Discard, nil))
 	handler := New(
 		l,
 		&db_mock.DBMock{
-			GetNewVideosFunc: func(ctx context.Context, _ bool) ([]models.Video, error) {
+			GetNewVideosFunc: func(ctx context.Context, _ models.VideoSort, _ bool) ([]models.Video, error) {
 				return []models.Video{
 					{
 						ID:            "test",
@@ -41,9 +71,10 @@ func TestGetNewVideos(t *testing.T) {
 		&parser_mock.ParserMock{},
 		&youtube_mock.ClientMock{},
 		nil,
+		nil,
 		testConfig,
 	)
-	resp, err := handler.GetNewVideos(context.TODO(), false)
+	resp, err := handler.GetNewVideos(context.TODO(), models.SortPublished, false)
 
 	if assert.NoError(t, err) {
 		if assert.NotNil(t, resp) {
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// goneAfterNotFound is how many fetches in a row must return 404 before a channel is flagged as gone
+const goneAfterNotFound = 3
+
+// recordFetch updates the fetch health of a channel with the outcome of fetching its feed
+func (h *handler) recordFetch(ctx context.Context, channelID string, fetchErr error, newVideos int) {
+	outcome := models.FetchOutcome{NewVideos: newVideos}
+	if fetchErr != nil {
+		outcome.Error = fetchErr.Error()
+		outcome.NotFound = errors.Is(fetchErr, feedparser.ErrInvalidChannelID)
+	}
+
+	err := h.db.RecordChannelFetch(ctx, channelID, outcome, goneAfterNotFound)
+	if err != nil {
+		h.log.Error("Failed to record channel fetch", "channelID", channelID, "error", err)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestIngestParseResultRecordsFetch(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var outcomes []models.FetchOutcome
+	dbMock := &db_mock.DBMock{
+		RecordChannelFetchFunc: func(
+			ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int,
+		) error {
+			assert.Equal(t, goneAfterNotFound, goneAfter)
+			outcomes = append(outcomes, outcome)
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+	ctx := context.Background()
+
+	notFound := fmt.Errorf("%w: https://example.com/feed", feedparser.ErrInvalidChannelID)
+	channel := models.Channel{ID: "UC123"}
+	_, _ = h.ingestParseResult(ctx, parseResult{channel: channel, err: notFound})
+	_, _ = h.ingestParseResult(ctx, parseResult{channel: channel, err: errors.New("connection reset")})
+	_, _ = h.ingestParseResult(ctx, parseResult{channel: channel, err: feedparser.ErrNotModified})
+
+	assert.Equal(t, []models.FetchOutcome{
+		{Error: notFound.Error(), NotFound: true},
+		{Error: "connection reset"},
+		{},
+	}, outcomes)
+}
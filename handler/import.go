@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+var (
+	ErrInvalidImportRange = errors.New("invalid import date range")
+	ErrImportUnsupported  = errors.New("back catalog import is only supported for YouTube channels")
+)
+
+// durationBatchSize is the number of videos whose durations are looked up before they're stored
+const durationBatchSize = youtube.VideosPageSize
+
+// parseImportRange parses an inclusive date range in the YYYY-MM-DD format into [after, before). Empty dates
+// leave the range open.
+func parseImportRange(from string, to string) (time.Time, time.Time, error) {
+	var after, before time.Time
+	var err error
+	if from != "" {
+		after, err = time.Parse(time.DateOnly, from)
+		if err != nil {
+			return after, before, fmt.Errorf("%w: %v", ErrInvalidImportRange, err.Error())
+		}
+	}
+	if to != "" {
+		before, err = time.Parse(time.DateOnly, to)
+		if err != nil {
+			return after, before, fmt.Errorf("%w: %v", ErrInvalidImportRange, err.Error())
+		}
+		before = before.AddDate(0, 0, 1)
+	}
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return after, before, fmt.Errorf("%w: start date is after end date", ErrInvalidImportRange)
+	}
+
+	return after, before, nil
+}
+
+// addUploads adds the uploads of a channel that aren't known yet, looking up their durations and live status
+// in batches. Videos marked as watched get their publish time as the watch time, so they don't crowd the top
+// of the watch history. It returns the number of imported and skipped videos.
+func (h *handler) addUploads(
+	ctx context.Context, channel models.Channel, uploads []models.Video, markWatched bool,
+) (int, int, error) {
+	ids := make([]string, 0, len(uploads))
+	for _, upload := range uploads {
+		ids = append(ids, upload.ID)
+	}
+	known, err := h.db.GetKnownVideoIDs(ctx, ids)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newVideos := make(map[string]*models.Video, len(uploads))
+	for _, upload := range uploads {
+		if !known[upload.ID] {
+			video := upload
+			newVideos[video.ID] = &video
+		}
+	}
+	skipped := len(uploads) - len(newVideos)
+
+	imported := 0
+	discardReason := h.discardFilter(channel)
+	for batch := range slices.Chunk(slices.Sorted(maps.Keys(newVideos)), durationBatchSize) {
+		videos := make(map[string]*models.Video, len(batch))
+		for _, id := range batch {
+			videos[id] = newVideos[id]
+		}
+		err := h.youTubeClient.GetVideoDurations(ctx, videos)
+		if err := h.deferFailedDurations(err, videos, channel.ID); err != nil {
+			return imported, skipped, err
+		}
+		h.classifyShorts(ctx, videos)
+
+		newBatch := make([]models.Video, 0, len(videos))
+		for _, id := range batch {
+			video := videos[id]
+			video.ChannelID = channel.ID
+			video.DiscardReason = discardReason(*video)
+			video.IsDiscarded = video.DiscardReason != models.NotDiscarded
+			newBatch = append(newBatch, *video)
+		}
+		// Videos added since they were checked, e.g. by a concurrent WebSub notification, are skipped
+		added, err := h.db.AddVideos(ctx, newBatch)
+		if err != nil {
+			return imported, skipped, err
+		}
+		imported += len(added)
+		skipped += len(newBatch) - len(added)
+
+		if markWatched {
+			watchTimes := make(map[string]time.Time, len(added))
+			for _, id := range added {
+				watchTimes[id] = videos[id].PublishedTime
+			}
+			if err := h.db.SetVideoWatchTimes(ctx, watchTimes); err != nil {
+				return imported, skipped, err
+			}
+		}
+	}
+
+	return imported, skipped, nil
+}
+
+// StartImport starts adding the uploads of a channel published between from and to (inclusive, YYYY-MM-DD,
+// either may be empty), which the feed doesn't go back far enough to include, in the background and returns the
+// new job. Listing a long back catalog takes a while and spends quota, so if the channel is already being
+// imported, that job is returned instead of starting another one.
+func (h *handler) StartImport(
+	ctx context.Context, channelID string, from string, to string, markWatched bool,
+) (models.FetchJob, error) {
+	after, before, err := parseImportRange(from, to)
+	if err != nil {
+		return models.FetchJob{}, err
+	}
+
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return models.FetchJob{}, err
+	}
+	if channel.SourceType != models.SourceYouTube {
+		return models.FetchJob{}, ErrImportUnsupported
+	}
+
+	h.fetchJobs.mu.Lock()
+	defer h.fetchJobs.mu.Unlock()
+
+	if running, ok := h.fetchJobs.imports[channelID]; ok {
+		return running.Snapshot(), nil
+	}
+
+	job := &models.FetchJob{
+		ID:        ulid.Make().String(),
+		StartedAt: time.Now(),
+		Channels:  1,
+		Import: &models.ImportReport{
+			ChannelID:     channelID,
+			MarkedWatched: markWatched,
+		},
+	}
+	if h.fetchJobs.imports == nil {
+		h.fetchJobs.imports = make(map[string]*models.FetchJob)
+	}
+	h.fetchJobs.imports[channelID] = job
+	// The job outlives the request that started it
+	go h.runImportJob(context.WithoutCancel(ctx), job, *channel, after, before)
+
+	return job.Snapshot(), nil
+}
+
+func (h *handler) runImportJob(
+	ctx context.Context, job *models.FetchJob, channel models.Channel, after time.Time, before time.Time,
+) {
+	// Imports wait for fetches and polls, so the same uploads aren't added twice at once
+	h.fetchJobs.running.Lock()
+	defer h.fetchJobs.running.Unlock()
+
+	h.log.Info("Importing back catalog", "jobID", job.ID, "channelID", channel.ID)
+	err := h.importBackCatalog(ctx, job, channel, after, before)
+	if err != nil {
+		h.log.Error("Failed to import back catalog", "jobID", job.ID, "channelID", channel.ID, "error", err)
+	}
+
+	h.fetchJobs.finish(job, err)
+}
+
+// importBackCatalog adds the uploads of the channel published in [after, before), recording the progress in
+// the job
+func (h *handler) importBackCatalog(
+	ctx context.Context, job *models.FetchJob, channel models.Channel, after time.Time, before time.Time,
+) error {
+	uploads, err := h.youTubeClient.ListUploads(ctx, channel.ID, after, before)
+	if err != nil {
+		return err
+	}
+	h.fetchJobs.update(job, func(job *models.FetchJob) {
+		job.Import.Found = len(uploads)
+	})
+
+	imported, skipped, err := h.addUploads(ctx, channel, uploads, job.Import.MarkedWatched)
+	h.fetchJobs.update(job, func(job *models.FetchJob) {
+		job.ChannelsDone = 1
+		job.NewVideos = imported
+		job.Import.Imported = imported
+		job.Import.Skipped = skipped
+	})
+	h.log.Info(
+		"Imported back catalog",
+		"channelID", channel.ID,
+		"found", len(uploads),
+		"imported", imported,
+		"skipped", skipped,
+	)
+
+	return err
+}
+
+// backfillGap adds the uploads that fell out of the feed window between polls, e.g. after downtime or a burst
+// of uploads. It is called when none of the feed's entries were known, and does nothing for channels without
+// any history before the oldest entry. It returns the number of backfilled videos.
+func (h *handler) backfillGap(ctx context.Context, channel models.Channel, feedVideos []models.Video) int {
+	if len(feedVideos) == 0 {
+		return 0
+	}
+
+	oldest := feedVideos[0].PublishedTime
+	for _, video := range feedVideos[1:] {
+		if video.PublishedTime.Before(oldest) {
+			oldest = video.PublishedTime
+		}
+	}
+
+	lastKnown, err := h.db.GetLastPublishedBefore(ctx, channel.ID, oldest)
+	if err != nil {
+		h.log.Error("Failed to get last known video for gap detection", "channelID", channel.ID, "error", err)
+		return 0
+	}
+	if lastKnown == nil {
+		return 0
+	}
+
+	uploads, err := h.youTubeClient.ListUploads(ctx, channel.ID, *lastKnown, oldest)
+	if isQuotaError(err) {
+		h.log.Info("Skipping backfill until the quota resets", "channelID", channel.ID, "error", err)
+		return 0
+	}
+	if errors.Is(err, youtube.ErrNotFound) {
+		// Channels without any public uploads have no uploads playlist
+		h.log.Info("No uploads to backfill", "channelID", channel.ID)
+		return 0
+	}
+	if err != nil {
+		h.log.Error("Failed to list uploads for backfill", "channelID", channel.ID, "error", err)
+		return 0
+	}
+
+	imported, skipped, err := h.addUploads(ctx, channel, uploads, false)
+	h.log.Info(
+		"Backfill report",
+		"channelID", channel.ID,
+		"from", *lastKnown,
+		"to", oldest,
+		"found", len(uploads),
+		"imported", imported,
+		"skipped", skipped,
+		"error", err,
+	)
+
+	return imported
+}
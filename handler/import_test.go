@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestParseImportRange(t *testing.T) {
+	after, before, err := parseImportRange("2024-01-01", "2024-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), after)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), before)
+
+	after, before, err = parseImportRange("", "")
+	require.NoError(t, err)
+	assert.True(t, after.IsZero())
+	assert.True(t, before.IsZero())
+
+	_, _, err = parseImportRange("2024-02-01", "2024-01-01")
+	assert.ErrorIs(t, err, ErrInvalidImportRange)
+	_, _, err = parseImportRange("yesterday", "")
+	assert.ErrorIs(t, err, ErrInvalidImportRange)
+}
+
+func TestImportBackCatalog(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	published := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	uploads := make([]models.Video, 0, 60)
+	for i := range 60 {
+		uploads = append(uploads, models.Video{ID: fmt.Sprintf("video%02d", i), PublishedTime: published})
+	}
+
+	var added []string
+	watched := map[string]time.Time{}
+	dbMock := &db_mock.DBMock{
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, SourceType: models.SourceYouTube, EnableShorts: true}, nil
+		},
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return videoID == "video00"
+		}),
+		AddVideosFunc: addEach(func(video models.Video) {
+			assert.Equal(t, "UC123", video.ChannelID)
+			assert.Equal(t, 60, video.DurationSeconds)
+			added = append(added, video.ID)
+		}),
+		SetVideoWatchTimesFunc: func(ctx context.Context, watchTimes map[string]time.Time) error {
+			maps.Copy(watched, watchTimes)
+			return nil
+		},
+	}
+	var batchSizes []int
+	release := make(chan struct{})
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		ListUploadsFunc: func(
+			ctx context.Context, channelID string, after time.Time, before time.Time,
+		) ([]models.Video, error) {
+			<-release
+			assert.Equal(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), after)
+			assert.True(t, before.IsZero())
+			return uploads, nil
+		},
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			batchSizes = append(batchSizes, len(videos))
+			for _, video := range videos {
+				video.DurationSeconds = 60
+			}
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	ctx := context.Background()
+	first, err := h.StartImport(ctx, "UC123", "2019-01-01", "", true)
+	require.NoError(t, err)
+	// Importing the same channel again joins the running import instead of listing the uploads twice
+	second, err := h.StartImport(ctx, "UC123", "2019-01-01", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.False(t, second.IsFinished())
+
+	close(release)
+	require.Eventually(t, func() bool {
+		job, err := h.GetFetchJob(first.ID)
+		return err == nil && job.IsFinished()
+	}, time.Second, 5*time.Millisecond)
+
+	job, err := h.GetFetchJob(first.ID)
+	require.NoError(t, err)
+	assert.Empty(t, job.Error)
+	assert.Equal(t, 59, job.NewVideos)
+	assert.Len(t, youTubeMock.ListUploadsCalls(), 1)
+	assert.Equal(t, &models.ImportReport{
+		ChannelID:     "UC123",
+		Found:         60,
+		Imported:      59,
+		Skipped:       1,
+		MarkedWatched: true,
+	}, job.Import)
+	assert.Equal(t, []int{50, 9}, batchSizes)
+	assert.Len(t, added, 59)
+	assert.Len(t, watched, 59)
+	assert.Len(t, dbMock.AddVideosCalls(), 2)
+	assert.Len(t, dbMock.SetVideoWatchTimesCalls(), 2)
+	assert.Equal(t, published, watched["video01"])
+}
+
+func TestImportBackCatalogUnsupportedSource(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, SourceType: models.SourceYouTubePlaylist}, nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	_, err := h.StartImport(context.Background(), "PL123", "", "", false)
+	assert.ErrorIs(t, err, ErrImportUnsupported)
+}
+
+func TestIngestParseResultBackfillsGap(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	lastKnown := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldestEntry := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	known := map[string]bool{"known": true}
+	var outcome models.FetchOutcome
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return known[videoID]
+		}),
+		AddVideosFunc: addEach(func(video models.Video) {
+			known[video.ID] = true
+		}),
+		GetLastPublishedBeforeFunc: func(ctx context.Context, channelID string, before time.Time) (*time.Time, error) {
+			assert.Equal(t, oldestEntry, before)
+			return &lastKnown, nil
+		},
+		RecordChannelFetchFunc: func(
+			ctx context.Context, channelID string, o models.FetchOutcome, goneAfter int,
+		) error {
+			outcome = o
+			return nil
+		},
+		SetChannelFeedValidatorsFunc: func(ctx context.Context, channelID string, etag string, lastModified string) error {
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return nil
+		},
+		ListUploadsFunc: func(
+			ctx context.Context, channelID string, after time.Time, before time.Time,
+		) ([]models.Video, error) {
+			assert.Equal(t, lastKnown, after)
+			assert.Equal(t, oldestEntry, before)
+			return []models.Video{{ID: "gap1"}, {ID: "gap2"}, {ID: "known"}}, nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	added, err := h.ingestParseResult(context.Background(), parseResult{
+		channel: models.Channel{ID: "UC123", SourceType: models.SourceYouTube, EnableShorts: true},
+		parsed: &feedparser.Channel{
+			ID:         "UC123",
+			SourceType: models.SourceYouTube,
+			Videos: []models.Video{
+				{ID: "new1", PublishedTime: oldestEntry.Add(time.Hour)},
+				{ID: "new2", PublishedTime: oldestEntry},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, known["gap1"])
+	assert.True(t, known["gap2"])
+	assert.Equal(t, 4, outcome.NewVideos)
+	assert.Equal(t, 4, added)
+	assert.Len(t, youTubeMock.ListUploadsCalls(), 1)
+
+	// A feed that overlaps with known videos has no gap
+	_, err = h.ingestParseResult(context.Background(), parseResult{
+		channel: models.Channel{ID: "UC123", SourceType: models.SourceYouTube, EnableShorts: true},
+		parsed: &feedparser.Channel{
+			ID:         "UC123",
+			SourceType: models.SourceYouTube,
+			Videos:     []models.Video{{ID: "new3"}, {ID: "new1"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, youTubeMock.ListUploadsCalls(), 1)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// isQuotaError reports whether a YouTube API call was refused because the day's quota ran out, which isn't
+// worth logging as an error since the work is picked up again after the reset
+func isQuotaError(err error) bool {
+	return errors.Is(err, quota.ErrBudgetExhausted) || errors.Is(err, quota.ErrQuotaExceeded)
+}
+
+// isTransientError reports whether a YouTube API call failed for a reason that usually goes away by itself
+func isTransientError(err error) bool {
+	return errors.Is(err, youtube.ErrRateLimited) || errors.Is(err, youtube.ErrUnavailable)
+}
+
+// isAPIUnusable reports whether a YouTube API call failed for a reason that fails the following calls too,
+// until the quota resets, the API recovers or the key is fixed
+func isAPIUnusable(err error) bool {
+	return isQuotaError(err) || isTransientError(err) || errors.Is(err, youtube.ErrInvalidAPIKey)
+}
+
+// durationErrors returns why the durations of the videos couldn't be looked up by video ID, given the error
+// GetVideoDurations returned
+func durationErrors(err error, videos map[string]*models.Video) youtube.VideoErrors {
+	if err == nil {
+		return nil
+	}
+	var failed youtube.VideoErrors
+	if errors.As(err, &failed) {
+		return failed
+	}
+
+	failed = make(youtube.VideoErrors, len(videos))
+	for id := range videos {
+		failed[id] = err
+	}
+	return failed
+}
+
+// deferFailedDurations handles the videos whose durations couldn't be looked up, given the error
+// GetVideoDurations returned. Videos that can be looked up later are marked as pending and videos with invalid
+// details are kept without a duration. Any other failure is returned, and the videos shouldn't be stored then.
+func (h *handler) deferFailedDurations(err error, videos map[string]*models.Video, channelID string) error {
+	deferred := 0
+	for id, videoErr := range durationErrors(err, videos) {
+		switch {
+		case errors.Is(videoErr, youtube.ErrInvalidDuration):
+			h.log.Warn("Adding video without its duration", "videoID", id, "error", videoErr)
+		case isAPIUnusable(videoErr):
+			videos[id].DurationPending = true
+			deferred++
+		default:
+			return videoErr
+		}
+	}
+	if deferred == 0 {
+		return nil
+	}
+
+	switch {
+	case isQuotaError(err):
+		h.log.Info("Queueing duration lookups until the quota resets", "channelID", channelID, "videos", deferred)
+	case errors.Is(err, youtube.ErrInvalidAPIKey):
+		h.log.Error(
+			"Queueing duration lookups, the YouTube API key was rejected",
+			"channelID", channelID,
+			"videos", deferred,
+			"error", err,
+		)
+	default:
+		h.log.Warn(
+			"Queueing duration lookups until the YouTube API recovers",
+			"channelID", channelID,
+			"videos", deferred,
+			"error", err,
+		)
+	}
+
+	return nil
+}
+
+func (h *handler) GetQuotaUsage(ctx context.Context) models.QuotaUsage {
+	return h.youTubeClient.QuotaUsage(ctx)
+}
+
+// lookupPendingDurations looks up the durations of videos that were added while the YouTube API couldn't be used
+// and reclassifies them as shorts where only the duration could tell. It stops once the API fails again and
+// leaves the rest for the next run.
+func (h *handler) lookupPendingDurations(ctx context.Context) {
+	pending, err := h.db.GetVideosPendingDuration(ctx)
+	if err != nil {
+		h.log.Error("Failed to get videos pending duration", "call", "db.GetVideosPendingDuration", "error", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	channels := make(map[string]*models.Channel)
+	updated := 0
+	for batch := range slices.Chunk(pending, durationBatchSize) {
+		videos := make(map[string]*models.Video, len(batch))
+		for i := range batch {
+			videos[batch[i].ID] = &batch[i]
+		}
+		lookupErr := h.youTubeClient.GetVideoDurations(ctx, videos)
+		switch {
+		case lookupErr == nil || isQuotaError(lookupErr):
+			// Videos refused for lack of quota are picked up again after the reset
+		case isTransientError(lookupErr):
+			h.log.Warn("Postponing pending video durations", "call", "youtube.GetVideoDurations", "error", lookupErr)
+		default:
+			h.log.Error("Failed to get pending video durations", "call", "youtube.GetVideoDurations", "error", lookupErr)
+		}
+
+		failed := durationErrors(lookupErr, videos)
+		for _, video := range videos {
+			// Videos with invalid details are stored without a duration, so they aren't looked up forever
+			if err := failed[video.ID]; err != nil && !errors.Is(err, youtube.ErrInvalidDuration) {
+				continue
+			}
+			// Feed hints, probes and overrides don't depend on the duration
+			if video.ShortSignal == models.ShortSignalDuration {
+				video.IsShort, video.ShortSignal = h.youTubeClient.ClassifyShort(
+					ctx, video.ID, false, video.DurationSeconds,
+				)
+			}
+			if err := h.db.SetVideoDuration(ctx, *video); err != nil {
+				h.log.Error("Failed to set video duration", "videoID", video.ID, "error", err)
+				continue
+			}
+			updated++
+
+			if video.IsDiscarded || video.WatchTime != nil || !video.IsShort {
+				continue
+			}
+			channel, ok := channels[video.ChannelID]
+			if !ok {
+				channel, err = h.db.GetChannelByID(ctx, video.ChannelID)
+				if err != nil {
+					h.log.Error("Failed to get channel of video", "videoID", video.ID, "error", err)
+					continue
+				}
+				channels[video.ChannelID] = channel
+			}
+			if h.discardFilter(*channel)(*video) == models.DiscardShorts {
+				err := h.db.DiscardVideo(ctx, video.ID, models.DiscardShorts)
+				if err != nil {
+					h.log.Error("Failed to discard short", "videoID", video.ID, "error", err)
+				}
+			}
+		}
+		if isAPIUnusable(lookupErr) {
+			break
+		}
+	}
+
+	h.log.Info("Looked up pending video durations", "pending", len(pending), "updated", updated)
+}
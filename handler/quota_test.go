@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestAddVideosForChannelDefersDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "quota exhausted", err: fmt.Errorf("%w: videos.list needs 1 units", quota.ErrBudgetExhausted)},
+		{name: "API unavailable", err: fmt.Errorf("%w: backend error", youtube.ErrUnavailable)},
+		{name: "rate limited", err: fmt.Errorf("%w: slow down", youtube.ErrRateLimited)},
+		{name: "key rejected", err: fmt.Errorf("%w: keyInvalid", youtube.ErrInvalidAPIKey)},
+		{name: "other error", err: errors.New("failed to decode response"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := slog.New(slog.NewTextHandler(io.Discard, nil))
+			added := map[string]models.Video{}
+			dbMock := &db_mock.DBMock{
+				GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+					return false
+				}),
+				AddVideosFunc: addEach(func(video models.Video) {
+					added[video.ID] = video
+				}),
+			}
+			youTubeMock := &youtube_mock.ClientMock{
+				ClassifyShortFunc: trustFeedHint,
+				GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+					return tt.err
+				},
+			}
+			h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+			channel := models.Channel{ID: "UC123", SourceType: models.SourceYouTube}
+			stats, err := h.addVideosForChannel(context.Background(), channel, &feedparser.Channel{
+				ID:         "UC123",
+				SourceType: models.SourceYouTube,
+				Videos: []models.Video{
+					{ID: "regular", Title: "Regular"},
+					{ID: "short", Title: "Short", IsShort: true},
+				},
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, added)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ingestStats{added: 2}, stats)
+			require.Len(t, added, 2)
+			assert.True(t, added["regular"].DurationPending)
+			assert.False(t, added["regular"].IsDiscarded)
+			// The feed hint still works without the API
+			assert.True(t, added["short"].DurationPending)
+			assert.Equal(t, models.DiscardShorts, added["short"].DiscardReason)
+		})
+	}
+}
+
+func TestAddVideosForChannelWithPartialDurations(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	added := map[string]models.Video{}
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return false
+		}),
+		AddVideosFunc: addEach(func(video models.Video) {
+			added[video.ID] = video
+		}),
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			videos["found"].DurationSeconds = 600
+			return youtube.VideoErrors{
+				"broken":  fmt.Errorf("%w [PT?]", youtube.ErrInvalidDuration),
+				"refused": fmt.Errorf("%w: videos.list needs 1 units", quota.ErrBudgetExhausted),
+			}
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	channel := models.Channel{ID: "UC123", SourceType: models.SourceYouTube}
+	stats, err := h.addVideosForChannel(context.Background(), channel, &feedparser.Channel{
+		ID:         "UC123",
+		SourceType: models.SourceYouTube,
+		Videos:     []models.Video{{ID: "found"}, {ID: "broken"}, {ID: "refused"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ingestStats{added: 3}, stats)
+	assert.Equal(t, 600, added["found"].DurationSeconds)
+	assert.False(t, added["found"].DurationPending)
+	// Invalid details won't get better by asking again
+	assert.False(t, added["broken"].DurationPending)
+	assert.True(t, added["refused"].DurationPending)
+}
+
+func TestLookupPendingDurations(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	durations := map[string]models.Video{}
+	var discarded []string
+	dbMock := &db_mock.DBMock{
+		GetVideosPendingDurationFunc: func(ctx context.Context) ([]models.Video, error) {
+			return []models.Video{
+				{ID: "regular", ChannelID: "UC123", ShortSignal: models.ShortSignalDuration},
+				{ID: "short", ChannelID: "UC123", ShortSignal: models.ShortSignalDuration},
+				{ID: "hinted", ChannelID: "UC123", IsShort: true, ShortSignal: models.ShortSignalFeed, IsDiscarded: true},
+			}, nil
+		},
+		SetVideoDurationFunc: func(ctx context.Context, video models.Video) error {
+			durations[video.ID] = video
+			return nil
+		},
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID}, nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			assert.Equal(t, models.DiscardShorts, reason)
+			discarded = append(discarded, videoID)
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: func(
+			ctx context.Context, videoID string, feedHint bool, durationSeconds int,
+		) (bool, models.ShortSignal) {
+			return durationSeconds <= 60, models.ShortSignalDuration
+		},
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			videos["regular"].DurationSeconds = 600
+			videos["short"].DurationSeconds = 30
+			videos["hinted"].DurationSeconds = 45
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	h.lookupPendingDurations(context.Background())
+
+	require.Len(t, durations, 3)
+	assert.Equal(t, 600, durations["regular"].DurationSeconds)
+	assert.False(t, durations["regular"].IsShort)
+	assert.True(t, durations["short"].IsShort)
+	// Classifications that didn't come from the duration are kept
+	assert.Equal(t, models.ShortSignalFeed, durations["hinted"].ShortSignal)
+	assert.Len(t, youTubeMock.ClassifyShortCalls(), 2)
+	assert.Equal(t, []string{"short"}, discarded)
+	assert.Len(t, dbMock.GetChannelByIDCalls(), 1)
+}
+
+func TestLookupPendingDurationsWithoutQuota(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		GetVideosPendingDurationFunc: func(ctx context.Context) ([]models.Video, error) {
+			return []models.Video{{ID: "pending", ChannelID: "UC123"}}, nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return fmt.Errorf("%w: quotaExceeded", quota.ErrQuotaExceeded)
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	h.lookupPendingDurations(context.Background())
+
+	// The videos stay pending for the next run
+	assert.Empty(t, dbMock.SetVideoDurationCalls())
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+	"github.com/TheEdgeOfRage/ytrssil-api/scheduler"
+)
+
+var ErrInvalidPollInterval = errors.New("invalid poll interval")
+
+// pollTick is how often the scheduler checks for channels that are due to be polled
+const pollTick = time.Minute
+
+func (h *handler) pollBounds() scheduler.Bounds {
+	return scheduler.Bounds{
+		Min: h.config.FetchInterval,
+		Max: h.config.MaxFetchInterval,
+	}
+}
+
+// PollRoutine periodically polls every channel whose next scheduled poll time has passed
+func (h *handler) PollRoutine(ctx context.Context) {
+	ticker := time.NewTicker(pollTick)
+	defer ticker.Stop()
+
+	h.log.Info(
+		"Starting poll scheduler goroutine",
+		"min_interval", h.config.FetchInterval,
+		"max_interval", h.config.MaxFetchInterval,
+	)
+
+	var lastLiveRecheck time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			h.log.Info("Poll scheduler context done, stopping poll scheduler goroutine")
+			return
+		case <-ticker.C:
+			h.pollDueChannels(ctx)
+			if time.Since(lastLiveRecheck) >= h.config.FetchInterval {
+				h.recheckLiveVideos(ctx)
+				h.lookupPendingDurations(ctx)
+				lastLiveRecheck = time.Now()
+			}
+		}
+	}
+}
+
+func (h *handler) pollDueChannels(ctx context.Context) {
+	// A manual fetch covers every channel anyway, the due ones get picked up on the next tick
+	if !h.fetchJobs.running.TryLock() {
+		return
+	}
+	defer h.fetchJobs.running.Unlock()
+
+	due, err := h.db.ListChannelsDueForPoll(ctx, time.Now())
+	if err != nil {
+		h.log.Error("Failed to list channels due for poll", "error", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	h.log.Info("Polling due channels", "count", len(due))
+	bounds := h.pollBounds()
+	stagger := func(i int) time.Duration {
+		return scheduler.Stagger(i, len(due), pollTick)
+	}
+	runPool(ctx, h.config.FetchWorkers, due, stagger, func(state models.ChannelPollState) {
+		_, err := h.fetchChannel(ctx, state.Channel)
+		h.schedulePoll(ctx, state, bounds, err)
+	})
+}
+
+// schedulePoll records that the channel was just fetched and schedules its next poll based on the outcome
+func (h *handler) schedulePoll(ctx context.Context, state models.ChannelPollState, bounds scheduler.Bounds, err error) {
+	if err != nil {
+		state.ConsecutiveFailures++
+	} else {
+		state.ConsecutiveFailures = 0
+	}
+
+	nextPoll := scheduler.NextPoll(state, bounds, time.Now())
+	if err := h.db.SetChannelPollResult(ctx, state.ID, nextPoll); err != nil {
+		h.log.Error("Failed to schedule next poll", "channelID", state.ID, "error", err)
+	}
+}
+
+// runPool runs work for every item on at most workers goroutines at a time and waits for them to finish.
+// delay, if given, returns how long after the start the item at an index may be picked up, which spreads
+// the items out over time. Items that haven't been picked up when the context is done are skipped.
+func runPool[T any](ctx context.Context, workers int, items []T, delay func(i int) time.Duration, work func(T)) {
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(items))) {
+		wg.Go(func() {
+			for item := range jobs {
+				work(item)
+			}
+		})
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	start := time.Now()
+	for i, item := range items {
+		if delay != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(start.Add(delay(i)))):
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- item:
+		}
+	}
+}
+
+func (h *handler) SetChannelPollInterval(ctx context.Context, channelID string, minInterval, maxInterval string) error {
+	parse := func(s string) (time.Duration, error) {
+		if s == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidPollInterval, err.Error())
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("%w: negative interval %q", ErrInvalidPollInterval, s)
+		}
+		return d, nil
+	}
+
+	minDuration, err := parse(minInterval)
+	if err != nil {
+		return err
+	}
+	maxDuration, err := parse(maxInterval)
+	if err != nil {
+		return err
+	}
+	if minDuration > 0 && maxDuration > 0 && minDuration > maxDuration {
+		return fmt.Errorf("%w: min interval is larger than max interval", ErrInvalidPollInterval)
+	}
+
+	return h.db.SetChannelPollIntervals(ctx, channelID, minDuration, maxDuration)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPoolBoundsConcurrency(t *testing.T) {
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	done := map[int]bool{}
+	runPool(context.Background(), 3, items, nil, func(item int) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		done[item] = true
+		mu.Unlock()
+	})
+
+	assert.Len(t, done, len(items))
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestRunPoolSkipsItemsAfterContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var processed atomic.Int32
+	delay := func(i int) time.Duration {
+		return time.Duration(i) * time.Hour
+	}
+	runPool(ctx, 2, []string{"first", "second", "third"}, delay, func(item string) {
+		processed.Add(1)
+		cancel()
+	})
+
+	assert.Equal(t, int32(1), processed.Load())
+}
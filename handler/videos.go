@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/TheEdgeOfRage/ytrssil-api/db"
@@ -17,8 +18,8 @@ import (
 
 var ErrInvalidProgress = errors.New("invalid progress time")
 
-func (h *handler) GetNewVideos(ctx context.Context, sortDesc bool) ([]models.Video, error) {
-	return h.db.GetNewVideos(ctx, sortDesc)
+func (h *handler) GetNewVideos(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error) {
+	return h.db.GetNewVideos(ctx, sortBy, sortDesc)
 }
 
 const WatchedVideosPageSize = 100
@@ -31,101 +32,270 @@ func (h *handler) GetWatchedVideos(ctx context.Context, sortDesc bool, page int)
 	return h.db.GetWatchedVideos(ctx, sortDesc, WatchedVideosPageSize, offset)
 }
 
-func (h *handler) addVideosForChannel(ctx context.Context, parsedChannel *feedparser.Channel, enableShorts bool) {
-	videos := make(map[string]*models.Video, len(parsedChannel.Videos))
+func (h *handler) GetDiscardedVideos(ctx context.Context, page int) ([]models.Video, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * WatchedVideosPageSize
+	return h.db.GetDiscardedVideos(ctx, WatchedVideosPageSize, offset)
+}
 
+// ingestStats counts what happened to the entries of a feed during ingestion
+type ingestStats struct {
+	// added is the number of new videos that were stored
+	added int
+	// known is the number of entries that were already stored
+	known int
+}
+
+// addVideosForChannel adds the videos of a parsed feed that aren't known yet, discarding the ones filtered
+// out by the channel's settings. Known videos are updated from the feed instead. Lookups and writes are done
+// for the whole feed at once, so the number of queries doesn't grow with the number of entries.
+func (h *handler) addVideosForChannel(
+	ctx context.Context, channel models.Channel, parsedChannel *feedparser.Channel,
+) (ingestStats, error) {
+	var stats ingestStats
+	isPlaylist := parsedChannel.SourceType == models.SourceYouTubePlaylist
+
+	ids := make([]string, 0, len(parsedChannel.Videos))
 	for _, parsedVideo := range parsedChannel.Videos {
-		date, err := parsedVideo.Published.Parse()
-		if err != nil {
-			h.log.Error("Failed to parse video information", "call", "feedparser.Parse", "err", err)
-			continue
-		}
+		ids = append(ids, parsedVideo.ID)
+	}
+	known, err := h.db.GetKnownVideoIDs(ctx, ids)
+	if err != nil {
+		h.log.Error("Failed to check which videos already exist", "call", "db.GetKnownVideoIDs", "err", err)
+		return stats, err
+	}
 
-		videoID := strings.Split(parsedVideo.ID, ":")[2]
-		exists, err := h.db.HasVideo(ctx, videoID)
-		if err != nil {
-			h.log.Error("Failed to check if video already exists", "call", "db.HasVideo", "err", err)
+	videos := make(map[string]*models.Video, len(parsedChannel.Videos))
+	knownVideos := make([]models.Video, 0, len(known))
+	for _, parsedVideo := range parsedChannel.Videos {
+		if known[parsedVideo.ID] {
+			stats.known++
+			// Only the first entry of a video that's listed more than once is used
+			if !slices.ContainsFunc(knownVideos, func(v models.Video) bool { return v.ID == parsedVideo.ID }) {
+				knownVideos = append(knownVideos, parsedVideo)
+			}
 			continue
 		}
 
-		if exists {
+		if isPlaylist && parsedVideo.ChannelID == "" {
+			h.log.Warn("Skipping playlist video without uploader", "playlistID", parsedChannel.ID, "videoID", parsedVideo.ID)
 			continue
 		}
 
-		videos[videoID] = &models.Video{
-			ID:            videoID,
-			Title:         parsedVideo.Title,
-			PublishedTime: date,
-			IsShort:       parsedVideo.IsShort,
-		}
+		video := parsedVideo
+		videos[video.ID] = &video
+	}
+
+	h.updateKnownVideos(ctx, knownVideos)
+	if isPlaylist {
+		// The videos may have arrived through their channel's feed first
+		h.addPlaylistVideos(ctx, parsedChannel.ID, knownVideos)
 	}
 
 	if len(videos) == 0 {
-		return
+		return stats, nil
 	}
 
-	// Get durations for all videos
-	err := h.youTubeClient.GetVideoDurations(ctx, videos)
-	if err != nil {
-		h.log.Error("Failed to get video durations", "call", "handler.getVideoDurations", "err", err)
-		return
+	// Get durations for all videos, other sources include them in the feed. When the API can't be used right
+	// now the videos are added with what the feed says and their durations are looked up later, a video with
+	// invalid details doesn't hold up the others.
+	if parsedChannel.SourceType == models.SourceYouTube || isPlaylist {
+		err := h.youTubeClient.GetVideoDurations(ctx, videos)
+		if err := h.deferFailedDurations(err, videos, parsedChannel.ID); err != nil {
+			h.log.Error("Failed to get video durations", "call", "handler.getVideoDurations", "err", err)
+			return stats, err
+		}
+		h.classifyShorts(ctx, videos)
 	}
 
 	// Add videos with appropriate discard flag
+	discardReason := h.discardFilter(channel)
+	newVideos := make([]models.Video, 0, len(videos))
+	uploaders := make(map[string]models.Channel)
 	for _, video := range videos {
-		isDiscarded := video.IsShort && !enableShorts
-		err = h.db.AddVideo(ctx, *video, parsedChannel.ID, isDiscarded)
-		if err != nil {
-			if !errors.Is(err, db.ErrVideoExists) {
-				h.log.Error("Failed to save video to db", "call", "db.AddVideo", "err", err)
+		if isPlaylist {
+			// Attribute playlist videos to the channel that uploaded them
+			uploaders[video.ChannelID] = models.Channel{
+				ID:           video.ChannelID,
+				SourceType:   models.SourceYouTube,
+				Name:         video.ChannelName,
+				EnableShorts: true,
 			}
-			continue
+		} else {
+			video.ChannelID = parsedChannel.ID
+		}
+
+		video.DiscardReason = discardReason(*video)
+		video.IsDiscarded = video.DiscardReason != models.NotDiscarded
+		newVideos = append(newVideos, *video)
+	}
+
+	if len(uploaders) > 0 {
+		err := h.db.AddChannels(ctx, slices.Collect(maps.Values(uploaders)))
+		if err != nil {
+			h.log.Error("Failed to add uploader channels", "call", "db.AddChannels", "err", err)
+			return stats, err
 		}
 	}
+	added, err := h.db.AddVideos(ctx, newVideos)
+	if err != nil {
+		h.log.Error("Failed to save videos to db", "call", "db.AddVideos", "err", err)
+		return stats, err
+	}
+	stats.added = len(added)
+
+	if isPlaylist {
+		h.addPlaylistVideos(ctx, parsedChannel.ID, newVideos)
+	}
+
+	return stats, nil
 }
 
-type parseResult struct {
-	channel      *feedparser.Channel
-	err          error
-	enableShorts bool
+// classifyShorts decides which of the YouTube videos are shorts, using the hint from the feed if there was one
+func (h *handler) classifyShorts(ctx context.Context, videos map[string]*models.Video) {
+	for _, video := range videos {
+		video.IsShort, video.ShortSignal = h.youTubeClient.ClassifyShort(
+			ctx, video.ID, video.IsShort, video.DurationSeconds,
+		)
+	}
 }
 
-func (h *handler) FetchVideos(ctx context.Context) error {
-	h.log.Info("Fetching new videos for all channels")
+// SetVideoShortOverride classifies a video as a short, or not, by hand and discards or restores it as the
+// shorts setting of its channel requires
+func (h *handler) SetVideoShortOverride(ctx context.Context, videoID string, isShort bool) (*models.Video, error) {
+	err := h.db.SetVideoShortOverride(ctx, videoID, isShort)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := h.db.GetVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.WatchTime != nil {
+		return video, nil
+	}
 
-	channels, err := h.db.ListChannels(ctx)
+	channel, err := h.db.GetChannelByID(ctx, video.ChannelID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var wg sync.WaitGroup
-	results := make(chan parseResult, 1)
-	for _, channel := range channels {
-		wg.Go(func() {
-			parsedChannel, err := h.parser.Parse(channel.ID)
-			results <- parseResult{channel: parsedChannel, err: err, enableShorts: channel.EnableShorts}
-		})
+	// Leave videos discarded for other reasons alone
+	reason := h.discardFilter(*channel)(*video)
+	switch {
+	case reason == models.DiscardShorts && !video.IsDiscarded:
+		err = h.db.DiscardVideo(ctx, videoID, models.DiscardShorts)
+	case reason == models.NotDiscarded && video.DiscardReason == models.DiscardShorts:
+		err = h.db.RestoreVideo(ctx, videoID)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	return h.db.GetVideo(ctx, videoID)
+}
 
-	for result := range results {
-		if result.err != nil {
-			h.log.Error("failed to parse channel feed", "error", result.err)
-			continue
+// updateKnownVideos stores the current title, thumbnail, description and statistics of known videos from the
+// feed
+func (h *handler) updateKnownVideos(ctx context.Context, videos []models.Video) {
+	updates := make([]models.Video, 0, len(videos))
+	for _, video := range videos {
+		if video.Title != "" || video.Thumbnail != "" || video.Description != "" || video.Views != 0 ||
+			video.RatingCount != 0 {
+			updates = append(updates, video)
 		}
+	}
+	if len(updates) == 0 {
+		return
+	}
 
-		h.addVideosForChannel(ctx, result.channel, result.enableShorts)
+	revised, err := h.db.UpdateKnownVideos(ctx, updates)
+	if err != nil {
+		h.log.Error("Failed to update known videos", "call", "db.UpdateKnownVideos", "err", err)
+		return
 	}
+	for _, id := range revised {
+		h.log.Info("Video was revised", "videoID", id)
+	}
+}
 
-	h.recheckLiveVideos(ctx)
+func (h *handler) addPlaylistVideos(ctx context.Context, playlistID string, videos []models.Video) {
+	ids := make([]string, 0, len(videos))
+	for _, video := range videos {
+		ids = append(ids, video.ID)
+	}
 
-	return nil
+	err := h.db.AddPlaylistVideos(ctx, playlistID, ids)
+	if err != nil {
+		h.log.Error("Failed to add videos to playlist", "call", "db.AddPlaylistVideos", "err", err)
+	}
+}
+
+type parseResult struct {
+	channel models.Channel
+	parsed  *feedparser.Channel
+	err     error
+}
+
+// parseChannel fetches the feed of a channel, skipping the download if it hasn't changed since the last
+// successfully processed fetch
+func (h *handler) parseChannel(ctx context.Context, channel models.Channel) parseResult {
+	validators := feedparser.CacheValidators{
+		ETag:         channel.FeedETag,
+		LastModified: channel.FeedLastModified,
+	}
+	parsedChannel, err := h.parser.ParseIfModified(ctx, channel, validators)
+	return parseResult{
+		channel: channel,
+		parsed:  parsedChannel,
+		err:     err,
+	}
 }
 
+// ingestParseResult adds the new videos from a parsed feed and stores its cache validators. It returns the
+// number of videos that were added.
+func (h *handler) ingestParseResult(ctx context.Context, result parseResult) (int, error) {
+	channelID := result.channel.ID
+	if errors.Is(result.err, feedparser.ErrNotModified) {
+		h.recordFetch(ctx, channelID, nil, 0)
+		return 0, nil
+	}
+	if result.err != nil {
+		h.log.Error("failed to parse channel feed", "channelID", channelID, "error", result.err)
+		h.recordFetch(ctx, channelID, result.err, 0)
+		return 0, result.err
+	}
+
+	stats, err := h.addVideosForChannel(ctx, result.channel, result.parsed)
+	if err != nil {
+		// Keep the old validators so the feed gets processed again on the next fetch
+		h.recordFetch(ctx, channelID, err, 0)
+		return 0, err
+	}
+	if stats.known == 0 && result.parsed.SourceType == models.SourceYouTube {
+		stats.added += h.backfillGap(ctx, result.channel, result.parsed.Videos)
+	}
+	h.recordFetch(ctx, channelID, nil, stats.added)
+
+	validators := result.parsed.Validators
+	err = h.db.SetChannelFeedValidators(ctx, channelID, validators.ETag, validators.LastModified)
+	if err != nil {
+		h.log.Error("Failed to store feed validators", "channelID", channelID, "error", err)
+	}
+
+	return stats.added, nil
+}
+
+// fetchChannel fetches and ingests the feed of a single channel and returns the number of videos added
+func (h *handler) fetchChannel(ctx context.Context, channel models.Channel) (int, error) {
+	return h.ingestParseResult(ctx, h.parseChannel(ctx, channel))
+}
+
+// recheckLiveVideos follows upcoming and live videos until they finish, moving them from upcoming to live and
+// from live to a regular video with its final duration
 func (h *handler) recheckLiveVideos(ctx context.Context) {
 	liveVideos, err := h.db.GetLiveVideos(ctx)
 	if err != nil {
@@ -136,28 +306,45 @@ func (h *handler) recheckLiveVideos(ctx context.Context) {
 		return
 	}
 
+	stored := make(map[string]models.Video, len(liveVideos))
 	videos := make(map[string]*models.Video, len(liveVideos))
 	for i := range liveVideos {
+		stored[liveVideos[i].ID] = liveVideos[i]
 		videos[liveVideos[i].ID] = &liveVideos[i]
 	}
 
 	err = h.youTubeClient.GetVideoDurations(ctx, videos)
-	if err != nil {
+	if isQuotaError(err) {
+		h.log.Info("Skipping live video recheck until the quota resets", "error", err)
+	} else if err != nil {
 		h.log.Error("Failed to recheck live video durations", "error", err)
-		return
 	}
 
+	failed := durationErrors(err, videos)
 	for _, video := range videos {
-		if video.IsLive {
+		// Streams that disappeared are left to the availability check, failed ones to the next recheck
+		if failed[video.ID] != nil || video.IsUnavailable() || !liveStatusChanged(stored[video.ID], *video) {
 			continue
 		}
-		err = h.db.UpdateVideoLiveStatus(ctx, video.ID, false, video.DurationSeconds)
+		err = h.db.UpdateVideoLiveStatus(ctx, *video)
 		if err != nil {
 			h.log.Error("Failed to update video live status", "videoID", video.ID, "error", err)
 		}
 	}
 }
 
+// liveStatusChanged reports whether a recheck moved the video to another state or rescheduled it
+func liveStatusChanged(before models.Video, after models.Video) bool {
+	if before.IsLive != after.IsLive || before.IsUpcoming != after.IsUpcoming {
+		return true
+	}
+	if before.ScheduledStartTime == nil || after.ScheduledStartTime == nil {
+		return before.ScheduledStartTime != after.ScheduledStartTime
+	}
+
+	return !before.ScheduledStartTime.Equal(*after.ScheduledStartTime)
+}
+
 func (h *handler) MarkVideoAsWatched(ctx context.Context, videoID string) error {
 	watchTime := time.Now()
 	return h.db.SetVideoWatchTime(ctx, videoID, &watchTime)
@@ -167,6 +354,16 @@ func (h *handler) MarkVideoAsUnwatched(ctx context.Context, videoID string) erro
 	return h.db.SetVideoWatchTime(ctx, videoID, nil)
 }
 
+// DismissVideo takes a video out of the feed without adding it to the watch history
+func (h *handler) DismissVideo(ctx context.Context, videoID string) error {
+	return h.db.DiscardVideo(ctx, videoID, models.DiscardDismissed)
+}
+
+// RestoreVideo brings a discarded video back into the feed, whatever the reason it was discarded for
+func (h *handler) RestoreVideo(ctx context.Context, videoID string) error {
+	return h.db.RestoreVideo(ctx, videoID)
+}
+
 // parseTimeProgress parses a duration string in  the Go duration format, hh:mm:ss, and mm:ss to a time.Duration
 func parseTimeProgress(progressTime string) (time.Duration, error) {
 	// Try Go duration format first
@@ -297,7 +494,7 @@ func (h *handler) AddCustomVideo(ctx context.Context, rawVideoID string) error {
 		return err
 	}
 
-	err = h.db.AddVideo(ctx, *video, video.ChannelID, false)
+	err = h.db.AddVideo(ctx, *video, video.ChannelID, models.NotDiscarded)
 	if err != nil {
 		if !errors.Is(err, db.ErrVideoExists) {
 			h.log.Error("Failed to save video to db", "call", "db.AddVideo", "err", err)
@@ -1,10 +1,19 @@
 package handler
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 func TestParseVideoInput(t *testing.T) {
@@ -77,3 +86,222 @@ func TestParseVideoInput(t *testing.T) {
 		})
 	}
 }
+
+func TestAddVideosForPlaylist(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	addedTo := map[string]string{}
+	var addedChannels []string
+	playlistVideos := map[string]bool{}
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return videoID == "fromChannel"
+		}),
+		AddChannelsFunc: func(ctx context.Context, channels []models.Channel) error {
+			for _, channel := range channels {
+				addedChannels = append(addedChannels, channel.ID)
+			}
+			return nil
+		},
+		AddVideosFunc: addEach(func(video models.Video) {
+			addedTo[video.ID] = video.ChannelID
+		}),
+		AddPlaylistVideosFunc: func(ctx context.Context, playlistID string, videoIDs []string) error {
+			assert.Equal(t, "PLseries", playlistID)
+			for _, videoID := range videoIDs {
+				playlistVideos[videoID] = true
+			}
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	playlist := models.Channel{ID: "PLseries", SourceType: models.SourceYouTubePlaylist}
+	stats, err := h.addVideosForChannel(context.Background(), playlist, &feedparser.Channel{
+		ID:         "PLseries",
+		SourceType: models.SourceYouTubePlaylist,
+		Videos: []models.Video{
+			{ID: "newVideo", ChannelID: "UCuploader", ChannelName: "Uploader"},
+			{ID: "fromChannel", ChannelID: "UCuploader", ChannelName: "Uploader"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ingestStats{added: 1, known: 1}, stats)
+	assert.Equal(t, map[string]string{"newVideo": "UCuploader"}, addedTo)
+	assert.Equal(t, []string{"UCuploader"}, addedChannels)
+	assert.Equal(t, map[string]bool{"newVideo": true, "fromChannel": true}, playlistVideos)
+	assert.Len(t, dbMock.AddChannelsCalls(), 1)
+	assert.Len(t, dbMock.AddPlaylistVideosCalls(), 2)
+}
+
+func TestSetVideoShortOverride(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	video := models.Video{ID: "vid", ChannelID: "UC123"}
+	dbMock := &db_mock.DBMock{
+		SetVideoShortOverrideFunc: func(ctx context.Context, videoID string, isShort bool) error {
+			video.IsShort = isShort
+			video.ShortSignal = models.ShortSignalOverride
+			return nil
+		},
+		GetVideoFunc: func(ctx context.Context, videoID string) (*models.Video, error) {
+			v := video
+			return &v, nil
+		},
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, EnableShorts: false}, nil
+		},
+		DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
+			video.IsDiscarded = true
+			video.DiscardReason = reason
+			return nil
+		},
+		RestoreVideoFunc: func(ctx context.Context, videoID string) error {
+			video.IsDiscarded = false
+			video.DiscardReason = models.NotDiscarded
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	updated, err := h.SetVideoShortOverride(context.Background(), "vid", true)
+	require.NoError(t, err)
+	assert.True(t, updated.IsDiscarded)
+	assert.Equal(t, models.DiscardShorts, updated.DiscardReason)
+	assert.Equal(t, models.ShortSignalOverride, updated.ShortSignal)
+
+	updated, err = h.SetVideoShortOverride(context.Background(), "vid", false)
+	require.NoError(t, err)
+	assert.False(t, updated.IsDiscarded)
+
+	// A dismissed video stays dismissed
+	video.IsDiscarded = true
+	video.DiscardReason = models.DiscardDismissed
+	updated, err = h.SetVideoShortOverride(context.Background(), "vid", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.DiscardDismissed, updated.DiscardReason)
+}
+
+func TestAddVideosForChannelUpdatesKnownVideos(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var updated []models.Video
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return true
+		}),
+		UpdateKnownVideosFunc: func(ctx context.Context, videos []models.Video) ([]string, error) {
+			updated = append(updated, videos...)
+			return []string{"renamed"}, nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	channel := models.Channel{ID: "UC123", SourceType: models.SourceYouTube}
+	stats, err := h.addVideosForChannel(context.Background(), channel, &feedparser.Channel{
+		ID:         "UC123",
+		SourceType: models.SourceYouTube,
+		Videos: []models.Video{
+			{ID: "renamed", Title: "I tried this for 30 days (shocking)"},
+			{ID: "popular", Title: "Popular", Views: 12345, RatingAverage: 5, RatingCount: 678},
+			{ID: "renamed", Title: "Listed twice"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ingestStats{known: 3}, stats)
+	// All known videos are updated with a single call, with duplicate entries dropped
+	require.Len(t, dbMock.UpdateKnownVideosCalls(), 1)
+	require.Len(t, updated, 2)
+	assert.Equal(t, "I tried this for 30 days (shocking)", updated[0].Title)
+	assert.Equal(t, int64(12345), updated[1].Views)
+}
+
+func TestAddVideosForChannelBatchesQueries(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dbMock := &db_mock.DBMock{
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return videoID == "known"
+		}),
+		UpdateKnownVideosFunc: func(ctx context.Context, videos []models.Video) ([]string, error) {
+			return nil, nil
+		},
+		AddVideosFunc: func(ctx context.Context, videos []models.Video) ([]string, error) {
+			// One of the new videos was added concurrently, e.g. through WebSub
+			return []string{"new1", "new2"}, nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	channel := models.Channel{ID: "UC123", SourceType: models.SourceYouTube, EnableShorts: true}
+	stats, err := h.addVideosForChannel(context.Background(), channel, &feedparser.Channel{
+		ID:         "UC123",
+		SourceType: models.SourceYouTube,
+		Videos:     []models.Video{{ID: "known"}, {ID: "new1"}, {ID: "new2"}, {ID: "new3"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ingestStats{added: 2, known: 1}, stats)
+	require.Len(t, dbMock.GetKnownVideoIDsCalls(), 1)
+	assert.Equal(t, []string{"known", "new1", "new2", "new3"}, dbMock.GetKnownVideoIDsCalls()[0].VideoIDs)
+	require.Len(t, dbMock.AddVideosCalls(), 1)
+	added := dbMock.AddVideosCalls()[0].Videos
+	require.Len(t, added, 3)
+	for _, video := range added {
+		assert.Equal(t, "UC123", video.ChannelID)
+	}
+}
+
+func TestRecheckLiveVideos(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	scheduled := time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC)
+	rescheduled := scheduled.Add(time.Hour)
+	updated := map[string]models.Video{}
+	dbMock := &db_mock.DBMock{
+		GetLiveVideosFunc: func(ctx context.Context) ([]models.Video, error) {
+			return []models.Video{
+				{ID: "waiting", IsUpcoming: true, ScheduledStartTime: &scheduled},
+				{ID: "rescheduled", IsUpcoming: true, ScheduledStartTime: &scheduled},
+				{ID: "started", IsUpcoming: true, ScheduledStartTime: &scheduled},
+				{ID: "streaming", IsLive: true},
+				{ID: "finished", IsLive: true},
+				{ID: "deleted", IsUpcoming: true, ScheduledStartTime: &scheduled},
+			}, nil
+		},
+		UpdateVideoLiveStatusFunc: func(ctx context.Context, video models.Video) error {
+			updated[video.ID] = video
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			videos["waiting"].ScheduledStartTime = &scheduled
+			videos["rescheduled"].ScheduledStartTime = &rescheduled
+			videos["started"].IsUpcoming = false
+			videos["started"].ScheduledStartTime = nil
+			videos["started"].IsLive = true
+			videos["finished"].IsLive = false
+			videos["finished"].DurationSeconds = 3600
+			videos["deleted"].UnavailableReason = models.UnavailableRemoved
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, youTubeMock, nil, nil, testConfig)
+
+	h.recheckLiveVideos(context.Background())
+
+	require.Len(t, updated, 3)
+	assert.Equal(t, rescheduled, *updated["rescheduled"].ScheduledStartTime)
+	assert.True(t, updated["started"].IsLive)
+	assert.False(t, updated["started"].IsUpcoming)
+	assert.False(t, updated["finished"].IsLive)
+	assert.Equal(t, 3600, updated["finished"].DurationSeconds)
+}
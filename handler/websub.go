@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/websub"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+var (
+	ErrWebSubVerificationFailed = errors.New("WebSub intent verification failed")
+	ErrWebSubInvalidSignature   = errors.New("invalid WebSub signature")
+)
+
+const (
+	webSubTopicFormat = "https://www.youtube.com/xml/feeds/videos.xml?channel_id=%s"
+	// webSubLease is the lease duration requested from the hub, which may grant a shorter one
+	webSubLease = 5 * 24 * time.Hour
+	// webSubRenewBefore is how long before its expiry a lease gets renewed
+	webSubRenewBefore = 24 * time.Hour
+	// webSubRetryAfter is how long to wait for the hub to verify a request before sending it again, verifications
+	// arriving later are rejected
+	webSubRetryAfter = 6 * time.Hour
+	// webSubRenewalInterval is how often leases are checked for renewal
+	webSubRenewalInterval = time.Hour
+)
+
+func webSubTopic(channelID string) string {
+	return fmt.Sprintf(webSubTopicFormat, channelID)
+}
+
+func (h *handler) webSubCallbackURL(channelID string) string {
+	return fmt.Sprintf("%s/websub/%s", h.config.PublicURL, url.PathEscape(channelID))
+}
+
+// webSubSecret derives a separate HMAC secret for every channel from the configured secret
+func (h *handler) webSubSecret(channelID string) string {
+	mac := hmac.New(sha256.New, []byte(h.config.WebSubSecret))
+	mac.Write([]byte(channelID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestWebSubSubscription asks the hub to subscribe to the channel. The request is recorded before it is
+// sent, since hubs may verify the intent before answering it.
+func (h *handler) requestWebSubSubscription(ctx context.Context, channelID string) error {
+	if err := h.db.SetChannelWebSubRequested(ctx, channelID); err != nil {
+		return err
+	}
+
+	return h.webSub.Subscribe(
+		ctx,
+		webSubTopic(channelID),
+		h.webSubCallbackURL(channelID),
+		h.webSubSecret(channelID),
+		webSubLease,
+	)
+}
+
+func (h *handler) requestWebSubUnsubscription(ctx context.Context, channelID string) error {
+	return h.webSub.Unsubscribe(ctx, webSubTopic(channelID), h.webSubCallbackURL(channelID))
+}
+
+// WebSubRoutine periodically requests WebSub subscriptions for channels whose lease is missing or about to
+// expire. It returns immediately if WebSub is disabled.
+func (h *handler) WebSubRoutine(ctx context.Context) {
+	if !h.config.WebSubEnabled() {
+		return
+	}
+
+	ticker := time.NewTicker(webSubRenewalInterval)
+	defer ticker.Stop()
+
+	h.log.Info("Starting WebSub renewal goroutine", "hub", h.config.WebSubHubURL)
+
+	h.renewWebSubLeases(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			h.log.Info("WebSub context done, stopping WebSub renewal goroutine")
+			return
+		case <-ticker.C:
+			h.renewWebSubLeases(ctx)
+		}
+	}
+}
+
+func (h *handler) renewWebSubLeases(ctx context.Context) {
+	now := time.Now()
+	channels, err := h.db.ListChannelsForWebSubRenewal(ctx, now.Add(webSubRenewBefore), now.Add(-webSubRetryAfter))
+	if err != nil {
+		h.log.Error("Failed to list channels for WebSub renewal", "error", err)
+		return
+	}
+
+	for _, channel := range channels {
+		if err := h.requestWebSubSubscription(ctx, channel.ID); err != nil {
+			h.log.Error("Failed to request WebSub subscription", "channelID", channel.ID, "error", err)
+		}
+	}
+}
+
+// VerifyWebSubIntent confirms a (un)subscription request that the hub is verifying. Subscriptions are only
+// confirmed while a recent request is pending, their lease is recorded capped at the requested one.
+func (h *handler) VerifyWebSubIntent(
+	ctx context.Context, channelID string, mode string, topic string, leaseSeconds int,
+) error {
+	if topic != webSubTopic(channelID) {
+		return fmt.Errorf("%w: unexpected topic %q", ErrWebSubVerificationFailed, topic)
+	}
+
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebSubVerificationFailed, err.Error())
+	}
+
+	switch mode {
+	case websub.ModeSubscribe:
+		if !channel.Subscribed {
+			return fmt.Errorf("%w: not subscribed to channel", ErrWebSubVerificationFailed)
+		}
+		if channel.WebSubRequestedAt == nil || time.Since(*channel.WebSubRequestedAt) > webSubRetryAfter {
+			return fmt.Errorf("%w: no pending subscription request", ErrWebSubVerificationFailed)
+		}
+		// Hubs may grant a shorter lease than requested, but never a longer one
+		lease := min(time.Duration(max(leaseSeconds, 0))*time.Second, webSubLease)
+		expiresAt := time.Now().Add(lease)
+		return h.db.SetChannelWebSubLease(ctx, channelID, &expiresAt)
+	case websub.ModeUnsubscribe:
+		if channel.Subscribed {
+			return fmt.Errorf("%w: still subscribed to channel", ErrWebSubVerificationFailed)
+		}
+		return h.db.SetChannelWebSubLease(ctx, channelID, nil)
+	default:
+		return fmt.Errorf("%w: unsupported mode %q", ErrWebSubVerificationFailed, mode)
+	}
+}
+
+// HandleWebSubNotification ingests a feed pushed by the hub after checking its signature. Without a configured
+// secret the per-channel secrets could be derived by anyone, so nothing is accepted.
+func (h *handler) HandleWebSubNotification(ctx context.Context, channelID string, signature string, body []byte) error {
+	if h.config.WebSubSecret == "" || !websub.VerifySignature(h.webSubSecret(channelID), signature, body) {
+		return ErrWebSubInvalidSignature
+	}
+
+	channel, err := h.db.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if !channel.Subscribed {
+		h.log.Warn("Ignoring WebSub notification for unsubscribed channel", "channelID", channelID)
+		return nil
+	}
+	// Only YouTube channel feeds are subscribed to at the hub
+	if channel.SourceType != models.SourceYouTube {
+		h.log.Warn("Ignoring WebSub notification for channel that isn't a YouTube channel", "channelID", channelID)
+		return nil
+	}
+
+	parsedChannel, err := h.parser.ParseReader(*channel, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	h.log.Info("Received WebSub notification", "channelID", channelID, "entries", len(parsedChannel.Videos))
+	stats, err := h.addVideosForChannel(ctx, *channel, parsedChannel)
+	if err != nil {
+		return err
+	}
+	h.recordFetch(ctx, channelID, nil, stats.added)
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	db_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/db"
+	youtube_mock "github.com/TheEdgeOfRage/ytrssil-api/mocks/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+const testPushedFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>YouTube video feed</title>
+	<entry>
+		<id>yt:video:dQw4w9WgXcQ</id>
+		<title>Pushed Video</title>
+		<link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+		<published>2024-01-01T00:00:00+00:00</published>
+	</entry>
+</feed>`
+
+func TestHandleWebSubNotification(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := testConfig
+	cfg.PublicURL = "https://ytrssil.example.com"
+	cfg.WebSubSecret = "secret"
+
+	var added []string
+	var outcomes []models.FetchOutcome
+	dbMock := &db_mock.DBMock{
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			sourceType := models.SourceYouTube
+			if channelID == "PLlist" {
+				sourceType = models.SourceYouTubePlaylist
+			}
+			return &models.Channel{ID: channelID, SourceType: sourceType, Subscribed: true, EnableShorts: true}, nil
+		},
+		GetKnownVideoIDsFunc: knownIDs(func(videoID string) bool {
+			return false
+		}),
+		AddVideosFunc: addEach(func(video models.Video) {
+			added = append(added, video.ID)
+		}),
+		RecordChannelFetchFunc: func(
+			ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int,
+		) error {
+			outcomes = append(outcomes, outcome)
+			return nil
+		},
+	}
+	youTubeMock := &youtube_mock.ClientMock{
+		ClassifyShortFunc: trustFeedHint,
+		GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
+			return nil
+		},
+	}
+	h := New(l, dbMock, feedparser.NewParser(l, nil, 0), youTubeMock, nil, nil, cfg)
+
+	body := []byte(testPushedFeed)
+	err := h.HandleWebSubNotification(context.Background(), "UC123", "sha1=deadbeef", body)
+	require.ErrorIs(t, err, ErrWebSubInvalidSignature)
+	assert.Empty(t, added)
+
+	sign := func(h *handler, channelID string) string {
+		mac := hmac.New(sha1.New, []byte(h.webSubSecret(channelID)))
+		mac.Write(body)
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	// Anyone could derive the channel secrets from an empty secret
+	unsecured := cfg
+	unsecured.WebSubSecret = ""
+	hUnsecured := New(l, dbMock, feedparser.NewParser(l, nil, 0), youTubeMock, nil, nil, unsecured)
+	err = hUnsecured.HandleWebSubNotification(context.Background(), "UC123", sign(hUnsecured, "UC123"), body)
+	require.ErrorIs(t, err, ErrWebSubInvalidSignature)
+	assert.Empty(t, added)
+
+	err = h.HandleWebSubNotification(context.Background(), "PLlist", sign(h, "PLlist"), body)
+	require.NoError(t, err)
+	assert.Empty(t, added, "only YouTube channels are subscribed to at the hub")
+
+	err = h.HandleWebSubNotification(context.Background(), "UC123", sign(h, "UC123"), body)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dQw4w9WgXcQ"}, added)
+	assert.Equal(t, []models.FetchOutcome{{NewVideos: 1}}, outcomes)
+}
+
+func TestVerifyWebSubIntent(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var lease *time.Time
+	var requestedAt *time.Time
+	dbMock := &db_mock.DBMock{
+		GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
+			return &models.Channel{ID: channelID, Subscribed: true, WebSubRequestedAt: requestedAt}, nil
+		},
+		SetChannelWebSubLeaseFunc: func(ctx context.Context, channelID string, expiresAt *time.Time) error {
+			lease = expiresAt
+			return nil
+		},
+	}
+	h := New(l, dbMock, nil, nil, nil, nil, testConfig)
+
+	err := h.VerifyWebSubIntent(context.Background(), "UC123", "subscribe", webSubTopic("UC123"), 3600)
+	require.ErrorIs(t, err, ErrWebSubVerificationFailed, "nothing was requested")
+
+	stale := time.Now().Add(-webSubRetryAfter - time.Minute)
+	requestedAt = &stale
+	err = h.VerifyWebSubIntent(context.Background(), "UC123", "subscribe", webSubTopic("UC123"), 3600)
+	require.ErrorIs(t, err, ErrWebSubVerificationFailed, "the request is too old")
+
+	recent := time.Now().Add(-time.Minute)
+	requestedAt = &recent
+	err = h.VerifyWebSubIntent(context.Background(), "UC123", "subscribe", webSubTopic("UC456"), 3600)
+	require.ErrorIs(t, err, ErrWebSubVerificationFailed)
+
+	err = h.VerifyWebSubIntent(context.Background(), "UC123", "unsubscribe", webSubTopic("UC123"), 0)
+	require.ErrorIs(t, err, ErrWebSubVerificationFailed)
+	assert.Empty(t, dbMock.SetChannelWebSubLeaseCalls())
+
+	err = h.VerifyWebSubIntent(context.Background(), "UC123", "subscribe", webSubTopic("UC123"), 3600)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *lease, time.Minute)
+
+	err = h.VerifyWebSubIntent(context.Background(), "UC123", "subscribe", webSubTopic("UC123"), 1<<30)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.WithinDuration(t, time.Now().Add(webSubLease), *lease, time.Minute)
+}
@@ -43,6 +43,7 @@ func (s *APITestSuite) SetupSuite() {
 		nil,
 		nil,
 		nil,
+		nil,
 		s.cfg,
 	)
 
@@ -8,16 +8,111 @@ import (
 
 	"github.com/TheEdgeOfRage/ytrssil-api/db"
 	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/handler"
+	"github.com/TheEdgeOfRage/ytrssil-api/titlefilter"
 )
 
+func (srv *server) ListChannelsJSON(c *gin.Context) {
+	channels, err := srv.handler.ListChannels(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": channels})
+}
+
+func (srv *server) SearchChannelsJSON(c *gin.Context) {
+	results, err := srv.handler.SearchChannels(c.Request.Context(), c.Query("q"))
+	if err != nil {
+		if errors.Is(err, handler.ErrSearchTooShort) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := youTubeErrorStatus(err); ok {
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": results})
+}
+
+// SubscribeToChannelJSON subscribes to the channel in the path or, for URLs that can't be part of a path, the
+// channel given in the body
 func (srv *server) SubscribeToChannelJSON(c *gin.Context) {
-	channel, err := srv.handler.SubscribeToChannel(c.Request.Context(), c.Param("channel_id"))
+	reference := c.Param("channel_id")
+	if reference == "" {
+		var body struct {
+			Channel string `json:"channel" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		reference = body.Channel
+	}
+
+	channel, err := srv.handler.SubscribeToChannel(c.Request.Context(), reference)
 	if err != nil {
 		if errors.Is(err, db.ErrAlreadySubscribed) {
 			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
-		if errors.Is(err, feedparser.ErrInvalidChannelID) {
+		if errors.Is(err, feedparser.ErrInvalidChannelID) ||
+			errors.Is(err, handler.ErrInvalidChannelReference) ||
+			errors.Is(err, handler.ErrNotYouTubeURL) ||
+			errors.Is(err, handler.ErrUnsupportedChannelURL) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := youTubeErrorStatus(err); ok {
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+func (srv *server) SubscribeToPlaylistJSON(c *gin.Context) {
+	channel, err := srv.handler.SubscribeToPlaylist(c.Request.Context(), c.Param("playlist_id"))
+	if err != nil {
+		if errors.Is(err, handler.ErrInvalidPlaylistID) || errors.Is(err, feedparser.ErrInvalidChannelID) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := youTubeErrorStatus(err); ok {
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+func (srv *server) SubscribeToFeedJSON(c *gin.Context) {
+	var body struct {
+		SourceType string `json:"source_type" binding:"required"`
+		URL        string `json:"url" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := srv.handler.SubscribeToFeed(c.Request.Context(), body.SourceType, body.URL)
+	if err != nil {
+		if errors.Is(err, feedparser.ErrInvalidFeedReference) || errors.Is(err, feedparser.ErrUnsupportedSource) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -61,3 +156,113 @@ func (srv *server) ToggleChannelShortsJSON(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"msg": "shorts setting updated", "enable_shorts": enable})
 }
+
+func (srv *server) ImportBackCatalogJSON(c *gin.Context) {
+	var body struct {
+		From        string `json:"from"`
+		To          string `json:"to"`
+		MarkWatched bool   `json:"mark_watched"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := srv.handler.StartImport(
+		c.Request.Context(), c.Param("channel_id"), body.From, body.To, body.MarkWatched,
+	)
+	if err != nil {
+		if errors.Is(err, handler.ErrInvalidImportRange) || errors.Is(err, handler.ErrImportUnsupported) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"job_id": job.ID, "job": job})
+}
+
+type titleFiltersBody struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+func (srv *server) PreviewTitleFiltersJSON(c *gin.Context) {
+	var body titleFiltersBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	videos, err := srv.handler.PreviewTitleFilters(c.Request.Context(), c.Param("channel_id"), body.Include, body.Exclude)
+	if err != nil {
+		if errors.Is(err, titlefilter.ErrInvalidPattern) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"videos": videos})
+}
+
+func (srv *server) SetChannelTitleFiltersJSON(c *gin.Context) {
+	var body titleFiltersBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	discarded, err := srv.handler.SetChannelTitleFilters(
+		c.Request.Context(), c.Param("channel_id"), body.Include, body.Exclude,
+	)
+	if err != nil {
+		if errors.Is(err, titlefilter.ErrInvalidPattern) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, db.ErrChannelNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "title filters updated", "discarded": discarded})
+}
+
+func (srv *server) SetChannelPollIntervalJSON(c *gin.Context) {
+	var body struct {
+		MinInterval string `json:"min_interval"`
+		MaxInterval string `json:"max_interval"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := srv.handler.SetChannelPollInterval(
+		c.Request.Context(), c.Param("channel_id"), body.MinInterval, body.MaxInterval,
+	)
+	if err != nil {
+		if errors.Is(err, handler.ErrInvalidPollInterval) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, db.ErrChannelNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "poll interval updated"})
+}
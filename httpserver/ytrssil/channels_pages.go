@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	datastar "github.com/starfederation/datastar-go/datastar"
 
 	"github.com/TheEdgeOfRage/ytrssil-api/db"
+	"github.com/TheEdgeOfRage/ytrssil-api/handler"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 	"github.com/TheEdgeOfRage/ytrssil-api/pages"
 )
 
@@ -26,9 +30,49 @@ func (srv *server) ChannelsPage(c *gin.Context) {
 	c.Render(http.StatusOK, r)
 }
 
+// avatarMaxAge is how long browsers may cache a channel avatar
+const avatarMaxAge = 24 * time.Hour
+
+func (srv *server) ChannelAvatarPage(c *gin.Context) {
+	filePath, imageURL, err := srv.handler.ServeChannelAvatar(c.Request.Context(), c.Param("channel_id"))
+	if err != nil {
+		returnErr(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d", int(avatarMaxAge.Seconds())))
+	if filePath == "" {
+		c.Redirect(http.StatusFound, imageURL)
+		return
+	}
+	c.File(filePath)
+}
+
+func (srv *server) SearchChannelsPage(c *gin.Context) {
+	var signals struct {
+		Query string `json:"channelSearch"`
+	}
+	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
+		sse := newSSE(c)
+		sse.PatchElementTempl(pages.ChannelSearchResults(nil, err))
+		return
+	}
+
+	results, err := srv.handler.SearchChannels(c.Request.Context(), signals.Query)
+	if errors.Is(err, handler.ErrSearchTooShort) {
+		// Clear the results while the query is still being typed
+		results, err = nil, nil
+	}
+
+	sse := newSSE(c)
+	sse.PatchElementTempl(pages.ChannelSearchResults(results, err))
+}
+
 func (srv *server) SubscribeToChannelPage(c *gin.Context) {
 	var signals struct {
-		ChannelID string `json:"channelID"`
+		ChannelID     string `json:"channelID"`
+		SourceType    string `json:"sourceType"`
+		ImportCatalog bool   `json:"importCatalog"`
 	}
 	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
 		sse := newSSE(c)
@@ -36,7 +80,19 @@ func (srv *server) SubscribeToChannelPage(c *gin.Context) {
 		return
 	}
 
-	_, err := srv.handler.SubscribeToChannel(c.Request.Context(), signals.ChannelID)
+	var err error
+	switch signals.SourceType {
+	case "", models.SourceYouTube:
+		var channel *models.Channel
+		channel, err = srv.handler.SubscribeToChannel(c.Request.Context(), signals.ChannelID)
+		if err == nil && signals.ImportCatalog {
+			_, err = srv.handler.StartImport(c.Request.Context(), channel.ID, "", "", false)
+		}
+	case models.SourceYouTubePlaylist:
+		_, err = srv.handler.SubscribeToPlaylist(c.Request.Context(), signals.ChannelID)
+	default:
+		_, err = srv.handler.SubscribeToFeed(c.Request.Context(), signals.SourceType, signals.ChannelID)
+	}
 	if err != nil {
 		sse := newSSE(c)
 		sse.ExecuteScript(fmt.Sprintf(`showFormError("subscription-modal", %q)`, err.Error()))
@@ -50,6 +106,89 @@ func (srv *server) SubscribeToChannelPage(c *gin.Context) {
 	`)
 }
 
+func (srv *server) ImportBackCatalogPage(c *gin.Context) {
+	var signals struct {
+		From        string `json:"importFrom"`
+		To          string `json:"importTo"`
+		MarkWatched bool   `json:"importWatched"`
+	}
+	channelID := c.Param("channel_id")
+	modalID := fmt.Sprintf("import-modal-%s", channelID)
+	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	job, err := srv.handler.StartImport(
+		c.Request.Context(), channelID, signals.From, signals.To, signals.MarkWatched,
+	)
+	if err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	sse := newSSE(c)
+	sse.ExecuteScript(fmt.Sprintf(`bootstrap.Modal.getInstance(document.getElementById(%q)).hide()`, modalID))
+	srv.followJob(c, sse, job)
+}
+
+// titleFilterSignals holds the patterns of the filters modal, one per line
+type titleFilterSignals struct {
+	Include string `json:"filterInclude"`
+	Exclude string `json:"filterExclude"`
+}
+
+func (srv *server) PreviewTitleFiltersPage(c *gin.Context) {
+	var signals titleFilterSignals
+	channelID := c.Param("channel_id")
+	modalID := fmt.Sprintf("filters-modal-%s", channelID)
+	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	videos, err := srv.handler.PreviewTitleFilters(
+		c.Request.Context(), channelID, strings.Split(signals.Include, "\n"), strings.Split(signals.Exclude, "\n"),
+	)
+	if err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	sse := newSSE(c)
+	sse.PatchElementTempl(pages.FilterPreview(channelID, videos))
+}
+
+func (srv *server) SetChannelTitleFiltersPage(c *gin.Context) {
+	var signals titleFilterSignals
+	channelID := c.Param("channel_id")
+	modalID := fmt.Sprintf("filters-modal-%s", channelID)
+	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	_, err := srv.handler.SetChannelTitleFilters(
+		c.Request.Context(), channelID, strings.Split(signals.Include, "\n"), strings.Split(signals.Exclude, "\n"),
+	)
+	if err != nil {
+		sse := newSSE(c)
+		sse.ExecuteScript(fmt.Sprintf(`showFormError(%q, %q)`, modalID, err.Error()))
+		return
+	}
+
+	sse := newSSE(c)
+	sse.ExecuteScript(fmt.Sprintf(`
+		bootstrap.Modal.getInstance(document.getElementById(%q)).hide();
+		location.reload()
+	`, modalID))
+}
+
 func (srv *server) UnsubscribeFromChannelPage(c *gin.Context) {
 	channelID := c.Param("channel_id")
 	err := srv.handler.UnsubscribeFromChannel(c.Request.Context(), channelID)
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -40,6 +42,36 @@ func (s *ChannelsTestSuite) TestSubscribeToChannelJSON() {
 	s.True(response.Subscribed)
 }
 
+func (s *ChannelsTestSuite) TestSearchChannelsJSON() {
+	err := s.db.SubscribeToChannel(context.Background(), models.Channel{
+		ID:         "search-subscribed",
+		Name:       "Subscribed",
+		Subscribed: true,
+	})
+	s.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/channels/search?q=cooking", nil)
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusOK, w.Code)
+	var response struct {
+		Channels []models.ChannelSearchResult `json:"channels"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	s.Require().NoError(err)
+	s.Require().Len(response.Channels, 2)
+	s.True(response.Channels[0].Subscribed)
+	s.False(response.Channels[1].Subscribed)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/channels/search?q=a", nil)
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (s *ChannelsTestSuite) TestUnsubscribeFromChannelJSON() {
 	channelID := "test-channel-456"
 
@@ -84,6 +116,40 @@ func (s *ChannelsTestSuite) TestChannelsPage() {
 	s.Contains(w.Body.String(), "Test Channel Page")
 }
 
+func (s *ChannelsTestSuite) TestResubscribeRecordsRename() {
+	ctx := context.Background()
+	channel := models.Channel{ID: "channel-616", Name: "Old Name", Subscribed: true}
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, channel))
+	s.Require().NoError(s.db.UnsubscribeFromChannel(ctx, channel.ID))
+
+	channel.Name = "New Name"
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, channel))
+
+	stored, err := s.db.GetChannelByID(ctx, channel.ID)
+	s.Require().NoError(err)
+	s.Equal("New Name", stored.Name)
+	s.Equal("Old Name", stored.FormerName)
+}
+
+func (s *ChannelsTestSuite) TestChannelAvatarPageRedirectsUntilCached() {
+	ctx := context.Background()
+	err := s.db.SubscribeToChannel(ctx, models.Channel{
+		ID:         "channel-626",
+		Name:       "Avatar Channel",
+		Subscribed: true,
+		ImageURL:   "https://example.com/avatar.jpg",
+	})
+	s.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/channels/channel-626/avatar", nil)
+	req.AddCookie(&http.Cookie{Name: "token", Value: s.cfg.AuthToken})
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusFound, w.Code)
+	s.Equal("https://example.com/avatar.jpg", w.Header().Get("Location"))
+}
+
 func (s *ChannelsTestSuite) TestSubscribeToChannelPage() {
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/subscribe", strings.NewReader(`{"channelID":"channel-707"}`))
@@ -129,3 +195,141 @@ func (s *ChannelsTestSuite) TestUnsubscribeRequiresAuth() {
 
 	s.Equal(http.StatusUnauthorized, w.Code)
 }
+
+func (s *ChannelsTestSuite) TestWebSubNotifyTooLarge() {
+	w := httptest.NewRecorder()
+	body := strings.NewReader(strings.Repeat("a", 5<<20))
+	req, _ := http.NewRequest("POST", "/websub/UC123", body)
+	s.webSubRouter().ServeHTTP(w, req)
+
+	s.Equal(http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func (s *ChannelsTestSuite) TestWebSubCallbacksDisabled() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/websub/UC123", strings.NewReader("<feed></feed>"))
+	req.Header.Set("X-Hub-Signature", "sha1=deadbeef")
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ChannelsTestSuite) TestWebSubVerifyWithoutRequest() {
+	err := s.db.SubscribeToChannel(context.Background(), models.Channel{
+		ID:         "websub-unrequested",
+		Name:       "Unrequested",
+		Subscribed: true,
+	})
+	s.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	query := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {"https://www.youtube.com/xml/feeds/videos.xml?channel_id=websub-unrequested"},
+		"hub.challenge":     {"challenge"},
+		"hub.lease_seconds": {"999999999"},
+	}
+	req, _ := http.NewRequest("GET", "/websub/websub-unrequested?"+query.Encode(), nil)
+	s.webSubRouter().ServeHTTP(w, req)
+
+	s.Equal(http.StatusNotFound, w.Code)
+	channels, err := s.db.ListChannels(context.Background())
+	s.Require().NoError(err)
+	for _, channel := range channels {
+		if channel.ID == "websub-unrequested" {
+			s.Nil(channel.WebSubLeaseExpiresAt)
+		}
+	}
+}
+
+func (s *ChannelsTestSuite) TestHandleRebrandKeepsFormerName() {
+	ctx := context.Background()
+	channel := models.Channel{ID: "channel-rebrand", Name: "Old Name", Subscribed: true}
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, channel))
+
+	_, err := s.db.UpdateChannelMetadata(ctx, []models.ChannelMetadata{
+		{ChannelID: channel.ID, Name: "New Name", Handle: "@first"},
+	})
+	s.Require().NoError(err)
+	_, err = s.db.UpdateChannelMetadata(ctx, []models.ChannelMetadata{
+		{ChannelID: channel.ID, Name: "New Name", Handle: "@second"},
+	})
+	s.Require().NoError(err)
+
+	stored, err := s.db.GetChannelByID(ctx, channel.ID)
+	s.Require().NoError(err)
+	s.Equal("@second", stored.Handle)
+	s.Equal("Old Name", stored.FormerName)
+}
+
+func (s *ChannelsTestSuite) TestUnwatchedCountsIncludePlaylistVideos() {
+	ctx := context.Background()
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, models.Channel{
+		ID: "UCuploader", Name: "Uploader", Subscribed: true, SourceType: models.SourceYouTube,
+	}))
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, models.Channel{
+		ID: "PLmix", Name: "Mix", Subscribed: true, SourceType: models.SourceYouTubePlaylist,
+	}))
+	for i, id := range []string{"upload-1", "upload-2", "upload-3"} {
+		err := s.db.AddVideo(ctx, models.Video{
+			ID:            id,
+			Title:         "Upload",
+			PublishedTime: time.Now().Add(-time.Duration(i) * time.Hour),
+		}, "UCuploader", models.NotDiscarded)
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(s.db.AddPlaylistVideos(ctx, "PLmix", []string{"upload-1", "upload-2"}))
+	watched := time.Now()
+	s.Require().NoError(s.db.SetVideoWatchTime(ctx, "upload-2", &watched))
+
+	channels, err := s.db.ListChannels(ctx)
+	s.Require().NoError(err)
+	counts := make(map[string]int)
+	for _, channel := range channels {
+		counts[channel.ID] = channel.UnwatchedCount
+	}
+	s.Equal(map[string]int{"UCuploader": 2, "PLmix": 1}, counts)
+
+	playlist, err := s.db.GetChannelByID(ctx, "PLmix")
+	s.Require().NoError(err)
+	s.Equal(1, playlist.UnwatchedCount)
+
+	videos, err := s.db.GetUnwatchedChannelVideos(ctx, "PLmix", false)
+	s.Require().NoError(err)
+	s.Require().Len(videos, 1)
+	s.Equal("upload-1", videos[0].ID)
+
+	states, err := s.db.ListChannelsDueForPoll(ctx, time.Now())
+	s.Require().NoError(err)
+	for _, state := range states {
+		s.Require().NotNil(state.LastPublished, state.ID)
+	}
+}
+
+func (s *ChannelsTestSuite) TestImportBackCatalogJSON() {
+	s.Require().NoError(s.db.SubscribeToChannel(context.Background(), models.Channel{
+		ID: "UCimport", Name: "Import", Subscribed: true, SourceType: models.SourceYouTube,
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/channels/UCimport/import", strings.NewReader(`{"from": "2020-01-01"}`))
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusAccepted, w.Code)
+	var response struct {
+		JobID string          `json:"job_id"`
+		Job   models.FetchJob `json:"job"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.NotEmpty(response.JobID)
+	s.Require().NotNil(response.Job.Import)
+	s.Equal("UCimport", response.Job.Import.ChannelID)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/channels/UCimport/import", strings.NewReader(`{"from": "tomorrow"}`))
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
@@ -1,7 +1,13 @@
 package ytrssil
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
 )
 
 func returnErr(c *gin.Context, status int, err error) {
@@ -11,3 +17,21 @@ func returnErr(c *gin.Context, status int, err error) {
 func returnMsg(c *gin.Context, status int, msg string) {
 	c.Data(status, "text/html", []byte(msg))
 }
+
+// youTubeErrorStatus returns the status to respond with when a call to the YouTube API failed with err, or
+// false if err didn't come from the API
+func youTubeErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, quota.ErrBudgetExhausted) || errors.Is(err, quota.ErrQuotaExceeded) ||
+		errors.Is(err, youtube.ErrRateLimited):
+		return http.StatusTooManyRequests, true
+	case errors.Is(err, youtube.ErrNotFound):
+		return http.StatusNotFound, true
+	case errors.Is(err, youtube.ErrUnavailable):
+		return http.StatusServiceUnavailable, true
+	case errors.Is(err, youtube.ErrInvalidAPIKey):
+		return http.StatusBadGateway, true
+	default:
+		return 0, false
+	}
+}
@@ -69,19 +69,36 @@ func SetupGinRouter(
 	engine.GET("/auth", srv.AuthPage)
 	engine.POST("/auth", srv.HandleAuth)
 
+	// WebSub hub callbacks are authenticated by the topic check and the HMAC signature instead, they only
+	// exist while WebSub is enabled and the secret is set
+	if cfg.WebSubEnabled() {
+		engine.GET("/websub/:channel_id", srv.WebSubVerify)
+		engine.POST("/websub/:channel_id", srv.WebSubNotify)
+	}
+
 	pages := engine.Group("")
 	pages.Use(auth.PageAuthMiddleware(cfg.AuthToken))
 	{
 		pages.POST("/fetch", srv.FetchVideosPage)
 		pages.GET("/", srv.NewVideosPage)
 		pages.GET("/watched", srv.WatchedVideosPage)
+		pages.GET("/discarded", srv.DiscardedVideosPage)
+		pages.GET("/status", srv.StatusPage)
 		pages.GET("/channels", srv.ChannelsPage)
 		pages.POST("/subscribe", srv.SubscribeToChannelPage)
+		pages.GET("/channels/search", srv.SearchChannelsPage)
+		pages.GET("/channels/:channel_id/avatar", srv.ChannelAvatarPage)
 		pages.POST("/channels/:channel_id/unsubscribe", srv.UnsubscribeFromChannelPage)
 		pages.POST("/channels/:channel_id/toggle-shorts", srv.ToggleChannelShortsPage)
+		pages.POST("/channels/:channel_id/import", srv.ImportBackCatalogPage)
+		pages.POST("/channels/:channel_id/filters", srv.SetChannelTitleFiltersPage)
+		pages.POST("/channels/:channel_id/filters/preview", srv.PreviewTitleFiltersPage)
 		pages.POST("/videos", srv.AddVideoPage)
 		pages.PATCH("/videos/:video_id/watch", srv.MarkVideoAsWatchedPage)
 		pages.PATCH("/videos/:video_id/unwatch", srv.MarkVideoAsUnwatchedPage)
+		pages.PATCH("/videos/:video_id/dismiss", srv.DismissVideoPage)
+		pages.PATCH("/videos/:video_id/restore", srv.RestoreVideoPage)
+		pages.PATCH("/videos/:video_id/short", srv.SetVideoShortOverridePage)
 		pages.PATCH("/videos/:video_id/progress", srv.SetVideoProgressPage)
 		pages.POST("/videos/:video_id/download", srv.DownloadVideoPage)
 		pages.GET("/videos/:video_id/card", srv.GetVideoCardPage)
@@ -92,12 +109,27 @@ func SetupGinRouter(
 	api.Use(auth.APIAuthMiddleware(cfg.AuthToken))
 	{
 		api.POST("/fetch", srv.FetchVideosJSON)
+		api.GET("/fetch/:job_id", srv.GetFetchJobJSON)
+		api.GET("/quota", srv.GetQuotaUsageJSON)
+		api.GET("channels", srv.ListChannelsJSON)
+		api.GET("channels/search", srv.SearchChannelsJSON)
+		api.POST("channels/subscribe", srv.SubscribeToChannelJSON)
 		api.POST("channels/:channel_id/subscribe", srv.SubscribeToChannelJSON)
 		api.POST("channels/:channel_id/unsubscribe", srv.UnsubscribeFromChannelJSON)
+		api.POST("playlists/:playlist_id/subscribe", srv.SubscribeToPlaylistJSON)
+		api.POST("feeds/subscribe", srv.SubscribeToFeedJSON)
+		api.POST("channels/:channel_id/poll-interval", srv.SetChannelPollIntervalJSON)
+		api.POST("channels/:channel_id/import", srv.ImportBackCatalogJSON)
+		api.POST("channels/:channel_id/filters", srv.SetChannelTitleFiltersJSON)
+		api.POST("channels/:channel_id/filters/preview", srv.PreviewTitleFiltersJSON)
 		api.GET("videos/new", srv.GetNewVideosJSON)
 		api.GET("videos/watched", srv.GetWatchedVideosJSON)
+		api.GET("videos/discarded", srv.GetDiscardedVideosJSON)
 		api.POST("videos/:video_id/watch", srv.MarkVideoAsWatchedJSON)
 		api.POST("videos/:video_id/unwatch", srv.MarkVideoAsUnwatchedJSON)
+		api.POST("videos/:video_id/dismiss", srv.DismissVideoJSON)
+		api.POST("videos/:video_id/restore", srv.RestoreVideoJSON)
+		api.POST("videos/:video_id/short", srv.SetVideoShortOverrideJSON)
 		api.POST("videos/:video_id/download", srv.DownloadVideoJSON)
 	}
 
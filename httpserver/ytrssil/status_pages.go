@@ -0,0 +1,16 @@
+package ytrssil
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/pages"
+)
+
+func (srv server) StatusPage(c *gin.Context) {
+	c.Render(http.StatusOK, pages.TemplRenderer{
+		Ctx:       c.Request.Context(),
+		Component: pages.StatusPage(srv.handler.GetQuotaUsage(c.Request.Context())),
+	})
+}
@@ -3,6 +3,7 @@ package ytrssil_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -68,21 +69,27 @@ func (s *EndpointsTestSuite) SetupSuite() {
 		panic(fmt.Sprintf("failed to connect to test database: %v", err))
 	}
 
-	s.parser = &mockFeedparser.ParserMock{
-		ParseFunc: func(channelID string) (*feedparser.Channel, error) {
-			publishTime := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
-			return &feedparser.Channel{
-				ID:   channelID,
-				Name: fmt.Sprintf("Test Channel %s", channelID),
-				Videos: []*feedparser.Video{
-					{
-						ID:        fmt.Sprintf("yt:video:%s-video1", channelID),
-						Title:     "Test Video 1",
-						Published: feedparser.Date(publishTime),
-						IsShort:   false,
-					},
+	parseFeed := func(_ context.Context, channel models.Channel) (*feedparser.Channel, error) {
+		return &feedparser.Channel{
+			ID:         channel.ID,
+			SourceType: channel.SourceType,
+			Name:       fmt.Sprintf("Test Channel %s", channel.ID),
+			Videos: []models.Video{
+				{
+					ID:            fmt.Sprintf("%s-video1", channel.ID),
+					Title:         "Test Video 1",
+					PublishedTime: time.Now().Add(-24 * time.Hour),
+					IsShort:       false,
 				},
-			}, nil
+			},
+		}, nil
+	}
+	s.parser = &mockFeedparser.ParserMock{
+		ParseFunc: parseFeed,
+		ParseIfModifiedFunc: func(
+			ctx context.Context, channel models.Channel, _ feedparser.CacheValidators,
+		) (*feedparser.Channel, error) {
+			return parseFeed(ctx, channel)
 		},
 	}
 
@@ -107,12 +114,34 @@ func (s *EndpointsTestSuite) SetupSuite() {
 		GetChannelImageURLFunc: func(ctx context.Context, channelID string) (string, error) {
 			return fmt.Sprintf("https://example.com/%s.jpg", channelID), nil
 		},
+		ListUploadsFunc: func(
+			ctx context.Context, channelID string, after time.Time, before time.Time,
+		) ([]models.Video, error) {
+			return nil, nil
+		},
 		ResolveChannelIDFunc: func(ctx context.Context, handle string) (string, error) {
 			return strings.TrimPrefix(handle, "@"), nil
 		},
+		SearchChannelsFunc: func(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+			return []models.ChannelSearchResult{
+				{ID: "search-subscribed", Name: "Subscribed " + query},
+				{ID: "search-new", Name: "New " + query, SubscriberCount: 1234},
+			}, nil
+		},
+		QuotaUsageFunc: func(ctx context.Context) models.QuotaUsage {
+			return models.QuotaUsage{
+				Day:    "2026-10-18",
+				Budget: 9000,
+				Used:   102,
+				Calls: []models.QuotaCallUsage{
+					{Call: "search.list", Requests: 1, Units: 100},
+					{Call: "videos.list", Requests: 2, Units: 2},
+				},
+			}
+		},
 	}
 
-	h := handler.New(l, s.db, s.parser, s.youtubeClient, nil, s.cfg)
+	h := handler.New(l, s.db, s.parser, s.youtubeClient, nil, nil, s.cfg)
 
 	gin.SetMode(gin.TestMode)
 	router, err := ytrssil.SetupGinRouter(l, s.cfg, h)
@@ -126,6 +155,18 @@ func (s *EndpointsTestSuite) SetupSuite() {
 	}
 }
 
+// webSubRouter returns a router with WebSub enabled, the hub callbacks aren't registered in the default one
+func (s *EndpointsTestSuite) webSubRouter() http.Handler {
+	cfg := s.cfg
+	cfg.PublicURL = "https://ytrssil.example.com"
+	cfg.WebSubSecret = "secret"
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	router, err := ytrssil.SetupGinRouter(l, cfg, handler.New(l, s.db, s.parser, s.youtubeClient, nil, nil, cfg))
+	s.Require().NoError(err)
+
+	return router
+}
+
 func (s *EndpointsTestSuite) TearDownSuite() {
 	defer s.dbConn.Close(context.Background())
 	_, err := s.dbConn.Exec(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", s.schema))
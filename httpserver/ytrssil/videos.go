@@ -1,13 +1,17 @@
 package ytrssil
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/handler"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 func (srv *server) GetNewVideosJSON(c *gin.Context) {
-	videos, err := srv.handler.GetNewVideos(c.Request.Context(), false)
+	videos, err := srv.handler.GetNewVideos(c.Request.Context(), models.ParseVideoSort(c.Query("sort")), false)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -26,14 +30,38 @@ func (srv *server) GetWatchedVideosJSON(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"videos": videos})
 }
 
+func (srv *server) GetDiscardedVideosJSON(c *gin.Context) {
+	videos, err := srv.handler.GetDiscardedVideos(c.Request.Context(), pageParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"videos": videos})
+}
+
 func (srv *server) FetchVideosJSON(c *gin.Context) {
-	err := srv.handler.FetchVideos(c.Request.Context())
+	job := srv.handler.StartFetch(c.Request.Context())
+
+	c.JSON(http.StatusAccepted, gin.H{"job_id": job.ID, "job": job})
+}
+
+func (srv *server) GetFetchJobJSON(c *gin.Context) {
+	job, err := srv.handler.GetFetchJob(c.Param("job_id"))
 	if err != nil {
+		if errors.Is(err, handler.ErrFetchJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"msg": "videos fetched successfully"})
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+func (srv *server) GetQuotaUsageJSON(c *gin.Context) {
+	c.JSON(http.StatusOK, srv.handler.GetQuotaUsage(c.Request.Context()))
 }
 
 func (srv *server) MarkVideoAsWatchedJSON(c *gin.Context) {
@@ -56,6 +84,44 @@ func (srv *server) MarkVideoAsUnwatchedJSON(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"msg": "cleared video from watch history"})
 }
 
+func (srv *server) DismissVideoJSON(c *gin.Context) {
+	err := srv.handler.DismissVideo(c.Request.Context(), c.Param("video_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "dismissed video"})
+}
+
+func (srv *server) RestoreVideoJSON(c *gin.Context) {
+	err := srv.handler.RestoreVideo(c.Request.Context(), c.Param("video_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "restored video"})
+}
+
+func (srv *server) SetVideoShortOverrideJSON(c *gin.Context) {
+	var body struct {
+		Short *bool `json:"short"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Short == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing short field"})
+		return
+	}
+
+	video, err := srv.handler.SetVideoShortOverride(c.Request.Context(), c.Param("video_id"), *body.Short)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, video)
+}
+
 func (srv *server) DownloadVideoJSON(c *gin.Context) {
 	videoID := c.Param("video_id")
 	resolution := c.PostForm("format")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	datastar "github.com/starfederation/datastar-go/datastar"
@@ -13,7 +14,8 @@ import (
 )
 
 func (srv server) NewVideosPage(c *gin.Context) {
-	videos, err := srv.handler.GetNewVideos(c.Request.Context(), true)
+	sortBy := models.ParseVideoSort(c.Query("sort"))
+	videos, err := srv.handler.GetNewVideos(c.Request.Context(), sortBy, true)
 	if err != nil {
 		returnErr(c, http.StatusInternalServerError, err)
 		return
@@ -21,18 +23,21 @@ func (srv server) NewVideosPage(c *gin.Context) {
 
 	c.Render(http.StatusOK, pages.TemplRenderer{
 		Ctx:       c.Request.Context(),
-		Component: pages.NewVideosPage(videos),
+		Component: pages.NewVideosPage(videos, sortBy),
 	})
 }
 
-func (srv server) WatchedVideosPage(c *gin.Context) {
-	page := 1
-	if pageParam := c.Query("page"); pageParam != "" {
-		if parsedPage, err := strconv.Atoi(pageParam); err == nil && parsedPage > 0 {
-			page = parsedPage
-		}
+// pageParam returns the page requested with the page query parameter, the first one if it isn't valid
+func pageParam(c *gin.Context) int {
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		return page
 	}
 
+	return 1
+}
+
+func (srv server) WatchedVideosPage(c *gin.Context) {
+	page := pageParam(c)
 	videos, err := srv.handler.GetWatchedVideos(c.Request.Context(), true, page)
 	if err != nil {
 		returnErr(c, http.StatusInternalServerError, err)
@@ -45,14 +50,63 @@ func (srv server) WatchedVideosPage(c *gin.Context) {
 	})
 }
 
-func (srv server) FetchVideosPage(c *gin.Context) {
-	err := srv.handler.FetchVideos(c.Request.Context())
+func (srv server) DiscardedVideosPage(c *gin.Context) {
+	page := pageParam(c)
+	videos, err := srv.handler.GetDiscardedVideos(c.Request.Context(), page)
 	if err != nil {
 		returnErr(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	sse := newSSE(c)
+	c.Render(http.StatusOK, pages.TemplRenderer{
+		Ctx:       c.Request.Context(),
+		Component: pages.DiscardedVideosPage(videos, page),
+	})
+}
+
+// fetchProgressInterval is how often the progress of a running fetch or import job is sent to the page
+const fetchProgressInterval = 500 * time.Millisecond
+
+// fetchErrorsReloadDelay gives the user time to read what failed before the page reloads
+const fetchErrorsReloadDelay = 5 * time.Second
+
+func (srv server) FetchVideosPage(c *gin.Context) {
+	job := srv.handler.StartFetch(c.Request.Context())
+
+	srv.followJob(c, newSSE(c), job)
+}
+
+// followJob sends the progress of the job to the page until it finishes and reloads the page after it
+func (srv server) followJob(c *gin.Context, sse *datastar.ServerSentEventGenerator, job models.FetchJob) {
+	ctx := c.Request.Context()
+	sse.PatchElementTempl(pages.FetchProgress(&job))
+
+	ticker := time.NewTicker(fetchProgressInterval)
+	defer ticker.Stop()
+	for !job.IsFinished() {
+		select {
+		case <-ctx.Done():
+			// The job keeps running, the page just stops following it
+			return
+		case <-ticker.C:
+		}
+
+		current, err := srv.handler.GetFetchJob(job.ID)
+		if err != nil {
+			// The job was already evicted from the finished ones, so it's certainly done
+			sse.ExecuteScript(`window.location.reload()`)
+			return
+		}
+		job = *current
+		sse.PatchElementTempl(pages.FetchProgress(&job))
+	}
+
+	if len(job.Errors) > 0 || job.Error != "" {
+		sse.ExecuteScript(
+			fmt.Sprintf(`setTimeout(() => window.location.reload(), %d)`, fetchErrorsReloadDelay.Milliseconds()),
+		)
+		return
+	}
 	sse.ExecuteScript(`window.location.reload()`)
 }
 
@@ -80,6 +134,53 @@ func (srv server) MarkVideoAsUnwatchedPage(c *gin.Context) {
 	sse.ExecuteScript(fmt.Sprintf(`animateRemove("#video-card-%s")`, videoID))
 }
 
+func (srv server) DismissVideoPage(c *gin.Context) {
+	videoID := c.Param("video_id")
+	err := srv.handler.DismissVideo(c.Request.Context(), videoID)
+	if err != nil {
+		returnErr(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	sse := newSSE(c)
+	sse.ExecuteScript(fmt.Sprintf(`animateRemove("#video-card-%s")`, videoID))
+}
+
+func (srv server) RestoreVideoPage(c *gin.Context) {
+	videoID := c.Param("video_id")
+	err := srv.handler.RestoreVideo(c.Request.Context(), videoID)
+	if err != nil {
+		returnErr(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	sse := newSSE(c)
+	sse.ExecuteScript(fmt.Sprintf(`animateRemove("#video-card-%s")`, videoID))
+}
+
+func (srv server) SetVideoShortOverridePage(c *gin.Context) {
+	var signals struct {
+		Short bool `json:"short"`
+	}
+	if err := datastar.ReadSignals(c.Request, &signals); err != nil {
+		returnErr(c, http.StatusBadRequest, err)
+		return
+	}
+
+	video, err := srv.handler.SetVideoShortOverride(c.Request.Context(), c.Param("video_id"), signals.Short)
+	if err != nil {
+		returnErr(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	sse := newSSE(c)
+	if video.IsDiscarded {
+		sse.ExecuteScript(fmt.Sprintf(`animateRemove("#video-card-%s")`, video.ID))
+		return
+	}
+	sse.PatchElementTempl(pages.VideoCard(*video))
+}
+
 func (srv server) SetVideoProgressPage(c *gin.Context) {
 	var signals struct {
 		Progress string `json:"progress"`
@@ -139,7 +240,7 @@ func (srv server) DownloadVideoPage(c *gin.Context) {
 		return
 	}
 
-	videos, err := srv.handler.GetNewVideos(c.Request.Context(), true)
+	videos, err := srv.handler.GetNewVideos(c.Request.Context(), models.SortPublished, true)
 	if err != nil {
 		returnErr(c, http.StatusInternalServerError, err)
 		return
@@ -167,7 +268,7 @@ func (srv server) DownloadVideoPage(c *gin.Context) {
 func (srv server) GetVideoCardPage(c *gin.Context) {
 	videoID := c.Param("video_id")
 
-	videos, err := srv.handler.GetNewVideos(c.Request.Context(), true)
+	videos, err := srv.handler.GetNewVideos(c.Request.Context(), models.SortPublished, true)
 	if err != nil {
 		returnErr(c, http.StatusInternalServerError, err)
 		return
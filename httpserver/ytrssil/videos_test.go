@@ -3,6 +3,7 @@ package ytrssil_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/TheEdgeOfRage/ytrssil-api/handler"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
@@ -39,7 +41,7 @@ func (s *VideosTestSuite) TestGetNewVideosJSON() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	w := httptest.NewRecorder()
@@ -74,7 +76,7 @@ func (s *VideosTestSuite) TestGetWatchedVideosJSON() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	watchTime := time.Now()
@@ -113,7 +115,7 @@ func (s *VideosTestSuite) TestMarkVideoAsWatchedJSON() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	w := httptest.NewRecorder()
@@ -147,7 +149,7 @@ func (s *VideosTestSuite) TestMarkVideoAsUnwatchedJSON() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	watchTime := time.Now()
@@ -185,7 +187,7 @@ func (s *VideosTestSuite) TestNewVideosPage() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	w := httptest.NewRecorder()
@@ -214,7 +216,7 @@ func (s *VideosTestSuite) TestWatchedVideosPage() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	watchTime := time.Now()
@@ -258,7 +260,7 @@ func (s *VideosTestSuite) TestMarkVideoAsWatchedPage() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	w := httptest.NewRecorder()
@@ -286,7 +288,7 @@ func (s *VideosTestSuite) TestMarkVideoAsUnwatchedPage() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	watchTime := time.Now()
@@ -318,7 +320,7 @@ func (s *VideosTestSuite) TestSetVideoProgressPage() {
 		PublishedTime:   time.Now().Add(-1 * time.Hour),
 		DurationSeconds: 300,
 		IsShort:         false,
-	}, channelID, false)
+	}, channelID, models.NotDiscarded)
 	s.Require().NoError(err)
 
 	w := httptest.NewRecorder()
@@ -346,13 +348,38 @@ func (s *VideosTestSuite) TestFetchVideosJSON() {
 	req.Header.Set("Authorization", s.cfg.AuthToken)
 	s.server.Handler.ServeHTTP(w, req)
 
-	s.Equal(http.StatusOK, w.Code)
+	s.Equal(http.StatusAccepted, w.Code)
 
-	var response map[string]string
+	var response struct {
+		JobID string `json:"job_id"`
+	}
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	s.Require().NoError(err)
-	s.Require().Contains(response, "msg")
-	s.Equal("videos fetched successfully", response["msg"])
+	s.Require().NotEmpty(response.JobID)
+
+	s.Eventually(func() bool {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/fetch/"+response.JobID, nil)
+		req.Header.Set("Authorization", s.cfg.AuthToken)
+		s.server.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return false
+		}
+
+		var status struct {
+			Job models.FetchJob `json:"job"`
+		}
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Job.IsFinished()
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func (s *VideosTestSuite) TestGetFetchJobNotFound() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/fetch/missing", nil)
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusNotFound, w.Code)
 }
 
 func (s *VideosTestSuite) TestFetchVideosPage() {
@@ -365,6 +392,32 @@ func (s *VideosTestSuite) TestFetchVideosPage() {
 	s.Contains(w.Header().Get("Content-Type"), "text/event-stream")
 }
 
+func (s *VideosTestSuite) TestGetQuotaUsageJSON() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/quota", nil)
+	req.Header.Set("Authorization", s.cfg.AuthToken)
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusOK, w.Code)
+	var usage models.QuotaUsage
+	err := json.Unmarshal(w.Body.Bytes(), &usage)
+	s.Require().NoError(err)
+	s.Equal(102, usage.Used)
+	s.Require().Len(usage.Calls, 2)
+	s.Equal("search.list", usage.Calls[0].Call)
+}
+
+func (s *VideosTestSuite) TestStatusPage() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/status", nil)
+	req.AddCookie(&http.Cookie{Name: "token", Value: s.cfg.AuthToken})
+	s.server.Handler.ServeHTTP(w, req)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "102 of 9000 units used")
+	s.Contains(w.Body.String(), "search.list")
+}
+
 func (s *VideosTestSuite) TestVideosRequireAuth() {
 	testCases := []struct {
 		name   string
@@ -394,3 +447,68 @@ func (s *VideosTestSuite) TestVideosRequireAuth() {
 		})
 	}
 }
+
+func (s *VideosTestSuite) TestOriginalTitleIgnoresUnchangedTitles() {
+	ctx := context.Background()
+	channelID := "test-channel-revisions"
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, models.Channel{
+		ID:         channelID,
+		Name:       "Test Channel",
+		Subscribed: true,
+	}))
+	s.Require().NoError(s.db.AddVideo(ctx, models.Video{
+		ID:            "revised-video",
+		Title:         "First Title",
+		Thumbnail:     "https://example.com/first.jpg",
+		PublishedTime: time.Now().Add(-1 * time.Hour),
+	}, channelID, models.NotDiscarded))
+
+	originalTitle := func() string {
+		video, err := s.db.GetVideo(ctx, "revised-video")
+		s.Require().NoError(err)
+		return video.OriginalTitle
+	}
+	update := func(title string, thumbnail string) {
+		_, err := s.db.UpdateKnownVideos(ctx, []models.Video{{ID: "revised-video", Title: title, Thumbnail: thumbnail}})
+		s.Require().NoError(err)
+	}
+
+	update("First Title", "https://example.com/second.jpg")
+	s.Empty(originalTitle(), "only the thumbnail changed")
+
+	update("Second Title", "")
+	s.Equal("First Title", originalTitle())
+
+	update("First Title", "")
+	s.Empty(originalTitle(), "the title was changed back")
+}
+
+func (s *VideosTestSuite) TestGetDiscardedVideosJSONPages() {
+	ctx := context.Background()
+	channelID := "test-channel-discarded-pages"
+	s.Require().NoError(s.db.SubscribeToChannel(ctx, models.Channel{
+		ID:         channelID,
+		Name:       "Test Channel",
+		Subscribed: true,
+	}))
+	for i := range handler.WatchedVideosPageSize + 1 {
+		err := s.db.AddVideo(ctx, models.Video{
+			ID:            fmt.Sprintf("discarded-%03d", i),
+			Title:         "Discarded Video",
+			PublishedTime: time.Now().Add(-time.Duration(i) * time.Minute),
+		}, channelID, models.DiscardDismissed)
+		s.Require().NoError(err)
+	}
+
+	for page, want := range map[int]int{1: handler.WatchedVideosPageSize, 2: 1} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/videos/discarded?page=%d", page), nil)
+		req.Header.Set("Authorization", s.cfg.AuthToken)
+		s.server.Handler.ServeHTTP(w, req)
+
+		s.Equal(http.StatusOK, w.Code)
+		var response map[string][]models.Video
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		s.Len(response["videos"], want, "page %d", page)
+	}
+}
@@ -0,0 +1,78 @@
+package ytrssil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/handler"
+)
+
+// maxWebSubNotificationSize limits how much of a pushed feed is read, the route is public and the body is read
+// before its signature can be checked
+const maxWebSubNotificationSize = 4 << 20
+
+// WebSubVerify answers the hub's intent verification by echoing the challenge
+func (srv *server) WebSubVerify(c *gin.Context) {
+	leaseSeconds, _ := strconv.Atoi(c.Query("hub.lease_seconds"))
+	err := srv.handler.VerifyWebSubIntent(
+		c.Request.Context(),
+		c.Param("channel_id"),
+		c.Query("hub.mode"),
+		c.Query("hub.topic"),
+		leaseSeconds,
+	)
+	if err != nil {
+		if errors.Is(err, handler.ErrWebSubVerificationFailed) {
+			srv.log.Warn("Rejected WebSub intent verification", "error", err)
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.String(http.StatusOK, c.Query("hub.challenge"))
+}
+
+// WebSubNotify receives feed updates pushed by the hub
+func (srv *server) WebSubNotify(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebSubNotificationSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			srv.log.Warn("Rejecting oversized WebSub notification", "channelID", c.Param("channel_id"))
+			c.String(http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = srv.handler.HandleWebSubNotification(
+		c.Request.Context(),
+		c.Param("channel_id"),
+		c.GetHeader("X-Hub-Signature"),
+		body,
+	)
+	if err != nil {
+		// The hub must receive a success status even if the signature doesn't match, so it can't tell
+		// whether a forged notification was accepted
+		if errors.Is(err, handler.ErrWebSubInvalidSignature) {
+			srv.log.Warn("Ignoring WebSub notification with invalid signature", "channelID", c.Param("channel_id"))
+			c.Status(http.StatusAccepted)
+			return
+		}
+
+		srv.log.Error("Failed to handle WebSub notification", "channelID", c.Param("channel_id"), "error", err)
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,118 @@
+package avatars
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotCached      = errors.New("avatar not cached")
+	ErrInvalidChannel = errors.New("invalid channel ID for avatar")
+	ErrNotAnImage     = errors.New("avatar is not an image")
+	ErrAvatarTooLarge = errors.New("avatar is too large")
+)
+
+// channelIDPattern keeps channel IDs used as file names from escaping the cache directory
+var channelIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// maxAvatarSize caps how much is downloaded for a single avatar
+const maxAvatarSize = 5 << 20
+
+// downloadTimeout bounds how long downloading a single avatar may take
+const downloadTimeout = 30 * time.Second
+
+// Cache stores channel avatars on disk, so pages don't hotlink image URLs that expire. Each avatar is stored in
+// a file named after its channel ID.
+type Cache struct {
+	dir    string
+	client *http.Client
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{
+		dir:    dir,
+		client: &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+func (c *Cache) path(channelID string) (string, error) {
+	if !channelIDPattern.MatchString(channelID) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidChannel, channelID)
+	}
+
+	return filepath.Join(c.dir, channelID), nil
+}
+
+// Path returns the file the avatar of the channel is stored in
+func (c *Cache) Path(channelID string) (string, error) {
+	path, err := c.path(channelID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrNotCached, channelID)
+		}
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Store downloads the avatar of the channel from imageURL, replacing the cached one only once the download
+// succeeded
+func (c *Cache) Store(ctx context.Context, channelID string, imageURL string) error {
+	path, err := c.path(channelID)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set up request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download avatar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got non-200 status downloading avatar [%d]", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("%w: %s", ErrNotAnImage, contentType)
+	}
+
+	err = os.MkdirAll(c.dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create avatars directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.dir, channelID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create avatar file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(resp.Body, maxAvatarSize+1))
+	closeErr := tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write avatar: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to write avatar: %w", closeErr)
+	}
+	if written > maxAvatarSize {
+		return fmt.Errorf("%w: over %d bytes", ErrAvatarTooLarge, maxAvatarSize)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
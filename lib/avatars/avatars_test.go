@@ -0,0 +1,55 @@
+package avatars
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/avatar.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write([]byte("jpeg"))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	cache := NewCache(t.TempDir())
+	ctx := context.Background()
+
+	_, err := cache.Path("UC123")
+	assert.ErrorIs(t, err, ErrNotCached)
+
+	require.NoError(t, cache.Store(ctx, "UC123", server.URL+"/avatar.jpg"))
+	path, err := cache.Path("UC123")
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(content))
+
+	// Failed downloads keep the cached avatar
+	assert.ErrorIs(t, cache.Store(ctx, "UC123", server.URL+"/page.html"), ErrNotAnImage)
+	assert.Error(t, cache.Store(ctx, "UC123", server.URL+"/missing.jpg"))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(content))
+}
+
+func TestPathRejectsInvalidChannelIDs(t *testing.T) {
+	cache := NewCache(t.TempDir())
+
+	_, err := cache.Path("../etc/passwd")
+	assert.ErrorIs(t, err, ErrInvalidChannel)
+	assert.ErrorIs(t, cache.Store(context.Background(), "a/b", "https://example.com"), ErrInvalidChannel)
+}
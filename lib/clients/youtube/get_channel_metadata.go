@@ -0,0 +1,97 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// ChannelsPageSize is the maximum number of channels the channels API returns details for per request
+const ChannelsPageSize = 50
+
+type APIChannelMetadataListResponse struct {
+	Items []APIChannelMetadata `json:"items"`
+}
+
+type APIChannelMetadata struct {
+	ID      string `json:"id"`
+	Snippet struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		CustomURL   string `json:"customUrl"`
+		Thumbnails  struct {
+			Default APIThumbnail `json:"default"`
+			Medium  APIThumbnail `json:"medium"`
+			High    APIThumbnail `json:"high"`
+		} `json:"thumbnails"`
+	} `json:"snippet"`
+	BrandingSettings struct {
+		Image struct {
+			BannerExternalURL string `json:"bannerExternalUrl"`
+		} `json:"image"`
+	} `json:"brandingSettings"`
+}
+
+type APIThumbnail struct {
+	URL string `json:"url"`
+}
+
+// toChannelMetadata maps a channel from the API, picking the largest avatar it has
+func (c APIChannelMetadata) toChannelMetadata() models.ChannelMetadata {
+	thumbnails := c.Snippet.Thumbnails
+	avatarURL := thumbnails.High.URL
+	if avatarURL == "" {
+		avatarURL = thumbnails.Medium.URL
+	}
+	if avatarURL == "" {
+		avatarURL = thumbnails.Default.URL
+	}
+
+	return models.ChannelMetadata{
+		ChannelID:   c.ID,
+		Name:        c.Snippet.Title,
+		Handle:      c.Snippet.CustomURL,
+		Description: c.Snippet.Description,
+		AvatarURL:   avatarURL,
+		BannerURL:   c.BrandingSettings.Image.BannerExternalURL,
+	}
+}
+
+func (c *youTubeClient) GetChannelsMetadata(
+	ctx context.Context, channelIDs []string,
+) ([]models.ChannelMetadata, error) {
+	if len(channelIDs) > ChannelsPageSize {
+		return nil, fmt.Errorf("too many channels in one request [%d > %d]", len(channelIDs), ChannelsPageSize)
+	}
+	if len(channelIDs) == 0 {
+		return nil, nil
+	}
+
+	query := url.Values{}
+	query.Add("id", strings.Join(channelIDs, ","))
+	query.Add("part", "snippet,brandingSettings")
+	query.Add("maxResults", fmt.Sprint(ChannelsPageSize))
+	query.Add(
+		"fields",
+		"items(id,snippet(title,description,customUrl,thumbnails),brandingSettings/image/bannerExternalUrl)",
+	)
+
+	c.log.Info("Making request to YouTube API for channel metadata", "count", len(channelIDs))
+	var respData APIChannelMetadataListResponse
+	err := c.getJSON(ctx, quota.CallChannelsList, "/youtube/v3/channels", query, &respData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch channel details: %w", err)
+	}
+
+	// Channels that were terminated or deleted are left out of the response
+	metadata := make([]models.ChannelMetadata, 0, len(respData.Items))
+	for _, item := range respData.Items {
+		metadata = append(metadata, item.toChannelMetadata())
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,43 @@
+package youtube
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestToChannelMetadata(t *testing.T) {
+	const body = `{"items": [
+		{
+			"id": "UC123",
+			"snippet": {
+				"title": "New Name",
+				"description": "About",
+				"customUrl": "@newname",
+				"thumbnails": {
+					"default": {"url": "https://yt3.ggpht.com/default"},
+					"high": {"url": "https://yt3.ggpht.com/high"}
+				}
+			},
+			"brandingSettings": {"image": {"bannerExternalUrl": "https://yt3.ggpht.com/banner"}}
+		},
+		{"id": "UC456", "snippet": {"title": "Plain", "thumbnails": {"medium": {"url": "https://yt3.ggpht.com/medium"}}}}
+	]}`
+	var resp APIChannelMetadataListResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Items, 2)
+
+	assert.Equal(t, models.ChannelMetadata{
+		ChannelID:   "UC123",
+		Name:        "New Name",
+		Handle:      "@newname",
+		Description: "About",
+		AvatarURL:   "https://yt3.ggpht.com/high",
+		BannerURL:   "https://yt3.ggpht.com/banner",
+	}, resp.Items[0].toChannelMetadata())
+	assert.Equal(t, "https://yt3.ggpht.com/medium", resp.Items[1].toChannelMetadata().AvatarURL)
+}
@@ -2,18 +2,29 @@ package youtube
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"math"
-	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
+var ErrInvalidDuration = errors.New("invalid video duration")
+
+const (
+	// VideosPageSize is the maximum number of IDs the videos API accepts in one request
+	VideosPageSize = 50
+	// durationConcurrency is how many requests for durations are sent at the same time
+	durationConcurrency = 4
+)
+
 type APIVideoListResponse struct {
 	Items []APIVideo `json:"items"`
 }
@@ -35,7 +46,34 @@ type APISnippet struct {
 }
 
 type APILiveStreamingDetails struct {
-	ActualEndTime string `json:"actualEndTime"`
+	ScheduledStartTime string `json:"scheduledStartTime"`
+	ActualStartTime    string `json:"actualStartTime"`
+	ActualEndTime      string `json:"actualEndTime"`
+}
+
+// applyLiveStatus sets whether the video is an upcoming or currently active premiere or live stream. Streams
+// that already ended are regular videos.
+func applyLiveStatus(video *models.Video, details *APILiveStreamingDetails) {
+	video.IsLive = false
+	video.IsUpcoming = false
+	video.ScheduledStartTime = nil
+	if details == nil || details.ActualEndTime != "" {
+		return
+	}
+
+	if details.ActualStartTime != "" {
+		video.IsLive = true
+		return
+	}
+
+	scheduled, err := time.Parse(time.RFC3339, details.ScheduledStartTime)
+	if err != nil {
+		// Without a start time there's nothing to count down to, so treat it like the stream is live
+		video.IsLive = true
+		return
+	}
+	video.IsUpcoming = true
+	video.ScheduledStartTime = &scheduled
 }
 
 type APIPlayer struct {
@@ -43,8 +81,27 @@ type APIPlayer struct {
 }
 
 type APIContentDetails struct {
-	Duration   string `json:"duration"`
-	Definition string `json:"definition"`
+	Duration          string                `json:"duration"`
+	Definition        string                `json:"definition"`
+	RegionRestriction *APIRegionRestriction `json:"regionRestriction"`
+}
+
+// APIRegionRestriction lists the countries a video can or can't be watched in
+type APIRegionRestriction struct {
+	Allowed []string `json:"allowed"`
+	Blocked []string `json:"blocked"`
+}
+
+// BlocksRegion reports whether the video can't be watched in the region
+func (r *APIRegionRestriction) BlocksRegion(region string) bool {
+	if r == nil || region == "" {
+		return false
+	}
+	if r.Allowed != nil {
+		return !slices.Contains(r.Allowed, region)
+	}
+
+	return slices.Contains(r.Blocked, region)
 }
 
 func parseISO8601Duration(s string) (time.Duration, error) {
@@ -77,73 +134,116 @@ func parseISO8601Duration(s string) (time.Duration, error) {
 	return total + d, nil
 }
 
-func (c *youTubeClient) GetVideoDurations(ctx context.Context, videos map[string]*models.Video) error {
-	if len(videos) == 0 {
-		return nil
-	}
+// VideoErrors maps the IDs of videos whose details couldn't be looked up to the reason. It unwraps to the
+// distinct reasons, so errors.Is tells whether any of the videos failed for a reason.
+type VideoErrors map[string]error
 
-	ids := strings.Builder{}
-	for id := range videos {
-		ids.WriteString(id)
-		ids.WriteString(",")
+func (e VideoErrors) Error() string {
+	ids := slices.Sorted(maps.Keys(e))
+	if len(ids) == 0 {
+		return "no failed videos"
 	}
-	videoIDs := ids.String()
-	query := url.Values{}
-	query.Add("id", videoIDs)
-	query.Add("part", "contentDetails,liveStreamingDetails")
-	query.Add("key", c.apiKey)
+	return fmt.Sprintf("failed to look up %d videos, first %s: %v", len(ids), ids[0], e[ids[0]])
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "", nil)
-	if err != nil {
-		return fmt.Errorf("failed to set up request: %w", err)
-	}
-	req.URL = &url.URL{
-		Scheme:   "https",
-		Host:     "www.googleapis.com",
-		Path:     "/youtube/v3/videos",
-		RawQuery: query.Encode(),
+func (e VideoErrors) Unwrap() []error {
+	reasons := make([]error, 0, len(e))
+	for _, err := range e {
+		if !slices.Contains(reasons, err) {
+			reasons = append(reasons, err)
+		}
 	}
+	return reasons
+}
 
-	c.log.Info("Making request to YouTube API for video durations", "videoIDs", videoIDs)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to fetch video details: %w", err)
+// applyVideoDetails sets the availability, duration and live status of the videos from the items of a videos
+// API response for them. Videos left out of the response were removed, videos with a duration that can't be
+// parsed are returned with ErrInvalidDuration.
+func applyVideoDetails(videos map[string]*models.Video, items []APIVideo, region string) VideoErrors {
+	failed := VideoErrors{}
+	for _, video := range videos {
+		video.UnavailableReason = models.UnavailableRemoved
 	}
-	defer resp.Body.Close()
+	for _, item := range items {
+		video, ok := videos[item.ID]
+		if !ok {
+			continue
+		}
+		video.UnavailableReason = models.Available
+		if item.ContentDetails.RegionRestriction.BlocksRegion(region) {
+			video.UnavailableReason = models.UnavailableRegionBlocked
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		var bodyStr string
+		duration, err := parseISO8601Duration(item.ContentDetails.Duration)
 		if err != nil {
-			c.log.Error("failed to decode error body", "error", err)
-			bodyStr = "failed to decode body"
-		} else {
-			bodyStr = string(body)
+			failed[item.ID] = fmt.Errorf("%w [%v]: %w", ErrInvalidDuration, item.ContentDetails.Duration, err)
+			continue
 		}
-		return fmt.Errorf("got non-200 status from YouTube API [%d]: %v", resp.StatusCode, bodyStr)
+		video.DurationSeconds = int(math.Round(duration.Seconds()))
+		applyLiveStatus(video, item.LiveStreamingDetails)
 	}
 
-	decoder := json.NewDecoder(resp.Body)
-	var respData APIVideoListResponse
-	err = decoder.Decode(&respData)
-	if err != nil {
-		return fmt.Errorf("failed to decode video details: %w", err)
+	return failed
+}
+
+// GetVideoDurations looks the videos up in chunks of VideosPageSize, a few chunks at a time. Videos of chunks
+// that failed and videos with invalid details are returned in VideoErrors, the others are set regardless.
+func (c *youTubeClient) GetVideoDurations(ctx context.Context, videos map[string]*models.Video) error {
+	if len(videos) == 0 {
+		return nil
 	}
 
-	for _, v := range respData.Items {
-		duration, err := parseISO8601Duration(v.ContentDetails.Duration)
-		if err != nil {
-			c.log.Error(
-				"Failed to parse video duration",
-				"error", err,
-				"durationString", v.ContentDetails.Duration,
-				"videoID", v.ID,
-			)
-			return fmt.Errorf("failed to parse video duration [%v]: %w", v.ContentDetails.Duration, err)
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		failed    = VideoErrors{}
+		semaphore = make(chan struct{}, durationConcurrency)
+	)
+	for ids := range slices.Chunk(slices.Sorted(maps.Keys(videos)), VideosPageSize) {
+		chunk := make(map[string]*models.Video, len(ids))
+		for _, id := range ids {
+			chunk[id] = videos[id]
 		}
-		videos[v.ID].DurationSeconds = int(math.Round(duration.Seconds()))
-		videos[v.ID].IsLive = v.LiveStreamingDetails != nil && v.LiveStreamingDetails.ActualEndTime == ""
+
+		semaphore <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+			chunkFailed := c.getVideoDurationsChunk(ctx, chunk)
+			mu.Lock()
+			maps.Copy(failed, chunkFailed)
+			mu.Unlock()
+		})
 	}
+	wg.Wait()
 
+	if len(failed) > 0 {
+		return failed
+	}
 	return nil
 }
+
+// getVideoDurationsChunk looks up up to VideosPageSize videos in a single request
+func (c *youTubeClient) getVideoDurationsChunk(ctx context.Context, videos map[string]*models.Video) VideoErrors {
+	videoIDs := strings.Join(slices.Sorted(maps.Keys(videos)), ",")
+	query := url.Values{}
+	query.Add("id", videoIDs)
+	query.Add("part", "contentDetails,liveStreamingDetails")
+
+	c.log.Info("Making request to YouTube API for video durations", "videoIDs", videoIDs)
+	var respData APIVideoListResponse
+	err := c.getJSON(ctx, quota.CallVideosList, "/youtube/v3/videos", query, &respData)
+	if err != nil {
+		err = fmt.Errorf("failed to fetch video details: %w", err)
+		failed := make(VideoErrors, len(videos))
+		for id := range videos {
+			failed[id] = err
+		}
+		return failed
+	}
+
+	failed := applyVideoDetails(videos, respData.Items, c.region)
+	for id, err := range failed {
+		c.log.Error("Failed to parse video duration", "videoID", id, "error", err)
+	}
+	return failed
+}
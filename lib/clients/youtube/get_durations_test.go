@@ -1,8 +1,20 @@
 package youtube
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
 func TestParseISO8601Duration(t *testing.T) {
@@ -32,3 +44,129 @@ func TestParseISO8601Duration(t *testing.T) {
 		})
 	}
 }
+
+func TestRegionRestrictionBlocksRegion(t *testing.T) {
+	tests := []struct {
+		name        string
+		restriction *APIRegionRestriction
+		region      string
+		want        bool
+	}{
+		{"no restriction", nil, "DE", false},
+		{"no region configured", &APIRegionRestriction{Blocked: []string{"DE"}}, "", false},
+		{"blocked", &APIRegionRestriction{Blocked: []string{"US", "DE"}}, "DE", true},
+		{"blocked elsewhere", &APIRegionRestriction{Blocked: []string{"US"}}, "DE", false},
+		{"allowed", &APIRegionRestriction{Allowed: []string{"DE"}}, "DE", false},
+		{"not allowed", &APIRegionRestriction{Allowed: []string{"US"}}, "DE", true},
+		{"allowed nowhere", &APIRegionRestriction{Allowed: []string{}}, "DE", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.restriction.BlocksRegion(tt.region); got != tt.want {
+				t.Errorf("BlocksRegion(%q) = %v, want %v", tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyLiveStatus(t *testing.T) {
+	scheduled := time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		details      *APILiveStreamingDetails
+		wantLive     bool
+		wantUpcoming bool
+		wantStart    *time.Time
+	}{
+		{"regular video", nil, false, false, nil},
+		{"upcoming", &APILiveStreamingDetails{ScheduledStartTime: "2026-10-20T18:00:00Z"}, false, true, &scheduled},
+		{
+			"live",
+			&APILiveStreamingDetails{ScheduledStartTime: "2026-10-20T18:00:00Z", ActualStartTime: "2026-10-20T18:01:00Z"},
+			true, false, nil,
+		},
+		{
+			"finished",
+			&APILiveStreamingDetails{ActualStartTime: "2026-10-20T18:01:00Z", ActualEndTime: "2026-10-20T19:00:00Z"},
+			false, false, nil,
+		},
+		{"unscheduled", &APILiveStreamingDetails{}, true, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &models.Video{IsLive: true, IsUpcoming: true, ScheduledStartTime: &scheduled}
+			applyLiveStatus(video, tt.details)
+			if video.IsLive != tt.wantLive || video.IsUpcoming != tt.wantUpcoming {
+				t.Errorf(
+					"applyLiveStatus() live = %v, upcoming = %v, want %v, %v",
+					video.IsLive, video.IsUpcoming, tt.wantLive, tt.wantUpcoming,
+				)
+			}
+			if (video.ScheduledStartTime == nil) != (tt.wantStart == nil) ||
+				(tt.wantStart != nil && !video.ScheduledStartTime.Equal(*tt.wantStart)) {
+				t.Errorf("applyLiveStatus() scheduled start = %v, want %v", video.ScheduledStartTime, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestApplyVideoDetails(t *testing.T) {
+	videos := map[string]*models.Video{
+		"regular": {ID: "regular"},
+		"broken":  {ID: "broken"},
+		"deleted": {ID: "deleted"},
+	}
+	items := []APIVideo{
+		{ID: "regular", ContentDetails: APIContentDetails{Duration: "PT3M42S"}},
+		{ID: "broken", ContentDetails: APIContentDetails{Duration: "PT?"}},
+		{ID: "unrequested", ContentDetails: APIContentDetails{Duration: "PT1M"}},
+	}
+
+	failed := applyVideoDetails(videos, items, "")
+
+	require.Len(t, failed, 1)
+	require.ErrorIs(t, failed["broken"], ErrInvalidDuration)
+	assert.Equal(t, 222, videos["regular"].DurationSeconds)
+	assert.Equal(t, models.Available, videos["regular"].UnavailableReason)
+	assert.Equal(t, models.Available, videos["broken"].UnavailableReason)
+	assert.Equal(t, models.UnavailableRemoved, videos["deleted"].UnavailableReason)
+}
+
+func TestGetVideoDurationsChunks(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		ids := strings.Split(r.URL.Query().Get("id"), ",")
+		assert.LessOrEqual(t, len(ids), VideosPageSize)
+		if slices.Contains(ids, "video-000") {
+			respondError(w, http.StatusBadRequest, "badRequest", "Bad request")
+			return
+		}
+
+		var response APIVideoListResponse
+		for _, id := range ids {
+			response.Items = append(response.Items, APIVideo{ID: id, ContentDetails: APIContentDetails{Duration: "PT1M"}})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	})
+
+	videos := make(map[string]*models.Video)
+	for i := range 120 {
+		id := fmt.Sprintf("video-%03d", i)
+		videos[id] = &models.Video{ID: id}
+	}
+
+	err := client.GetVideoDurations(context.Background(), videos)
+
+	var failed VideoErrors
+	require.ErrorAs(t, err, &failed)
+	assert.Equal(t, int32(3), requests.Load())
+	// Only the chunk with the failing request is missing
+	assert.Len(t, failed, VideosPageSize)
+	assert.Contains(t, failed, "video-000")
+	assert.Equal(t, 60, videos["video-119"].DurationSeconds)
+	assert.Equal(t, models.Available, videos["video-119"].UnavailableReason)
+	assert.Equal(t, models.Available, videos["video-000"].UnavailableReason, "failed videos aren't marked as removed")
+}
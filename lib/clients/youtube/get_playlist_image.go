@@ -0,0 +1,41 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+)
+
+type APIPlaylistListResponse struct {
+	Items []struct {
+		Snippet struct {
+			Thumbnails struct {
+				Medium struct {
+					URL string `json:"url"`
+				} `json:"medium"`
+			} `json:"thumbnails"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+func (c *youTubeClient) GetPlaylistImageURL(ctx context.Context, playlistID string) (string, error) {
+	query := url.Values{}
+	query.Add("id", playlistID)
+	query.Add("part", "snippet")
+	query.Add("fields", "items/snippet/thumbnails")
+
+	c.log.Info("Making request to YouTube API for playlist image", "playlistID", playlistID)
+	var respData APIPlaylistListResponse
+	err := c.getJSON(ctx, quota.CallPlaylistsList, "/youtube/v3/playlists", query, &respData)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch playlist details: %w", err)
+	}
+
+	if len(respData.Items) == 0 {
+		return "", fmt.Errorf("playlist not found [%s]", playlistID)
+	}
+
+	return respData.Items[0].Snippet.Thumbnails.Medium.URL, nil
+}
@@ -2,14 +2,12 @@ package youtube
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"math"
-	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
@@ -17,47 +15,16 @@ func (c *youTubeClient) GetVideoMetadata(ctx context.Context, videoID string) (*
 	query := url.Values{}
 	query.Add("id", videoID)
 	query.Add("part", "snippet,contentDetails,player,liveStreamingDetails")
-	query.Add("key", c.apiKey)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to set up request: %w", err)
-	}
-	req.URL = &url.URL{
-		Scheme:   "https",
-		Host:     "www.googleapis.com",
-		Path:     "/youtube/v3/videos",
-		RawQuery: query.Encode(),
-	}
 
 	c.log.Info("Making request to YouTube API for video metadata", "videoID", videoID)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch video details: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		var bodyStr string
-		if err != nil {
-			c.log.Error("failed to decode error body", "error", err)
-			bodyStr = "failed to decode body"
-		} else {
-			bodyStr = string(body)
-		}
-		return nil, fmt.Errorf("got non-200 status from YouTube API [%d]: %v", resp.StatusCode, bodyStr)
-	}
-
-	decoder := json.NewDecoder(resp.Body)
 	var respData APIVideoListResponse
-	err = decoder.Decode(&respData)
+	err := c.getJSON(ctx, quota.CallVideosList, "/youtube/v3/videos", query, &respData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode video details: %w", err)
+		return nil, fmt.Errorf("failed to fetch video details: %w", err)
 	}
 
 	if len(respData.Items) == 0 {
-		return nil, fmt.Errorf("video not found [%s]", videoID)
+		return nil, fmt.Errorf("%w [%s]", ErrVideoNotFound, videoID)
 	}
 
 	videoData := respData.Items[0]
@@ -80,12 +47,9 @@ func (c *youTubeClient) GetVideoMetadata(ctx context.Context, videoID string) (*
 	}
 	video.DurationSeconds = int(math.Round(duration.Seconds()))
 
-	video.IsShort, err = c.isShort(ctx, videoID)
-	if err != nil {
-		return nil, err
-	}
+	video.IsShort, video.ShortSignal = c.ClassifyShort(ctx, videoID, false, video.DurationSeconds)
 
-	video.IsLive = videoData.LiveStreamingDetails != nil && videoData.LiveStreamingDetails.ActualEndTime == ""
+	applyLiveStatus(video, videoData.LiveStreamingDetails)
 
 	return video, nil
 }
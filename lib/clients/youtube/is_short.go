@@ -4,8 +4,36 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
+// shortMaxDurationSeconds is the longest a short can be
+const shortMaxDurationSeconds = 3 * 60
+
+// ClassifyShort decides whether a video is a short. A /shorts/ link in the feed is trusted first, then YouTube
+// is asked whether the video plays on the shorts page, and the duration is only used when that check fails.
+func (c *youTubeClient) ClassifyShort(
+	ctx context.Context, videoID string, feedHint bool, durationSeconds int,
+) (bool, models.ShortSignal) {
+	if feedHint {
+		return true, models.ShortSignalFeed
+	}
+
+	isShort, err := c.isShort(ctx, videoID)
+	return decideShort(isShort, err, durationSeconds)
+}
+
+// decideShort combines the result of the shorts page probe with the duration heuristic
+func decideShort(probed bool, probeErr error, durationSeconds int) (bool, models.ShortSignal) {
+	if probeErr == nil {
+		return probed, models.ShortSignalProbe
+	}
+
+	return durationSeconds > 0 && durationSeconds <= shortMaxDurationSeconds, models.ShortSignalDuration
+}
+
+// isShort checks whether the shorts page of a video plays it, regular videos get redirected to the watch page
 func (c *youTubeClient) isShort(ctx context.Context, videoID string) (bool, error) {
 	url := fmt.Sprintf("https://www.youtube.com/shorts/%s", videoID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
@@ -28,6 +56,14 @@ func (c *youTubeClient) isShort(ctx context.Context, videoID string) (bool, erro
 		c.log.Error("Failed to check if a video is a short", "videoID", videoID, "error", err)
 		return false, err
 	}
+	resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status checking if video %s is a short: %d", videoID, resp.StatusCode)
+	}
 }
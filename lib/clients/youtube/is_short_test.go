@@ -0,0 +1,36 @@
+package youtube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestDecideShort(t *testing.T) {
+	probeFailed := errors.New("consent page")
+	tests := []struct {
+		name       string
+		probed     bool
+		probeErr   error
+		duration   int
+		wantShort  bool
+		wantSignal models.ShortSignal
+	}{
+		{"probe says short", true, nil, 600, true, models.ShortSignalProbe},
+		{"probe says regular", false, nil, 30, false, models.ShortSignalProbe},
+		{"short duration", false, probeFailed, 45, true, models.ShortSignalDuration},
+		{"long duration", false, probeFailed, 181, false, models.ShortSignalDuration},
+		{"unknown duration", false, probeFailed, 0, false, models.ShortSignalDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isShort, signal := decideShort(tt.probed, tt.probeErr, tt.duration)
+			assert.Equal(t, tt.wantShort, isShort)
+			assert.Equal(t, tt.wantSignal, signal)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// uploadsPageSize is the maximum number of items the playlistItems API returns per page
+const uploadsPageSize = 50
+
+type APIPlaylistItemListResponse struct {
+	NextPageToken string            `json:"nextPageToken"`
+	Items         []APIPlaylistItem `json:"items"`
+}
+
+type APIPlaylistItem struct {
+	Snippet struct {
+		Title                  string `json:"title"`
+		VideoOwnerChannelID    string `json:"videoOwnerChannelId"`
+		VideoOwnerChannelTitle string `json:"videoOwnerChannelTitle"`
+	} `json:"snippet"`
+	ContentDetails struct {
+		VideoID          string `json:"videoId"`
+		VideoPublishedAt string `json:"videoPublishedAt"`
+	} `json:"contentDetails"`
+}
+
+// uploadsPlaylistID returns the ID of the playlist that holds every upload of a channel
+func uploadsPlaylistID(channelID string) (string, error) {
+	if !strings.HasPrefix(channelID, "UC") {
+		return "", fmt.Errorf("not a YouTube channel ID [%s]", channelID)
+	}
+
+	return "UU" + strings.TrimPrefix(channelID, "UC"), nil
+}
+
+// filterUploads maps a page of the uploads playlist, newest first, to videos published in [after, before).
+// Zero times leave the range open. It reports done once the page reaches videos older than after.
+func filterUploads(items []APIPlaylistItem, after time.Time, before time.Time) ([]models.Video, bool) {
+	videos := make([]models.Video, 0, len(items))
+	for _, item := range items {
+		// Private and deleted videos are listed without a publish time
+		publishedTime, err := time.Parse(time.RFC3339, item.ContentDetails.VideoPublishedAt)
+		if err != nil {
+			continue
+		}
+		if !after.IsZero() && publishedTime.Before(after) {
+			return videos, true
+		}
+		if !before.IsZero() && !publishedTime.Before(before) {
+			continue
+		}
+
+		videos = append(videos, models.Video{
+			ID:            item.ContentDetails.VideoID,
+			Title:         item.Snippet.Title,
+			ChannelID:     item.Snippet.VideoOwnerChannelID,
+			ChannelName:   item.Snippet.VideoOwnerChannelTitle,
+			PublishedTime: publishedTime,
+		})
+	}
+
+	return videos, false
+}
+
+func (c *youTubeClient) ListUploads(
+	ctx context.Context, channelID string, after time.Time, before time.Time,
+) ([]models.Video, error) {
+	playlistID, err := uploadsPlaylistID(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]models.Video, 0)
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Add("playlistId", playlistID)
+		query.Add("part", "snippet,contentDetails")
+		query.Add("maxResults", fmt.Sprint(uploadsPageSize))
+		if pageToken != "" {
+			query.Add("pageToken", pageToken)
+		}
+
+		c.log.Info("Making request to YouTube API for channel uploads", "channelID", channelID, "pageToken", pageToken)
+		var respData APIPlaylistItemListResponse
+		err := c.getJSON(ctx, quota.CallPlaylistItemsList, "/youtube/v3/playlistItems", query, &respData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch channel uploads: %w", err)
+		}
+
+		page, done := filterUploads(respData.Items, after, before)
+		videos = append(videos, page...)
+		if done || respData.NextPageToken == "" {
+			return videos, nil
+		}
+		pageToken = respData.NextPageToken
+	}
+}
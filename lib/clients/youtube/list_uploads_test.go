@@ -0,0 +1,44 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadsPlaylistID(t *testing.T) {
+	playlistID, err := uploadsPlaylistID("UCuAXFkgsw1L7xaCfnd5JJOw")
+	require.NoError(t, err)
+	assert.Equal(t, "UUuAXFkgsw1L7xaCfnd5JJOw", playlistID)
+
+	_, err = uploadsPlaylistID("PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf")
+	assert.Error(t, err)
+}
+
+func TestFilterUploads(t *testing.T) {
+	item := func(id string, publishedAt string) APIPlaylistItem {
+		var i APIPlaylistItem
+		i.ContentDetails.VideoID = id
+		i.ContentDetails.VideoPublishedAt = publishedAt
+		return i
+	}
+	items := []APIPlaylistItem{
+		item("newest", "2024-03-01T00:00:00Z"),
+		item("private", ""),
+		item("middle", "2024-02-01T00:00:00Z"),
+		item("oldest", "2024-01-01T00:00:00Z"),
+	}
+
+	videos, done := filterUploads(items, time.Time{}, time.Time{})
+	assert.False(t, done)
+	assert.Len(t, videos, 3)
+
+	after := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	videos, done = filterUploads(items, after, before)
+	assert.True(t, done)
+	require.Len(t, videos, 1)
+	assert.Equal(t, "middle", videos[0].ID)
+}
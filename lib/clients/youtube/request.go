@@ -0,0 +1,218 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidAPIKey = errors.New("YouTube API key rejected")
+	ErrRateLimited   = errors.New("rate limited by the YouTube API")
+	ErrUnavailable   = errors.New("YouTube API unavailable")
+)
+
+const (
+	// apiURL is where the Data API is served
+	apiURL = "https://www.googleapis.com"
+	// requestTimeout is how long a single attempt may take, including reading the response
+	requestTimeout = 15 * time.Second
+	// maxAttempts is how often a request is sent before a transient failure is returned
+	maxAttempts = 4
+	// retryBackoff is the upper bound of the wait before the first retry, doubling with each further one
+	retryBackoff = 500 * time.Millisecond
+	// maxRetryBackoff caps the wait between two attempts
+	maxRetryBackoff = 8 * time.Second
+	// maxRetryAfter is the longest Retry-After that is waited for, longer ones are returned as ErrRateLimited
+	maxRetryAfter = 30 * time.Second
+	// maxErrorBodySize limits how much of an error response is read
+	maxErrorBodySize = 64 << 10
+)
+
+// APIError is an error response of the Data API. It wraps ErrNotFound, ErrInvalidAPIKey, ErrRateLimited,
+// ErrUnavailable or quota.ErrQuotaExceeded if the response is one of those cases.
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Reason is the machine readable reason of the first error, like quotaExceeded
+	Reason string
+	// Message is the human readable description of the error
+	Message string
+	// RetryAfter is how long the API asked to wait before trying again, 0 if it didn't say
+	RetryAfter time.Duration
+
+	kind error
+}
+
+func (e *APIError) Error() string {
+	description := e.Message
+	if e.Reason != "" {
+		description = fmt.Sprintf("%s (%s)", e.Message, e.Reason)
+	}
+	if e.kind != nil {
+		return fmt.Sprintf("%v [%d]: %s", e.kind, e.StatusCode, description)
+	}
+
+	return fmt.Sprintf("got non-200 status from YouTube API [%d]: %s", e.StatusCode, description)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// apiErrorResponse is the body of an error response
+type apiErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// parseAPIError builds the error for a non-200 response
+func parseAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	var errorResponse apiErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.Message != "" {
+		apiErr.Message = errorResponse.Error.Message
+		if len(errorResponse.Error.Errors) > 0 {
+			apiErr.Reason = errorResponse.Error.Errors[0].Reason
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	switch {
+	case apiErr.Reason == "quotaExceeded" || apiErr.Reason == "dailyLimitExceeded":
+		apiErr.kind = quota.ErrQuotaExceeded
+	case resp.StatusCode == http.StatusTooManyRequests ||
+		apiErr.Reason == "rateLimitExceeded" || apiErr.Reason == "userRateLimitExceeded":
+		apiErr.kind = ErrRateLimited
+	case apiErr.Reason == "keyInvalid" || apiErr.Reason == "keyExpired" ||
+		apiErr.Reason == "accessNotConfigured" || strings.Contains(apiErr.Message, "API key"):
+		apiErr.kind = ErrInvalidAPIKey
+	case resp.StatusCode == http.StatusNotFound:
+		apiErr.kind = ErrNotFound
+	case resp.StatusCode >= http.StatusInternalServerError:
+		apiErr.kind = ErrUnavailable
+	}
+
+	return apiErr
+}
+
+// parseRetryAfter returns the wait asked for by a Retry-After header, given in seconds or as a date
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(0, time.Duration(seconds)*time.Second)
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(0, date.Sub(now))
+	}
+
+	return 0
+}
+
+// retryWait returns how long to wait before sending a request again after the given attempt failed with err,
+// or false if it shouldn't be sent again
+func (c *youTubeClient) retryWait(err error, attempt int) (time.Duration, bool) {
+	if attempt >= maxAttempts || !(errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, apiErr.RetryAfter <= maxRetryAfter
+	}
+
+	// Full jitter keeps concurrent requests that failed together from retrying together
+	backoff := min(maxRetryBackoff, c.retryBackoff<<(attempt-1))
+	return rand.N(backoff + 1), true
+}
+
+// getJSON sends a GET request to the Data API method and decodes the JSON response into target. Rate limited
+// and failed requests are retried with backoff, every attempt spends the method's quota.
+func (c *youTubeClient) getJSON(
+	ctx context.Context, call quota.Call, path string, params url.Values, target any,
+) error {
+	query := maps.Clone(params)
+	query.Set("key", c.apiKey)
+	endpoint := c.baseURL + path + "?" + query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, call, endpoint, target)
+		wait, retry := c.retryWait(err, attempt)
+		if !retry {
+			return err
+		}
+
+		c.log.Warn("Retrying YouTube API request", "call", string(call), "attempt", attempt, "wait", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send makes a single attempt at a request, spending its quota first. A response saying the quota is exceeded
+// stops further calls until the quota resets.
+func (c *youTubeClient) send(ctx context.Context, call quota.Call, endpoint string, target any) error {
+	if err := c.quota.Spend(ctx, call); err != nil {
+		return err
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set up request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		// Connection failures and timed out attempts are worth another try
+		return fmt.Errorf("%w: failed to send request: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := parseAPIError(resp)
+		if errors.Is(apiErr, quota.ErrQuotaExceeded) {
+			c.quota.Exhaust(ctx)
+		}
+		return apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		if ctx.Err() == nil && attemptCtx.Err() != nil {
+			return fmt.Errorf("%w: failed to read response: %w", ErrUnavailable, err)
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
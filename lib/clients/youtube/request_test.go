@@ -0,0 +1,207 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// newTestClient returns a client sending its requests to the handler, retrying without noticeable waits
+func newTestClient(t *testing.T, handler http.HandlerFunc) *youTubeClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewYouTubeClient(slog.New(slog.NewTextHandler(io.Discard, nil)), "test-key", "", nil)
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+	client.retryBackoff = time.Millisecond
+	return client
+}
+
+// respondError writes an error response in the format of the Data API
+func respondError(w http.ResponseWriter, status int, reason string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(
+		w, `{"error":{"code":0,"message":"`+message+`","errors":[{"reason":"`+reason+`"}]}}`,
+	)
+}
+
+func TestGetJSONRetriesTransientFailures(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.URL.Query().Get("key"))
+		switch attempts.Add(1) {
+		case 1:
+			respondError(w, http.StatusServiceUnavailable, "backendError", "Backend Error")
+		case 2:
+			respondError(w, http.StatusTooManyRequests, "rateLimitExceeded", "Slow down")
+		default:
+			_, _ = io.WriteString(w, `{"items":[{"id":"UC123"}]}`)
+		}
+	})
+
+	var response apiChannelIDResponse
+	err := client.getJSON(context.Background(), quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, "UC123", response.Items[0].ID)
+}
+
+func TestGetJSONGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		respondError(w, http.StatusInternalServerError, "backendError", "Backend Error")
+	})
+
+	var response apiChannelIDResponse
+	err := client.getJSON(context.Background(), quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response)
+	require.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(maxAttempts), attempts.Load())
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, "backendError", apiErr.Reason)
+}
+
+func TestGetJSONTypedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		reason  string
+		message string
+		want    error
+	}{
+		{
+			name:    "quota exceeded",
+			status:  http.StatusForbidden,
+			reason:  "quotaExceeded",
+			message: "The request cannot be completed because you have exceeded your quota.",
+			want:    quota.ErrQuotaExceeded,
+		},
+		{
+			name:    "invalid key",
+			status:  http.StatusBadRequest,
+			reason:  "badRequest",
+			message: "API key not valid. Please pass a valid API key.",
+			want:    ErrInvalidAPIKey,
+		},
+		{
+			name:    "API not enabled",
+			status:  http.StatusForbidden,
+			reason:  "accessNotConfigured",
+			message: "YouTube Data API v3 has not been used in project 123 before or it is disabled.",
+			want:    ErrInvalidAPIKey,
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			reason:  "playlistNotFound",
+			message: "The playlist identified with the request's playlistId parameter cannot be found.",
+			want:    ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				respondError(w, tt.status, tt.reason, tt.message)
+			})
+
+			var response apiChannelIDResponse
+			err := client.getJSON(
+				context.Background(), quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response,
+			)
+			require.ErrorIs(t, err, tt.want)
+			assert.Equal(t, int32(1), attempts.Load(), "only transient failures are retried")
+		})
+	}
+}
+
+func TestGetJSONExhaustsQuota(t *testing.T) {
+	ledger := quota.NewLedger(slog.New(slog.NewTextHandler(io.Discard, nil)), nopStore{}, 0)
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		respondError(w, http.StatusForbidden, "quotaExceeded", "Quota exceeded")
+	})
+	client.quota = ledger
+
+	var response apiChannelIDResponse
+	ctx := context.Background()
+	err := client.getJSON(ctx, quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response)
+	require.ErrorIs(t, err, quota.ErrQuotaExceeded)
+	// Later calls are refused without reaching the API
+	err = client.getJSON(ctx, quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response)
+	require.ErrorIs(t, err, quota.ErrBudgetExhausted)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestGetJSONLongRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		respondError(w, http.StatusTooManyRequests, "rateLimitExceeded", "Slow down")
+	})
+
+	var response apiChannelIDResponse
+	err := client.getJSON(context.Background(), quota.CallChannelsList, "/youtube/v3/channels", url.Values{}, &response)
+	require.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(1), attempts.Load())
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, time.Hour, apiErr.RetryAfter)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-5", 0},
+		{"Sun, 18 Oct 2026 12:00:30 GMT", 30 * time.Second},
+		{"Sun, 18 Oct 2026 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.header, now))
+		})
+	}
+}
+
+// nopStore doesn't keep the usage
+type nopStore struct{}
+
+func (nopStore) GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error) {
+	return nil, nil
+}
+
+func (nopStore) AddQuotaUsage(ctx context.Context, day string, call string, units int) error {
+	return nil
+}
@@ -2,11 +2,10 @@ package youtube
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
 )
 
 type apiChannelIDResponse struct {
@@ -16,48 +15,30 @@ type apiChannelIDResponse struct {
 }
 
 func (c *youTubeClient) ResolveChannelID(ctx context.Context, handle string) (string, error) {
+	c.log.Info("Resolving channel handle to ID", "handle", handle)
+	return c.lookupChannelID(ctx, "forHandle", handle)
+}
+
+func (c *youTubeClient) ResolveUsername(ctx context.Context, username string) (string, error) {
+	c.log.Info("Resolving legacy channel username to ID", "username", username)
+	return c.lookupChannelID(ctx, "forUsername", username)
+}
+
+// lookupChannelID returns the ID of the channel found by one of the channels API lookup filters
+func (c *youTubeClient) lookupChannelID(ctx context.Context, filter string, value string) (string, error) {
 	query := url.Values{}
-	query.Add("forHandle", handle)
+	query.Add(filter, value)
 	query.Add("part", "id")
 	query.Add("fields", "items/id")
-	query.Add("key", c.apiKey)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "", nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to set up request: %w", err)
-	}
-	req.URL = &url.URL{
-		Scheme:   "https",
-		Host:     "www.googleapis.com",
-		Path:     "/youtube/v3/channels",
-		RawQuery: query.Encode(),
-	}
-
-	c.log.Info("Resolving channel handle to ID", "handle", handle)
-	resp, err := http.DefaultClient.Do(req)
+	var respData apiChannelIDResponse
+	err := c.getJSON(ctx, quota.CallChannelsList, "/youtube/v3/channels", query, &respData)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch channel: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		var bodyStr string
-		if err != nil {
-			bodyStr = "failed to decode body"
-		} else {
-			bodyStr = string(body)
-		}
-		return "", fmt.Errorf("got non-200 status from YouTube API [%d]: %v", resp.StatusCode, bodyStr)
-	}
-
-	var respData apiChannelIDResponse
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
 
 	if len(respData.Items) == 0 {
-		return "", fmt.Errorf("channel not found for handle %q", handle)
+		return "", fmt.Errorf("%w: no channel %s %q", ErrChannelNotFound, filter, value)
 	}
 
 	return respData.Items[0].ID, nil
@@ -0,0 +1,105 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// channelSearchResults is how many channels a search returns
+const channelSearchResults = 10
+
+type APISearchListResponse struct {
+	Items []struct {
+		ID struct {
+			ChannelID string `json:"channelId"`
+		} `json:"id"`
+	} `json:"items"`
+}
+
+type APIChannelStatisticsListResponse struct {
+	Items []APIChannelStatistics `json:"items"`
+}
+
+type APIChannelStatistics struct {
+	APIChannelMetadata
+	Statistics struct {
+		SubscriberCount       string `json:"subscriberCount"`
+		HiddenSubscriberCount bool   `json:"hiddenSubscriberCount"`
+	} `json:"statistics"`
+}
+
+// toSearchResult maps a channel with its statistics from the API
+func (c APIChannelStatistics) toSearchResult() models.ChannelSearchResult {
+	metadata := c.toChannelMetadata()
+	// The count is a string in the API, and left out when hidden
+	subscribers, _ := strconv.ParseInt(c.Statistics.SubscriberCount, 10, 64)
+
+	return models.ChannelSearchResult{
+		ID:                metadata.ChannelID,
+		Name:              metadata.Name,
+		Handle:            metadata.Handle,
+		Description:       metadata.Description,
+		ImageURL:          metadata.AvatarURL,
+		SubscriberCount:   subscribers,
+		SubscribersHidden: c.Statistics.HiddenSubscriberCount,
+	}
+}
+
+// SearchChannels searches for channels by name, most relevant first. The search itself only returns channel IDs
+// reliably, so their details are looked up in a second request.
+func (c *youTubeClient) SearchChannels(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("type", "channel")
+	params.Add("part", "id")
+	params.Add("fields", "items/id/channelId")
+	params.Add("maxResults", fmt.Sprint(channelSearchResults))
+
+	c.log.Info("Making request to YouTube API to search channels", "query", query)
+	var searchData APISearchListResponse
+	err := c.getJSON(ctx, quota.CallSearchList, "/youtube/v3/search", params, &searchData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search channels: %w", err)
+	}
+	if len(searchData.Items) == 0 {
+		return []models.ChannelSearchResult{}, nil
+	}
+
+	ids := make([]string, 0, len(searchData.Items))
+	for _, item := range searchData.Items {
+		ids = append(ids, item.ID.ChannelID)
+	}
+	params = url.Values{}
+	params.Add("id", strings.Join(ids, ","))
+	params.Add("part", "snippet,statistics")
+	params.Add(
+		"fields",
+		"items(id,snippet(title,description,customUrl,thumbnails),statistics(subscriberCount,hiddenSubscriberCount))",
+	)
+
+	var channelData APIChannelStatisticsListResponse
+	err = c.getJSON(ctx, quota.CallChannelsList, "/youtube/v3/channels", params, &channelData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch channel details: %w", err)
+	}
+
+	// Keep the order of relevance of the search
+	byID := make(map[string]APIChannelStatistics, len(channelData.Items))
+	for _, item := range channelData.Items {
+		byID[item.ID] = item
+	}
+	results := make([]models.ChannelSearchResult, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			results = append(results, item.toSearchResult())
+		}
+	}
+
+	return results, nil
+}
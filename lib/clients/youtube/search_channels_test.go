@@ -0,0 +1,42 @@
+package youtube
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestToSearchResult(t *testing.T) {
+	const body = `{"items": [
+		{
+			"id": "UC123",
+			"snippet": {
+				"title": "Cooking",
+				"customUrl": "@cooking",
+				"thumbnails": {"medium": {"url": "https://yt3.ggpht.com/medium"}}
+			},
+			"statistics": {"subscriberCount": "1234000", "hiddenSubscriberCount": false}
+		},
+		{"id": "UC456", "snippet": {"title": "Secretive"}, "statistics": {"hiddenSubscriberCount": true}}
+	]}`
+	var resp APIChannelStatisticsListResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Items, 2)
+
+	assert.Equal(t, models.ChannelSearchResult{
+		ID:              "UC123",
+		Name:            "Cooking",
+		Handle:          "@cooking",
+		ImageURL:        "https://yt3.ggpht.com/medium",
+		SubscriberCount: 1234000,
+	}, resp.Items[0].toSearchResult())
+	assert.Equal(t, models.ChannelSearchResult{
+		ID:                "UC456",
+		Name:              "Secretive",
+		SubscribersHidden: true,
+	}, resp.Items[1].toSearchResult())
+}
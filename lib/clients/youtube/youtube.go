@@ -2,28 +2,76 @@ package youtube
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"time"
 
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/quota"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 )
 
+var (
+	ErrChannelNotFound = fmt.Errorf("channel %w", ErrNotFound)
+	ErrVideoNotFound   = fmt.Errorf("video %w", ErrNotFound)
+)
+
 type Client interface {
+	// GetVideoDurations sets the duration and live or upcoming status of the videos. Videos that can't be
+	// watched are marked with the reason instead. Videos that couldn't be looked up are returned in VideoErrors,
+	// the others are set either way.
 	GetVideoDurations(ctx context.Context, videos map[string]*models.Video) error
 	GetVideoMetadata(ctx context.Context, videoID string) (*models.Video, error)
+	// ClassifyShort decides whether a video is a short and returns the signal that decided it. feedHint tells
+	// whether the feed linked the video through its shorts page.
+	ClassifyShort(ctx context.Context, videoID string, feedHint bool, durationSeconds int) (bool, models.ShortSignal)
 	GetChannelImageURL(ctx context.Context, channelID string) (string, error)
+	GetPlaylistImageURL(ctx context.Context, playlistID string) (string, error)
+	// GetChannelsMetadata returns the profiles of up to ChannelsPageSize channels. Channels that no longer
+	// exist are left out.
+	GetChannelsMetadata(ctx context.Context, channelIDs []string) ([]models.ChannelMetadata, error)
+	// ResolveChannelID returns the ID of the channel with the given @handle
 	ResolveChannelID(ctx context.Context, handle string) (string, error)
+	// SearchChannels searches for channels by name, most relevant first. Searching costs 100 quota units.
+	SearchChannels(ctx context.Context, query string) ([]models.ChannelSearchResult, error)
+	// ResolveUsername returns the ID of the channel with the given legacy username, as used by /user/ URLs
+	ResolveUsername(ctx context.Context, username string) (string, error)
+	// QuotaUsage returns the API quota spent today
+	QuotaUsage(ctx context.Context) models.QuotaUsage
+	// ListUploads returns the uploads of a channel published in [after, before), newest first. Zero times
+	// leave the range open.
+	ListUploads(ctx context.Context, channelID string, after time.Time, before time.Time) ([]models.Video, error)
 }
 
 type youTubeClient struct {
 	log    *slog.Logger
 	apiKey string
+	// region is the ISO 3166-1 alpha-2 code of the country videos are watched from, if known
+	region string
+	quota  *quota.Ledger
+
+	httpClient *http.Client
+	// baseURL is where the Data API is served, replaced in tests
+	baseURL string
+	// retryBackoff is the upper bound of the wait before the first retry
+	retryBackoff time.Duration
 }
 
 var _ Client = (*youTubeClient)(nil)
 
-func NewYouTubeClient(log *slog.Logger, apiKey string) *youTubeClient {
+func NewYouTubeClient(log *slog.Logger, apiKey string, region string, ledger *quota.Ledger) *youTubeClient {
 	return &youTubeClient{
 		log:    log,
 		apiKey: apiKey,
+		region: region,
+		quota:  ledger,
+
+		httpClient:   &http.Client{},
+		baseURL:      apiURL,
+		retryBackoff: retryBackoff,
 	}
 }
+
+func (c *youTubeClient) QuotaUsage(ctx context.Context) models.QuotaUsage {
+	return c.quota.Usage(ctx)
+}
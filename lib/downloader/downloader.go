@@ -11,7 +11,7 @@ import (
 const defaultResolution = "1080"
 
 type Downloader interface {
-	Download(ctx context.Context, videoID string, title string, outputDir string, resolution string) (string, error)
+	Download(ctx context.Context, videoID string, videoURL string, outputDir string, resolution string) (string, error)
 	ValidateInstallation() error
 }
 
@@ -41,7 +41,7 @@ func formatSelector(resolution string) string {
 func (d *ytdlpDownloader) Download(
 	ctx context.Context,
 	videoID string,
-	title string,
+	videoURL string,
 	outputDir string,
 	resolution string,
 ) (string, error) {
@@ -58,7 +58,9 @@ func (d *ytdlpDownloader) Download(
 		"--no-warnings",
 		"--quiet",
 		"--progress",
-		fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
+		// Feed supplied URLs must never be read as options
+		"--",
+		videoURL,
 	)
 
 	output, err := cmd.CombinedOutput()
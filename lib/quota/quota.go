@@ -0,0 +1,204 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	// The reset time zone must be known even where the system has no time zone database
+	_ "time/tzdata"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+var (
+	ErrBudgetExhausted = errors.New("daily YouTube API quota budget exhausted")
+	ErrQuotaExceeded   = errors.New("YouTube API quota exceeded")
+)
+
+// Call is a YouTube Data API method
+type Call string
+
+const (
+	CallVideosList        Call = "videos.list"
+	CallChannelsList      Call = "channels.list"
+	CallPlaylistsList     Call = "playlists.list"
+	CallPlaylistItemsList Call = "playlistItems.list"
+	CallSearchList        Call = "search.list"
+)
+
+// costs are the quota units each method spends per request
+var costs = map[Call]int{
+	CallVideosList:        1,
+	CallChannelsList:      1,
+	CallPlaylistsList:     1,
+	CallPlaylistItemsList: 1,
+	CallSearchList:        100,
+}
+
+// Cost returns the quota units a request to the method spends
+func Cost(call Call) int {
+	return costs[call]
+}
+
+// resetLocation is the time zone whose midnight resets the quota
+var resetLocation = mustLoadLocation("America/Los_Angeles")
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// day returns the Pacific date of t and when the day after it starts
+func day(t time.Time) (string, time.Time) {
+	local := t.In(resetLocation)
+	year, month, date := local.Date()
+	return local.Format(time.DateOnly), time.Date(year, month, date+1, 0, 0, 0, 0, resetLocation)
+}
+
+// Store keeps the daily usage, so it survives restarts
+type Store interface {
+	// GetQuotaUsage returns the usage per method on the given day
+	GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error)
+	// AddQuotaUsage records a request to the method on the given day
+	AddQuotaUsage(ctx context.Context, day string, call string, units int) error
+}
+
+// Ledger meters the quota spent on the YouTube Data API per day and refuses calls once the daily budget is
+// reached. A nil Ledger doesn't meter anything.
+type Ledger struct {
+	log    *slog.Logger
+	store  Store
+	budget int
+	now    func() time.Time
+
+	mu        sync.Mutex
+	day       string
+	resetsAt  time.Time
+	used      int
+	exhausted bool
+	calls     map[string]*models.QuotaCallUsage
+}
+
+// NewLedger returns a ledger allowing budget units per day, or any number of units if budget isn't positive
+func NewLedger(log *slog.Logger, store Store, budget int) *Ledger {
+	return &Ledger{
+		log:    log,
+		store:  store,
+		budget: budget,
+		now:    time.Now,
+	}
+}
+
+// rollover starts a new day once the quota was reset, picking up what was already spent on it. It must be
+// called with mu held.
+func (l *Ledger) rollover(ctx context.Context) {
+	today, resetsAt := day(l.now())
+	if today == l.day {
+		return
+	}
+
+	l.day = today
+	l.resetsAt = resetsAt
+	l.used = 0
+	l.exhausted = false
+	l.calls = make(map[string]*models.QuotaCallUsage)
+	stored, err := l.store.GetQuotaUsage(ctx, today)
+	if err != nil {
+		l.log.Error("Failed to load quota usage", "call", "store.GetQuotaUsage", "error", err)
+		return
+	}
+	for _, usage := range stored {
+		l.calls[usage.Call] = &usage
+		l.used += usage.Units
+	}
+	l.exhausted = l.budget > 0 && l.used >= l.budget
+}
+
+// Spend records a request to the method, or returns ErrBudgetExhausted if it would go over the day's budget
+func (l *Ledger) Spend(ctx context.Context, call Call) error {
+	if l == nil {
+		return nil
+	}
+	units := Cost(call)
+
+	l.mu.Lock()
+	l.rollover(ctx)
+	if l.exhausted || (l.budget > 0 && l.used+units > l.budget) {
+		resetsAt := l.resetsAt
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s needs %d units, resets at %s", ErrBudgetExhausted, call, units, resetsAt)
+	}
+	l.used += units
+	usage, ok := l.calls[string(call)]
+	if !ok {
+		usage = &models.QuotaCallUsage{Call: string(call)}
+		l.calls[string(call)] = usage
+	}
+	usage.Requests++
+	usage.Units += units
+	today := l.day
+	l.mu.Unlock()
+
+	err := l.store.AddQuotaUsage(ctx, today, string(call), units)
+	if err != nil {
+		l.log.Error("Failed to store quota usage", "call", "store.AddQuotaUsage", "error", err)
+	}
+
+	return nil
+}
+
+// Exhaust stops all calls until the next reset, for when the API reports the quota as exceeded before the
+// budget was reached
+func (l *Ledger) Exhaust(ctx context.Context) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover(ctx)
+	if !l.exhausted {
+		l.log.Warn("YouTube API quota exceeded, pausing API calls until the reset", "resetsAt", l.resetsAt)
+	}
+	l.exhausted = true
+}
+
+// Usage returns what was spent today
+func (l *Ledger) Usage(ctx context.Context) models.QuotaUsage {
+	if l == nil {
+		today, resetsAt := day(time.Now())
+		return models.QuotaUsage{Day: today, ResetsAt: resetsAt, Calls: []models.QuotaCallUsage{}}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover(ctx)
+
+	calls := make([]models.QuotaCallUsage, 0, len(l.calls))
+	for _, usage := range l.calls {
+		calls = append(calls, *usage)
+	}
+	slices.SortFunc(calls, func(a, b models.QuotaCallUsage) int {
+		if a.Units != b.Units {
+			return b.Units - a.Units
+		}
+		return strings.Compare(a.Call, b.Call)
+	})
+
+	return models.QuotaUsage{
+		Day:       l.day,
+		Budget:    l.budget,
+		Used:      l.used,
+		Exhausted: l.exhausted || (l.budget > 0 && l.used >= l.budget),
+		ResetsAt:  l.resetsAt,
+		Calls:     calls,
+	}
+}
@@ -0,0 +1,160 @@
+package quota
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+// memoryStore keeps the usage in memory, keyed by day and method
+type memoryStore struct {
+	usage map[string]map[string]*models.QuotaCallUsage
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{usage: make(map[string]map[string]*models.QuotaCallUsage)}
+}
+
+func (s *memoryStore) GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error) {
+	usage := make([]models.QuotaCallUsage, 0, len(s.usage[day]))
+	for _, call := range s.usage[day] {
+		usage = append(usage, *call)
+	}
+	return usage, nil
+}
+
+func (s *memoryStore) AddQuotaUsage(ctx context.Context, day string, call string, units int) error {
+	if s.usage[day] == nil {
+		s.usage[day] = make(map[string]*models.QuotaCallUsage)
+	}
+	usage, ok := s.usage[day][call]
+	if !ok {
+		usage = &models.QuotaCallUsage{Call: call}
+		s.usage[day][call] = usage
+	}
+	usage.Requests++
+	usage.Units += units
+	return nil
+}
+
+func newTestLedger(store Store, budget int, now *time.Time) *Ledger {
+	ledger := NewLedger(slog.New(slog.NewTextHandler(io.Discard, nil)), store, budget)
+	ledger.now = func() time.Time { return *now }
+	return ledger
+}
+
+func TestDay(t *testing.T) {
+	tests := []struct {
+		name         string
+		time         time.Time
+		wantDay      string
+		wantResetsAt time.Time
+	}{
+		{
+			name:         "before Pacific midnight",
+			time:         time.Date(2026, 10, 18, 6, 59, 0, 0, time.UTC),
+			wantDay:      "2026-10-17",
+			wantResetsAt: time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "after Pacific midnight",
+			time:         time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC),
+			wantDay:      "2026-10-18",
+			wantResetsAt: time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "standard time",
+			time:         time.Date(2026, 12, 1, 12, 0, 0, 0, time.UTC),
+			wantDay:      "2026-12-01",
+			wantResetsAt: time.Date(2026, 12, 2, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDay, gotResetsAt := day(tt.time)
+			assert.Equal(t, tt.wantDay, gotDay)
+			assert.True(t, tt.wantResetsAt.Equal(gotResetsAt), "resets at %s", gotResetsAt)
+		})
+	}
+}
+
+func TestLedgerRefusesCallsOverBudget(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore()
+	ledger := newTestLedger(store, 102, &now)
+
+	require.NoError(t, ledger.Spend(ctx, CallSearchList))
+	require.NoError(t, ledger.Spend(ctx, CallVideosList))
+	// A search doesn't fit anymore, a cheaper call does
+	require.ErrorIs(t, ledger.Spend(ctx, CallSearchList), ErrBudgetExhausted)
+	require.NoError(t, ledger.Spend(ctx, CallChannelsList))
+	require.ErrorIs(t, ledger.Spend(ctx, CallVideosList), ErrBudgetExhausted)
+
+	usage := ledger.Usage(ctx)
+	assert.Equal(t, "2026-10-18", usage.Day)
+	assert.Equal(t, 102, usage.Used)
+	assert.True(t, usage.Exhausted)
+	assert.Equal(t, 100, usage.UsedPercentage())
+	assert.Equal(t, []models.QuotaCallUsage{
+		{Call: "search.list", Requests: 1, Units: 100},
+		{Call: "channels.list", Requests: 1, Units: 1},
+		{Call: "videos.list", Requests: 1, Units: 1},
+	}, usage.Calls)
+	// Refused calls aren't recorded
+	assert.Equal(t, 1, store.usage["2026-10-18"]["search.list"].Requests)
+
+	// The quota resets at midnight Pacific time
+	now = time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	require.NoError(t, ledger.Spend(ctx, CallSearchList))
+	usage = ledger.Usage(ctx)
+	assert.Equal(t, "2026-10-19", usage.Day)
+	assert.Equal(t, 100, usage.Used)
+	assert.False(t, usage.Exhausted)
+}
+
+func TestLedgerExhaust(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ledger := newTestLedger(newMemoryStore(), 0, &now)
+
+	require.NoError(t, ledger.Spend(ctx, CallVideosList))
+	ledger.Exhaust(ctx)
+	require.ErrorIs(t, ledger.Spend(ctx, CallVideosList), ErrBudgetExhausted)
+	assert.True(t, ledger.Usage(ctx).Exhausted)
+
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, ledger.Spend(ctx, CallVideosList))
+}
+
+func TestLedgerLoadsStoredUsage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore()
+	require.NoError(t, store.AddQuotaUsage(ctx, "2026-10-18", "search.list", 100))
+	require.NoError(t, store.AddQuotaUsage(ctx, "2026-10-17", "search.list", 100))
+	ledger := newTestLedger(store, 100, &now)
+
+	require.ErrorIs(t, ledger.Spend(ctx, CallVideosList), ErrBudgetExhausted)
+	assert.Equal(t, 100, ledger.Usage(ctx).Used)
+}
+
+func TestNilLedger(t *testing.T) {
+	var ledger *Ledger
+	ctx := context.Background()
+
+	require.NoError(t, ledger.Spend(ctx, CallSearchList))
+	ledger.Exhaust(ctx)
+	usage := ledger.Usage(ctx)
+	assert.Zero(t, usage.Used)
+	assert.False(t, usage.Exhausted)
+	assert.Empty(t, usage.Calls)
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HostLimiter spaces out requests so that no host gets more than a set number of requests per second.
+// Requests to different hosts don't wait for each other. A nil HostLimiter doesn't limit anything.
+type HostLimiter struct {
+	interval time.Duration
+
+	mu sync.Mutex
+	// next is the earliest time the next request to each host may be sent
+	next map[string]time.Time
+}
+
+// NewHostLimiter returns a limiter allowing requestsPerSecond requests to each host, or nil if
+// requestsPerSecond isn't positive
+func NewHostLimiter(requestsPerSecond float64) *HostLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	return &HostLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		next:     make(map[string]time.Time),
+	}
+}
+
+// Wait blocks until a request to host may be sent, or returns the context's error if it's done first
+func (l *HostLimiter) Wait(ctx context.Context, host string) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostLimiterSpacesRequestsToTheSameHost(t *testing.T) {
+	limiter := NewHostLimiter(20)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, limiter.Wait(ctx, "www.youtube.com"))
+	}
+	// The first request goes out right away, the other two wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestHostLimiterDoesNotDelayOtherHosts(t *testing.T) {
+	limiter := NewHostLimiter(1)
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(ctx, "www.youtube.com"))
+	require.NoError(t, limiter.Wait(ctx, "peertube.example.com"))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestHostLimiterStopsWaitingWhenContextIsDone(t *testing.T) {
+	limiter := NewHostLimiter(0.1)
+	require.NoError(t, limiter.Wait(context.Background(), "www.youtube.com"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx, "www.youtube.com"), context.DeadlineExceeded)
+}
+
+func TestNilHostLimiterDoesNotLimit(t *testing.T) {
+	limiter := NewHostLimiter(0)
+	assert.Nil(t, limiter)
+
+	start := time.Now()
+	for range 10 {
+		require.NoError(t, limiter.Wait(context.Background(), "www.youtube.com"))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
@@ -0,0 +1,138 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeSubscribe   = "subscribe"
+	ModeUnsubscribe = "unsubscribe"
+)
+
+// Client sends subscription requests to a WebSub hub
+type Client interface {
+	// Subscribe asks the hub to start pushing updates of topic to callback. The hub verifies the request
+	// asynchronously by calling the callback with a challenge.
+	Subscribe(ctx context.Context, topic string, callback string, secret string, lease time.Duration) error
+	// Unsubscribe asks the hub to stop pushing updates of topic to callback
+	Unsubscribe(ctx context.Context, topic string, callback string) error
+}
+
+type client struct {
+	log    *slog.Logger
+	hubURL string
+}
+
+var _ Client = (*client)(nil)
+
+func NewClient(log *slog.Logger, hubURL string) *client {
+	return &client{
+		log:    log,
+		hubURL: hubURL,
+	}
+}
+
+func (c *client) request(ctx context.Context, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.hubURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to set up request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	c.log.Info("Sending request to WebSub hub", "mode", form.Get("hub.mode"), "topic", form.Get("hub.topic"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to hub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Hubs respond with 202 Accepted and verify the intent asynchronously, some respond with 204 if they
+	// verified it synchronously
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, err := io.ReadAll(resp.Body)
+		var bodyStr string
+		if err != nil {
+			bodyStr = "failed to decode body"
+		} else {
+			bodyStr = string(body)
+		}
+		return fmt.Errorf("got unexpected status from WebSub hub [%d]: %v", resp.StatusCode, bodyStr)
+	}
+
+	return nil
+}
+
+func (c *client) Subscribe(
+	ctx context.Context, topic string, callback string, secret string, lease time.Duration,
+) error {
+	form := url.Values{}
+	form.Set("hub.mode", ModeSubscribe)
+	form.Set("hub.topic", topic)
+	form.Set("hub.callback", callback)
+	form.Set("hub.verify", "async")
+	form.Set("hub.lease_seconds", strconv.Itoa(int(lease.Seconds())))
+	if secret != "" {
+		form.Set("hub.secret", secret)
+	}
+
+	return c.request(ctx, form)
+}
+
+func (c *client) Unsubscribe(ctx context.Context, topic string, callback string) error {
+	form := url.Values{}
+	form.Set("hub.mode", ModeUnsubscribe)
+	form.Set("hub.topic", topic)
+	form.Set("hub.callback", callback)
+	form.Set("hub.verify", "async")
+
+	return c.request(ctx, form)
+}
+
+// VerifySignature checks the X-Hub-Signature header of a content distribution request against the body
+// signed with the subscription secret. Nothing is valid without a secret, since anyone could sign with it.
+func VerifySignature(secret string, signature string, body []byte) bool {
+	if secret == "" {
+		return false
+	}
+	method, signatureHex, found := strings.Cut(signature, "=")
+	if !found {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch method {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	expected, err := hex.DecodeString(signatureHex)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,117 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// standInHub plays the role of the Google hub: it verifies the intent of every subscription request with
+// the subscriber and then pushes a signed notification to it
+type standInHub struct {
+	t       *testing.T
+	payload string
+	done    chan struct{}
+}
+
+func (hub *standInHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	require.NoError(hub.t, r.ParseForm())
+	callback := r.PostForm.Get("hub.callback")
+	topic := r.PostForm.Get("hub.topic")
+	secret := r.PostForm.Get("hub.secret")
+	w.WriteHeader(http.StatusAccepted)
+
+	go func() {
+		defer close(hub.done)
+
+		query := url.Values{}
+		query.Set("hub.mode", r.PostForm.Get("hub.mode"))
+		query.Set("hub.topic", topic)
+		query.Set("hub.challenge", "challenge-123")
+		query.Set("hub.lease_seconds", r.PostForm.Get("hub.lease_seconds"))
+		resp, err := http.Get(callback + "?" + query.Encode())
+		require.NoError(hub.t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "challenge-123" {
+			return
+		}
+
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write([]byte(hub.payload))
+		req, err := http.NewRequest(http.MethodPost, callback, strings.NewReader(hub.payload))
+		require.NoError(hub.t, err)
+		req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(hub.t, err)
+		resp.Body.Close()
+	}()
+}
+
+func TestSubscribeWithStandInHub(t *testing.T) {
+	const secret = "s3cret"
+	const payload = "<feed></feed>"
+
+	var verifiedLease string
+	received := make(chan string, 1)
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			verifiedLease = r.URL.Query().Get("hub.lease_seconds")
+			w.Write([]byte(r.URL.Query().Get("hub.challenge")))
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			if VerifySignature(secret, r.Header.Get("X-Hub-Signature"), body) {
+				received <- string(body)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer subscriber.Close()
+
+	hub := &standInHub{t: t, payload: payload, done: make(chan struct{})}
+	hubServer := httptest.NewServer(hub)
+	defer hubServer.Close()
+
+	c := NewClient(slog.New(slog.NewTextHandler(io.Discard, nil)), hubServer.URL)
+	err := c.Subscribe(context.Background(), "https://example.com/topic", subscriber.URL, secret, time.Hour)
+	require.NoError(t, err)
+
+	select {
+	case body := <-received:
+		assert.Equal(t, payload, body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not receive notification from hub")
+	}
+	<-hub.done
+	assert.Equal(t, "3600", verifiedLease)
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte("hello")
+	mac := hmac.New(sha1.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha1=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.True(t, VerifySignature("secret", signature, body))
+	assert.False(t, VerifySignature("other", signature, body))
+	assert.False(t, VerifySignature("secret", signature, []byte("tampered")))
+	assert.False(t, VerifySignature("secret", "md5=abc", body))
+	assert.False(t, VerifySignature("secret", "garbage", body))
+
+	unsigned := hmac.New(sha1.New, nil)
+	unsigned.Write(body)
+	assert.False(t, VerifySignature("", "sha1="+hex.EncodeToString(unsigned.Sum(nil)), body))
+}
@@ -0,0 +1,2 @@
+ALTER TABLE channels DROP COLUMN IF EXISTS feed_last_modified;
+ALTER TABLE channels DROP COLUMN IF EXISTS feed_etag;
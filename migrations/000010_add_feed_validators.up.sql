@@ -0,0 +1,2 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS feed_etag TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS feed_last_modified TEXT;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS videos_channel_published_idx;
+
+ALTER TABLE channels DROP COLUMN IF EXISTS max_poll_interval;
+ALTER TABLE channels DROP COLUMN IF EXISTS min_poll_interval;
+ALTER TABLE channels DROP COLUMN IF EXISTS consecutive_failures;
+ALTER TABLE channels DROP COLUMN IF EXISTS last_polled_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS next_poll_at;
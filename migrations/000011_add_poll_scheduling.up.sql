@@ -0,0 +1,7 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS next_poll_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS last_polled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS min_poll_interval INTEGER;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS max_poll_interval INTEGER;
+
+CREATE INDEX IF NOT EXISTS videos_channel_published_idx ON videos (channel_id, published_timestamp DESC);
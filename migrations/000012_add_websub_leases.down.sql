@@ -0,0 +1,2 @@
+ALTER TABLE channels DROP COLUMN IF EXISTS websub_lease_expires_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS websub_requested_at;
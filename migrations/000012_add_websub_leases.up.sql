@@ -0,0 +1,2 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS websub_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS websub_lease_expires_at TIMESTAMP WITH TIME ZONE;
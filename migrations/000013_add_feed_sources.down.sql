@@ -0,0 +1,5 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS thumbnail_url;
+ALTER TABLE videos DROP COLUMN IF EXISTS url;
+ALTER TABLE channels DROP COLUMN IF EXISTS site_url;
+ALTER TABLE channels DROP COLUMN IF EXISTS feed_url;
+ALTER TABLE channels DROP COLUMN IF EXISTS source_type;
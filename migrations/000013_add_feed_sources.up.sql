@@ -0,0 +1,5 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS source_type TEXT NOT NULL DEFAULT 'youtube';
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS feed_url TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS site_url TEXT;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS url TEXT;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
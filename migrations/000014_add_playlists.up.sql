@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS playlist_videos (
+	playlist_id text NOT NULL REFERENCES channels(id) ON DELETE CASCADE
+	, video_id text NOT NULL REFERENCES videos(id) ON DELETE CASCADE
+	, CONSTRAINT playlist_videos_pkey PRIMARY KEY (playlist_id, video_id)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE channels DROP COLUMN IF EXISTS gone_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS consecutive_not_found;
+ALTER TABLE channels DROP COLUMN IF EXISTS last_new_video_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS last_fetch_error_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS last_fetch_error;
+ALTER TABLE channels DROP COLUMN IF EXISTS last_fetch_success_at;
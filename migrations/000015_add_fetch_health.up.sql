@@ -0,0 +1,6 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS last_fetch_success_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS last_fetch_error TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS last_fetch_error_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS last_new_video_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS consecutive_not_found INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS gone_at TIMESTAMP WITH TIME ZONE;
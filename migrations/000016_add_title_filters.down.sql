@@ -0,0 +1,2 @@
+ALTER TABLE channels DROP COLUMN IF EXISTS title_exclude;
+ALTER TABLE channels DROP COLUMN IF EXISTS title_include;
@@ -0,0 +1,2 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS title_include TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS title_exclude TEXT[] NOT NULL DEFAULT '{}';
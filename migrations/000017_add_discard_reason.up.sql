@@ -0,0 +1,4 @@
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS discard_reason TEXT;
+-- Until now videos were only discarded by the shorts filter and the title filters
+UPDATE videos SET discard_reason = CASE WHEN is_short THEN 'shorts' ELSE 'title_filter' END
+	WHERE is_discarded = true AND discard_reason IS NULL;
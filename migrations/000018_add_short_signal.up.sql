@@ -0,0 +1 @@
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS short_signal TEXT;
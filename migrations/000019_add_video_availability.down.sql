@@ -0,0 +1,2 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS unavailable_since;
+ALTER TABLE videos DROP COLUMN IF EXISTS unavailable_reason;
@@ -0,0 +1,2 @@
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS unavailable_reason TEXT;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS unavailable_since TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS video_revisions (
+	id serial PRIMARY KEY
+	, video_id text NOT NULL REFERENCES videos(id) ON DELETE CASCADE
+	, title text NOT NULL
+	, thumbnail_url text
+	, revised_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS video_revisions_video_id_idx ON video_revisions (video_id);
@@ -0,0 +1,2 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS scheduled_start_time;
+ALTER TABLE videos DROP COLUMN IF EXISTS is_upcoming;
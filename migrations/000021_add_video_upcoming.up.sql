@@ -0,0 +1,2 @@
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS is_upcoming BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS scheduled_start_time TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,4 @@
+ALTER TABLE videos DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE videos DROP COLUMN IF EXISTS rating_average;
+ALTER TABLE videos DROP COLUMN IF EXISTS view_count;
+ALTER TABLE videos DROP COLUMN IF EXISTS description;
@@ -0,0 +1,4 @@
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS view_count BIGINT;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS rating_average DOUBLE PRECISION;
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS rating_count BIGINT;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS channel_revisions;
+ALTER TABLE channels DROP COLUMN IF EXISTS metadata_refreshed_at;
+ALTER TABLE channels DROP COLUMN IF EXISTS banner_url;
+ALTER TABLE channels DROP COLUMN IF EXISTS description;
+ALTER TABLE channels DROP COLUMN IF EXISTS handle;
@@ -0,0 +1,13 @@
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS handle TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS banner_url TEXT;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS metadata_refreshed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS channel_revisions (
+	id serial PRIMARY KEY
+	, channel_id text NOT NULL REFERENCES channels(id) ON DELETE CASCADE
+	, name text NOT NULL
+	, handle text
+	, revised_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS channel_revisions_channel_id_idx ON channel_revisions (channel_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS videos_duration_pending_idx;
+ALTER TABLE videos DROP COLUMN IF EXISTS duration_pending;
+DROP TABLE IF EXISTS quota_usage;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS quota_usage (
+	day date NOT NULL
+	, call text NOT NULL
+	, requests integer NOT NULL DEFAULT 0
+	, units integer NOT NULL DEFAULT 0
+	, PRIMARY KEY (day, call)
+);
+
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS duration_pending BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS videos_duration_pending_idx ON videos (id) WHERE duration_pending;
@@ -21,42 +21,120 @@ var _ db.DB = &DBMock{}
 //
 //		// make and configure a mocked db.DB
 //		mockedDB := &DBMock{
-//			AddVideoFunc: func(ctx context.Context, video models.Video, channelID string, isDiscarded bool) error {
+//			AddChannelsFunc: func(ctx context.Context, channels []models.Channel) error {
+//				panic("mock out the AddChannels method")
+//			},
+//			AddPlaylistVideosFunc: func(ctx context.Context, playlistID string, videoIDs []string) error {
+//				panic("mock out the AddPlaylistVideos method")
+//			},
+//			AddQuotaUsageFunc: func(ctx context.Context, day string, call string, units int) error {
+//				panic("mock out the AddQuotaUsage method")
+//			},
+//			AddVideoFunc: func(ctx context.Context, video models.Video, channelID string, discardReason models.DiscardReason) error {
 //				panic("mock out the AddVideo method")
 //			},
+//			AddVideosFunc: func(ctx context.Context, videos []models.Video) ([]string, error) {
+//				panic("mock out the AddVideos method")
+//			},
 //			CloseFunc: func()  {
 //				panic("mock out the Close method")
 //			},
 //			DeleteVideoFileFunc: func(ctx context.Context, videoID string) error {
 //				panic("mock out the DeleteVideoFile method")
 //			},
-//			DiscardVideoFunc: func(ctx context.Context, videoID string) error {
+//			DiscardVideoFunc: func(ctx context.Context, videoID string, reason models.DiscardReason) error {
 //				panic("mock out the DiscardVideo method")
 //			},
+//			ExpireUnwatchedVideosFunc: func(ctx context.Context, publishedBefore time.Time) (int64, error) {
+//				panic("mock out the ExpireUnwatchedVideos method")
+//			},
 //			GetChannelByIDFunc: func(ctx context.Context, channelID string) (*models.Channel, error) {
 //				panic("mock out the GetChannelByID method")
 //			},
+//			GetDiscardedVideosFunc: func(ctx context.Context, limit int, offset int) ([]models.Video, error) {
+//				panic("mock out the GetDiscardedVideos method")
+//			},
+//			GetKnownVideoIDsFunc: func(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+//				panic("mock out the GetKnownVideoIDs method")
+//			},
+//			GetLastPublishedBeforeFunc: func(ctx context.Context, channelID string, before time.Time) (*time.Time, error) {
+//				panic("mock out the GetLastPublishedBefore method")
+//			},
 //			GetLiveVideosFunc: func(ctx context.Context) ([]models.Video, error) {
 //				panic("mock out the GetLiveVideos method")
 //			},
-//			GetNewVideosFunc: func(ctx context.Context, sortDesc bool) ([]models.Video, error) {
+//			GetNewVideosFunc: func(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error) {
 //				panic("mock out the GetNewVideos method")
 //			},
+//			GetQuotaUsageFunc: func(ctx context.Context, day string) ([]models.QuotaCallUsage, error) {
+//				panic("mock out the GetQuotaUsage method")
+//			},
+//			GetUnwatchedChannelVideosFunc: func(ctx context.Context, channelID string, discarded bool) ([]models.Video, error) {
+//				panic("mock out the GetUnwatchedChannelVideos method")
+//			},
 //			GetVideoFunc: func(ctx context.Context, videoID string) (*models.Video, error) {
 //				panic("mock out the GetVideo method")
 //			},
+//			GetVideosForAvailabilityCheckFunc: func(ctx context.Context) ([]models.Video, error) {
+//				panic("mock out the GetVideosForAvailabilityCheck method")
+//			},
 //			GetVideosForCleanupFunc: func(ctx context.Context, olderThan time.Duration) ([]models.Video, error) {
 //				panic("mock out the GetVideosForCleanup method")
 //			},
+//			GetVideosPendingDurationFunc: func(ctx context.Context) ([]models.Video, error) {
+//				panic("mock out the GetVideosPendingDuration method")
+//			},
 //			GetWatchedVideosFunc: func(ctx context.Context, sortDesc bool, limit int, offset int) ([]models.Video, error) {
 //				panic("mock out the GetWatchedVideos method")
 //			},
 //			HasVideoFunc: func(ctx context.Context, videoID string) (bool, error) {
 //				panic("mock out the HasVideo method")
 //			},
+//			ListChannelPollStatesFunc: func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+//				panic("mock out the ListChannelPollStates method")
+//			},
 //			ListChannelsFunc: func(ctx context.Context) ([]models.Channel, error) {
 //				panic("mock out the ListChannels method")
 //			},
+//			ListChannelsDueForPollFunc: func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+//				panic("mock out the ListChannelsDueForPoll method")
+//			},
+//			ListChannelsForMetadataRefreshFunc: func(ctx context.Context, refreshedBefore time.Time) ([]models.Channel, error) {
+//				panic("mock out the ListChannelsForMetadataRefresh method")
+//			},
+//			ListChannelsForWebSubRenewalFunc: func(ctx context.Context, expiringBefore time.Time, requestedBefore time.Time) ([]models.Channel, error) {
+//				panic("mock out the ListChannelsForWebSubRenewal method")
+//			},
+//			RecordChannelFetchFunc: func(ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int) error {
+//				panic("mock out the RecordChannelFetch method")
+//			},
+//			RestoreVideoFunc: func(ctx context.Context, videoID string) error {
+//				panic("mock out the RestoreVideo method")
+//			},
+//			SetChannelFeedValidatorsFunc: func(ctx context.Context, channelID string, etag string, lastModified string) error {
+//				panic("mock out the SetChannelFeedValidators method")
+//			},
+//			SetChannelPollIntervalsFunc: func(ctx context.Context, channelID string, minInterval time.Duration, maxInterval time.Duration) error {
+//				panic("mock out the SetChannelPollIntervals method")
+//			},
+//			SetChannelPollResultFunc: func(ctx context.Context, channelID string, nextPollAt time.Time) error {
+//				panic("mock out the SetChannelPollResult method")
+//			},
+//			SetChannelTitleFiltersFunc: func(ctx context.Context, channelID string, include []string, exclude []string) error {
+//				panic("mock out the SetChannelTitleFilters method")
+//			},
+//			SetChannelWebSubLeaseFunc: func(ctx context.Context, channelID string, expiresAt *time.Time) error {
+//				panic("mock out the SetChannelWebSubLease method")
+//			},
+//			SetChannelWebSubRequestedFunc: func(ctx context.Context, channelID string) error {
+//				panic("mock out the SetChannelWebSubRequested method")
+//			},
+//			SetChannelsMetadataRefreshedFunc: func(ctx context.Context, channelIDs []string) error {
+//				panic("mock out the SetChannelsMetadataRefreshed method")
+//			},
+//			SetVideoAvailabilityFunc: func(ctx context.Context, videoID string, reason models.UnavailableReason) error {
+//				panic("mock out the SetVideoAvailability method")
+//			},
 //			SetVideoDownloadCompletedFunc: func(ctx context.Context, videoID string, filePath string) error {
 //				panic("mock out the SetVideoDownloadCompleted method")
 //			},
@@ -66,12 +144,21 @@ var _ db.DB = &DBMock{}
 //			SetVideoDownloadStatusFunc: func(ctx context.Context, videoID string, status string) error {
 //				panic("mock out the SetVideoDownloadStatus method")
 //			},
+//			SetVideoDurationFunc: func(ctx context.Context, video models.Video) error {
+//				panic("mock out the SetVideoDuration method")
+//			},
 //			SetVideoProgressFunc: func(ctx context.Context, videoID string, progress int) (*models.Video, error) {
 //				panic("mock out the SetVideoProgress method")
 //			},
+//			SetVideoShortOverrideFunc: func(ctx context.Context, videoID string, isShort bool) error {
+//				panic("mock out the SetVideoShortOverride method")
+//			},
 //			SetVideoWatchTimeFunc: func(ctx context.Context, videoID string, watchTime *time.Time) error {
 //				panic("mock out the SetVideoWatchTime method")
 //			},
+//			SetVideoWatchTimesFunc: func(ctx context.Context, watchTimes map[string]time.Time) error {
+//				panic("mock out the SetVideoWatchTimes method")
+//			},
 //			SubscribeToChannelFunc: func(ctx context.Context, channel models.Channel) error {
 //				panic("mock out the SubscribeToChannel method")
 //			},
@@ -81,7 +168,13 @@ var _ db.DB = &DBMock{}
 //			UnsubscribeFromChannelFunc: func(ctx context.Context, channelID string) error {
 //				panic("mock out the UnsubscribeFromChannel method")
 //			},
-//			UpdateVideoLiveStatusFunc: func(ctx context.Context, videoID string, isLive bool, duration int) error {
+//			UpdateChannelMetadataFunc: func(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error) {
+//				panic("mock out the UpdateChannelMetadata method")
+//			},
+//			UpdateKnownVideosFunc: func(ctx context.Context, videos []models.Video) ([]string, error) {
+//				panic("mock out the UpdateKnownVideos method")
+//			},
+//			UpdateVideoLiveStatusFunc: func(ctx context.Context, video models.Video) error {
 //				panic("mock out the UpdateVideoLiveStatus method")
 //			},
 //		}
@@ -91,8 +184,20 @@ var _ db.DB = &DBMock{}
 //
 //	}
 type DBMock struct {
+	// AddChannelsFunc mocks the AddChannels method.
+	AddChannelsFunc func(ctx context.Context, channels []models.Channel) error
+
+	// AddPlaylistVideosFunc mocks the AddPlaylistVideos method.
+	AddPlaylistVideosFunc func(ctx context.Context, playlistID string, videoIDs []string) error
+
+	// AddQuotaUsageFunc mocks the AddQuotaUsage method.
+	AddQuotaUsageFunc func(ctx context.Context, day string, call string, units int) error
+
 	// AddVideoFunc mocks the AddVideo method.
-	AddVideoFunc func(ctx context.Context, video models.Video, channelID string, isDiscarded bool) error
+	AddVideoFunc func(ctx context.Context, video models.Video, channelID string, discardReason models.DiscardReason) error
+
+	// AddVideosFunc mocks the AddVideos method.
+	AddVideosFunc func(ctx context.Context, videos []models.Video) ([]string, error)
 
 	// CloseFunc mocks the Close method.
 	CloseFunc func()
@@ -101,32 +206,98 @@ type DBMock struct {
 	DeleteVideoFileFunc func(ctx context.Context, videoID string) error
 
 	// DiscardVideoFunc mocks the DiscardVideo method.
-	DiscardVideoFunc func(ctx context.Context, videoID string) error
+	DiscardVideoFunc func(ctx context.Context, videoID string, reason models.DiscardReason) error
+
+	// ExpireUnwatchedVideosFunc mocks the ExpireUnwatchedVideos method.
+	ExpireUnwatchedVideosFunc func(ctx context.Context, publishedBefore time.Time) (int64, error)
 
 	// GetChannelByIDFunc mocks the GetChannelByID method.
 	GetChannelByIDFunc func(ctx context.Context, channelID string) (*models.Channel, error)
 
+	// GetDiscardedVideosFunc mocks the GetDiscardedVideos method.
+	GetDiscardedVideosFunc func(ctx context.Context, limit int, offset int) ([]models.Video, error)
+
+	// GetKnownVideoIDsFunc mocks the GetKnownVideoIDs method.
+	GetKnownVideoIDsFunc func(ctx context.Context, videoIDs []string) (map[string]bool, error)
+
+	// GetLastPublishedBeforeFunc mocks the GetLastPublishedBefore method.
+	GetLastPublishedBeforeFunc func(ctx context.Context, channelID string, before time.Time) (*time.Time, error)
+
 	// GetLiveVideosFunc mocks the GetLiveVideos method.
 	GetLiveVideosFunc func(ctx context.Context) ([]models.Video, error)
 
 	// GetNewVideosFunc mocks the GetNewVideos method.
-	GetNewVideosFunc func(ctx context.Context, sortDesc bool) ([]models.Video, error)
+	GetNewVideosFunc func(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error)
+
+	// GetQuotaUsageFunc mocks the GetQuotaUsage method.
+	GetQuotaUsageFunc func(ctx context.Context, day string) ([]models.QuotaCallUsage, error)
+
+	// GetUnwatchedChannelVideosFunc mocks the GetUnwatchedChannelVideos method.
+	GetUnwatchedChannelVideosFunc func(ctx context.Context, channelID string, discarded bool) ([]models.Video, error)
 
 	// GetVideoFunc mocks the GetVideo method.
 	GetVideoFunc func(ctx context.Context, videoID string) (*models.Video, error)
 
+	// GetVideosForAvailabilityCheckFunc mocks the GetVideosForAvailabilityCheck method.
+	GetVideosForAvailabilityCheckFunc func(ctx context.Context) ([]models.Video, error)
+
 	// GetVideosForCleanupFunc mocks the GetVideosForCleanup method.
 	GetVideosForCleanupFunc func(ctx context.Context, olderThan time.Duration) ([]models.Video, error)
 
+	// GetVideosPendingDurationFunc mocks the GetVideosPendingDuration method.
+	GetVideosPendingDurationFunc func(ctx context.Context) ([]models.Video, error)
+
 	// GetWatchedVideosFunc mocks the GetWatchedVideos method.
 	GetWatchedVideosFunc func(ctx context.Context, sortDesc bool, limit int, offset int) ([]models.Video, error)
 
 	// HasVideoFunc mocks the HasVideo method.
 	HasVideoFunc func(ctx context.Context, videoID string) (bool, error)
 
+	// ListChannelPollStatesFunc mocks the ListChannelPollStates method.
+	ListChannelPollStatesFunc func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error)
+
 	// ListChannelsFunc mocks the ListChannels method.
 	ListChannelsFunc func(ctx context.Context) ([]models.Channel, error)
 
+	// ListChannelsDueForPollFunc mocks the ListChannelsDueForPoll method.
+	ListChannelsDueForPollFunc func(ctx context.Context, now time.Time) ([]models.ChannelPollState, error)
+
+	// ListChannelsForMetadataRefreshFunc mocks the ListChannelsForMetadataRefresh method.
+	ListChannelsForMetadataRefreshFunc func(ctx context.Context, refreshedBefore time.Time) ([]models.Channel, error)
+
+	// ListChannelsForWebSubRenewalFunc mocks the ListChannelsForWebSubRenewal method.
+	ListChannelsForWebSubRenewalFunc func(ctx context.Context, expiringBefore time.Time, requestedBefore time.Time) ([]models.Channel, error)
+
+	// RecordChannelFetchFunc mocks the RecordChannelFetch method.
+	RecordChannelFetchFunc func(ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int) error
+
+	// RestoreVideoFunc mocks the RestoreVideo method.
+	RestoreVideoFunc func(ctx context.Context, videoID string) error
+
+	// SetChannelFeedValidatorsFunc mocks the SetChannelFeedValidators method.
+	SetChannelFeedValidatorsFunc func(ctx context.Context, channelID string, etag string, lastModified string) error
+
+	// SetChannelPollIntervalsFunc mocks the SetChannelPollIntervals method.
+	SetChannelPollIntervalsFunc func(ctx context.Context, channelID string, minInterval time.Duration, maxInterval time.Duration) error
+
+	// SetChannelPollResultFunc mocks the SetChannelPollResult method.
+	SetChannelPollResultFunc func(ctx context.Context, channelID string, nextPollAt time.Time) error
+
+	// SetChannelTitleFiltersFunc mocks the SetChannelTitleFilters method.
+	SetChannelTitleFiltersFunc func(ctx context.Context, channelID string, include []string, exclude []string) error
+
+	// SetChannelWebSubLeaseFunc mocks the SetChannelWebSubLease method.
+	SetChannelWebSubLeaseFunc func(ctx context.Context, channelID string, expiresAt *time.Time) error
+
+	// SetChannelWebSubRequestedFunc mocks the SetChannelWebSubRequested method.
+	SetChannelWebSubRequestedFunc func(ctx context.Context, channelID string) error
+
+	// SetChannelsMetadataRefreshedFunc mocks the SetChannelsMetadataRefreshed method.
+	SetChannelsMetadataRefreshedFunc func(ctx context.Context, channelIDs []string) error
+
+	// SetVideoAvailabilityFunc mocks the SetVideoAvailability method.
+	SetVideoAvailabilityFunc func(ctx context.Context, videoID string, reason models.UnavailableReason) error
+
 	// SetVideoDownloadCompletedFunc mocks the SetVideoDownloadCompleted method.
 	SetVideoDownloadCompletedFunc func(ctx context.Context, videoID string, filePath string) error
 
@@ -136,12 +307,21 @@ type DBMock struct {
 	// SetVideoDownloadStatusFunc mocks the SetVideoDownloadStatus method.
 	SetVideoDownloadStatusFunc func(ctx context.Context, videoID string, status string) error
 
+	// SetVideoDurationFunc mocks the SetVideoDuration method.
+	SetVideoDurationFunc func(ctx context.Context, video models.Video) error
+
 	// SetVideoProgressFunc mocks the SetVideoProgress method.
 	SetVideoProgressFunc func(ctx context.Context, videoID string, progress int) (*models.Video, error)
 
+	// SetVideoShortOverrideFunc mocks the SetVideoShortOverride method.
+	SetVideoShortOverrideFunc func(ctx context.Context, videoID string, isShort bool) error
+
 	// SetVideoWatchTimeFunc mocks the SetVideoWatchTime method.
 	SetVideoWatchTimeFunc func(ctx context.Context, videoID string, watchTime *time.Time) error
 
+	// SetVideoWatchTimesFunc mocks the SetVideoWatchTimes method.
+	SetVideoWatchTimesFunc func(ctx context.Context, watchTimes map[string]time.Time) error
+
 	// SubscribeToChannelFunc mocks the SubscribeToChannel method.
 	SubscribeToChannelFunc func(ctx context.Context, channel models.Channel) error
 
@@ -151,11 +331,44 @@ type DBMock struct {
 	// UnsubscribeFromChannelFunc mocks the UnsubscribeFromChannel method.
 	UnsubscribeFromChannelFunc func(ctx context.Context, channelID string) error
 
+	// UpdateChannelMetadataFunc mocks the UpdateChannelMetadata method.
+	UpdateChannelMetadataFunc func(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error)
+
+	// UpdateKnownVideosFunc mocks the UpdateKnownVideos method.
+	UpdateKnownVideosFunc func(ctx context.Context, videos []models.Video) ([]string, error)
+
 	// UpdateVideoLiveStatusFunc mocks the UpdateVideoLiveStatus method.
-	UpdateVideoLiveStatusFunc func(ctx context.Context, videoID string, isLive bool, duration int) error
+	UpdateVideoLiveStatusFunc func(ctx context.Context, video models.Video) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddChannels holds details about calls to the AddChannels method.
+		AddChannels []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channels is the channels argument value.
+			Channels []models.Channel
+		}
+		// AddPlaylistVideos holds details about calls to the AddPlaylistVideos method.
+		AddPlaylistVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PlaylistID is the playlistID argument value.
+			PlaylistID string
+			// VideoIDs is the videoIDs argument value.
+			VideoIDs []string
+		}
+		// AddQuotaUsage holds details about calls to the AddQuotaUsage method.
+		AddQuotaUsage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Day is the day argument value.
+			Day string
+			// Call is the call argument value.
+			Call string
+			// Units is the units argument value.
+			Units int
+		}
 		// AddVideo holds details about calls to the AddVideo method.
 		AddVideo []struct {
 			// Ctx is the ctx argument value.
@@ -164,8 +377,15 @@ type DBMock struct {
 			Video models.Video
 			// ChannelID is the channelID argument value.
 			ChannelID string
-			// IsDiscarded is the isDiscarded argument value.
-			IsDiscarded bool
+			// DiscardReason is the discardReason argument value.
+			DiscardReason models.DiscardReason
+		}
+		// AddVideos holds details about calls to the AddVideos method.
+		AddVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Videos is the videos argument value.
+			Videos []models.Video
 		}
 		// Close holds details about calls to the Close method.
 		Close []struct {
@@ -183,6 +403,15 @@ type DBMock struct {
 			Ctx context.Context
 			// VideoID is the videoID argument value.
 			VideoID string
+			// Reason is the reason argument value.
+			Reason models.DiscardReason
+		}
+		// ExpireUnwatchedVideos holds details about calls to the ExpireUnwatchedVideos method.
+		ExpireUnwatchedVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PublishedBefore is the publishedBefore argument value.
+			PublishedBefore time.Time
 		}
 		// GetChannelByID holds details about calls to the GetChannelByID method.
 		GetChannelByID []struct {
@@ -191,6 +420,31 @@ type DBMock struct {
 			// ChannelID is the channelID argument value.
 			ChannelID string
 		}
+		// GetDiscardedVideos holds details about calls to the GetDiscardedVideos method.
+		GetDiscardedVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// GetKnownVideoIDs holds details about calls to the GetKnownVideoIDs method.
+		GetKnownVideoIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoIDs is the videoIDs argument value.
+			VideoIDs []string
+		}
+		// GetLastPublishedBefore holds details about calls to the GetLastPublishedBefore method.
+		GetLastPublishedBefore []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Before is the before argument value.
+			Before time.Time
+		}
 		// GetLiveVideos holds details about calls to the GetLiveVideos method.
 		GetLiveVideos []struct {
 			// Ctx is the ctx argument value.
@@ -200,9 +454,27 @@ type DBMock struct {
 		GetNewVideos []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
+			// SortBy is the sortBy argument value.
+			SortBy models.VideoSort
 			// SortDesc is the sortDesc argument value.
 			SortDesc bool
 		}
+		// GetQuotaUsage holds details about calls to the GetQuotaUsage method.
+		GetQuotaUsage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Day is the day argument value.
+			Day string
+		}
+		// GetUnwatchedChannelVideos holds details about calls to the GetUnwatchedChannelVideos method.
+		GetUnwatchedChannelVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Discarded is the discarded argument value.
+			Discarded bool
+		}
 		// GetVideo holds details about calls to the GetVideo method.
 		GetVideo []struct {
 			// Ctx is the ctx argument value.
@@ -210,6 +482,11 @@ type DBMock struct {
 			// VideoID is the videoID argument value.
 			VideoID string
 		}
+		// GetVideosForAvailabilityCheck holds details about calls to the GetVideosForAvailabilityCheck method.
+		GetVideosForAvailabilityCheck []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// GetVideosForCleanup holds details about calls to the GetVideosForCleanup method.
 		GetVideosForCleanup []struct {
 			// Ctx is the ctx argument value.
@@ -217,6 +494,11 @@ type DBMock struct {
 			// OlderThan is the olderThan argument value.
 			OlderThan time.Duration
 		}
+		// GetVideosPendingDuration holds details about calls to the GetVideosPendingDuration method.
+		GetVideosPendingDuration []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// GetWatchedVideos holds details about calls to the GetWatchedVideos method.
 		GetWatchedVideos []struct {
 			// Ctx is the ctx argument value.
@@ -235,11 +517,133 @@ type DBMock struct {
 			// VideoID is the videoID argument value.
 			VideoID string
 		}
+		// ListChannelPollStates holds details about calls to the ListChannelPollStates method.
+		ListChannelPollStates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Now is the now argument value.
+			Now time.Time
+		}
 		// ListChannels holds details about calls to the ListChannels method.
 		ListChannels []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// ListChannelsDueForPoll holds details about calls to the ListChannelsDueForPoll method.
+		ListChannelsDueForPoll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListChannelsForMetadataRefresh holds details about calls to the ListChannelsForMetadataRefresh method.
+		ListChannelsForMetadataRefresh []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RefreshedBefore is the refreshedBefore argument value.
+			RefreshedBefore time.Time
+		}
+		// ListChannelsForWebSubRenewal holds details about calls to the ListChannelsForWebSubRenewal method.
+		ListChannelsForWebSubRenewal []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ExpiringBefore is the expiringBefore argument value.
+			ExpiringBefore time.Time
+			// RequestedBefore is the requestedBefore argument value.
+			RequestedBefore time.Time
+		}
+		// RecordChannelFetch holds details about calls to the RecordChannelFetch method.
+		RecordChannelFetch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Outcome is the outcome argument value.
+			Outcome models.FetchOutcome
+			// GoneAfter is the goneAfter argument value.
+			GoneAfter int
+		}
+		// RestoreVideo holds details about calls to the RestoreVideo method.
+		RestoreVideo []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoID is the videoID argument value.
+			VideoID string
+		}
+		// SetChannelFeedValidators holds details about calls to the SetChannelFeedValidators method.
+		SetChannelFeedValidators []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Etag is the etag argument value.
+			Etag string
+			// LastModified is the lastModified argument value.
+			LastModified string
+		}
+		// SetChannelPollIntervals holds details about calls to the SetChannelPollIntervals method.
+		SetChannelPollIntervals []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// MinInterval is the minInterval argument value.
+			MinInterval time.Duration
+			// MaxInterval is the maxInterval argument value.
+			MaxInterval time.Duration
+		}
+		// SetChannelPollResult holds details about calls to the SetChannelPollResult method.
+		SetChannelPollResult []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// NextPollAt is the nextPollAt argument value.
+			NextPollAt time.Time
+		}
+		// SetChannelTitleFilters holds details about calls to the SetChannelTitleFilters method.
+		SetChannelTitleFilters []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Include is the include argument value.
+			Include []string
+			// Exclude is the exclude argument value.
+			Exclude []string
+		}
+		// SetChannelWebSubLease holds details about calls to the SetChannelWebSubLease method.
+		SetChannelWebSubLease []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// ExpiresAt is the expiresAt argument value.
+			ExpiresAt *time.Time
+		}
+		// SetChannelWebSubRequested holds details about calls to the SetChannelWebSubRequested method.
+		SetChannelWebSubRequested []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+		}
+		// SetChannelsMetadataRefreshed holds details about calls to the SetChannelsMetadataRefreshed method.
+		SetChannelsMetadataRefreshed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelIDs is the channelIDs argument value.
+			ChannelIDs []string
+		}
+		// SetVideoAvailability holds details about calls to the SetVideoAvailability method.
+		SetVideoAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoID is the videoID argument value.
+			VideoID string
+			// Reason is the reason argument value.
+			Reason models.UnavailableReason
+		}
 		// SetVideoDownloadCompleted holds details about calls to the SetVideoDownloadCompleted method.
 		SetVideoDownloadCompleted []struct {
 			// Ctx is the ctx argument value.
@@ -267,6 +671,13 @@ type DBMock struct {
 			// Status is the status argument value.
 			Status string
 		}
+		// SetVideoDuration holds details about calls to the SetVideoDuration method.
+		SetVideoDuration []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Video is the video argument value.
+			Video models.Video
+		}
 		// SetVideoProgress holds details about calls to the SetVideoProgress method.
 		SetVideoProgress []struct {
 			// Ctx is the ctx argument value.
@@ -276,6 +687,15 @@ type DBMock struct {
 			// Progress is the progress argument value.
 			Progress int
 		}
+		// SetVideoShortOverride holds details about calls to the SetVideoShortOverride method.
+		SetVideoShortOverride []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoID is the videoID argument value.
+			VideoID string
+			// IsShort is the isShort argument value.
+			IsShort bool
+		}
 		// SetVideoWatchTime holds details about calls to the SetVideoWatchTime method.
 		SetVideoWatchTime []struct {
 			// Ctx is the ctx argument value.
@@ -285,6 +705,13 @@ type DBMock struct {
 			// WatchTime is the watchTime argument value.
 			WatchTime *time.Time
 		}
+		// SetVideoWatchTimes holds details about calls to the SetVideoWatchTimes method.
+		SetVideoWatchTimes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// WatchTimes is the watchTimes argument value.
+			WatchTimes map[string]time.Time
+		}
 		// SubscribeToChannel holds details about calls to the SubscribeToChannel method.
 		SubscribeToChannel []struct {
 			// Ctx is the ctx argument value.
@@ -308,164 +735,363 @@ type DBMock struct {
 			// ChannelID is the channelID argument value.
 			ChannelID string
 		}
+		// UpdateChannelMetadata holds details about calls to the UpdateChannelMetadata method.
+		UpdateChannelMetadata []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Metadata is the metadata argument value.
+			Metadata []models.ChannelMetadata
+		}
+		// UpdateKnownVideos holds details about calls to the UpdateKnownVideos method.
+		UpdateKnownVideos []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Videos is the videos argument value.
+			Videos []models.Video
+		}
 		// UpdateVideoLiveStatus holds details about calls to the UpdateVideoLiveStatus method.
 		UpdateVideoLiveStatus []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// VideoID is the videoID argument value.
-			VideoID string
-			// IsLive is the isLive argument value.
-			IsLive bool
-			// Duration is the duration argument value.
-			Duration int
+			// Video is the video argument value.
+			Video models.Video
 		}
 	}
-	lockAddVideo                  sync.RWMutex
-	lockClose                     sync.RWMutex
-	lockDeleteVideoFile           sync.RWMutex
-	lockDiscardVideo              sync.RWMutex
-	lockGetChannelByID            sync.RWMutex
-	lockGetLiveVideos             sync.RWMutex
-	lockGetNewVideos              sync.RWMutex
-	lockGetVideo                  sync.RWMutex
-	lockGetVideosForCleanup       sync.RWMutex
-	lockGetWatchedVideos          sync.RWMutex
-	lockHasVideo                  sync.RWMutex
-	lockListChannels              sync.RWMutex
-	lockSetVideoDownloadCompleted sync.RWMutex
-	lockSetVideoDownloadFailed    sync.RWMutex
-	lockSetVideoDownloadStatus    sync.RWMutex
-	lockSetVideoProgress          sync.RWMutex
-	lockSetVideoWatchTime         sync.RWMutex
-	lockSubscribeToChannel        sync.RWMutex
-	lockToggleChannelShorts       sync.RWMutex
-	lockUnsubscribeFromChannel    sync.RWMutex
-	lockUpdateVideoLiveStatus     sync.RWMutex
+	lockAddChannels                    sync.RWMutex
+	lockAddPlaylistVideos              sync.RWMutex
+	lockAddQuotaUsage                  sync.RWMutex
+	lockAddVideo                       sync.RWMutex
+	lockAddVideos                      sync.RWMutex
+	lockClose                          sync.RWMutex
+	lockDeleteVideoFile                sync.RWMutex
+	lockDiscardVideo                   sync.RWMutex
+	lockExpireUnwatchedVideos          sync.RWMutex
+	lockGetChannelByID                 sync.RWMutex
+	lockGetDiscardedVideos             sync.RWMutex
+	lockGetKnownVideoIDs               sync.RWMutex
+	lockGetLastPublishedBefore         sync.RWMutex
+	lockGetLiveVideos                  sync.RWMutex
+	lockGetNewVideos                   sync.RWMutex
+	lockGetQuotaUsage                  sync.RWMutex
+	lockGetUnwatchedChannelVideos      sync.RWMutex
+	lockGetVideo                       sync.RWMutex
+	lockGetVideosForAvailabilityCheck  sync.RWMutex
+	lockGetVideosForCleanup            sync.RWMutex
+	lockGetVideosPendingDuration       sync.RWMutex
+	lockGetWatchedVideos               sync.RWMutex
+	lockHasVideo                       sync.RWMutex
+	lockListChannelPollStates          sync.RWMutex
+	lockListChannels                   sync.RWMutex
+	lockListChannelsDueForPoll         sync.RWMutex
+	lockListChannelsForMetadataRefresh sync.RWMutex
+	lockListChannelsForWebSubRenewal   sync.RWMutex
+	lockRecordChannelFetch             sync.RWMutex
+	lockRestoreVideo                   sync.RWMutex
+	lockSetChannelFeedValidators       sync.RWMutex
+	lockSetChannelPollIntervals        sync.RWMutex
+	lockSetChannelPollResult           sync.RWMutex
+	lockSetChannelTitleFilters         sync.RWMutex
+	lockSetChannelWebSubLease          sync.RWMutex
+	lockSetChannelWebSubRequested      sync.RWMutex
+	lockSetChannelsMetadataRefreshed   sync.RWMutex
+	lockSetVideoAvailability           sync.RWMutex
+	lockSetVideoDownloadCompleted      sync.RWMutex
+	lockSetVideoDownloadFailed         sync.RWMutex
+	lockSetVideoDownloadStatus         sync.RWMutex
+	lockSetVideoDuration               sync.RWMutex
+	lockSetVideoProgress               sync.RWMutex
+	lockSetVideoShortOverride          sync.RWMutex
+	lockSetVideoWatchTime              sync.RWMutex
+	lockSetVideoWatchTimes             sync.RWMutex
+	lockSubscribeToChannel             sync.RWMutex
+	lockToggleChannelShorts            sync.RWMutex
+	lockUnsubscribeFromChannel         sync.RWMutex
+	lockUpdateChannelMetadata          sync.RWMutex
+	lockUpdateKnownVideos              sync.RWMutex
+	lockUpdateVideoLiveStatus          sync.RWMutex
 }
 
-// AddVideo calls AddVideoFunc.
-func (mock *DBMock) AddVideo(ctx context.Context, video models.Video, channelID string, isDiscarded bool) error {
-	if mock.AddVideoFunc == nil {
-		panic("DBMock.AddVideoFunc: method is nil but DB.AddVideo was just called")
+// AddChannels calls AddChannelsFunc.
+func (mock *DBMock) AddChannels(ctx context.Context, channels []models.Channel) error {
+	if mock.AddChannelsFunc == nil {
+		panic("DBMock.AddChannelsFunc: method is nil but DB.AddChannels was just called")
 	}
 	callInfo := struct {
-		Ctx         context.Context
-		Video       models.Video
-		ChannelID   string
-		IsDiscarded bool
+		Ctx      context.Context
+		Channels []models.Channel
 	}{
-		Ctx:         ctx,
-		Video:       video,
-		ChannelID:   channelID,
-		IsDiscarded: isDiscarded,
+		Ctx:      ctx,
+		Channels: channels,
 	}
-	mock.lockAddVideo.Lock()
-	mock.calls.AddVideo = append(mock.calls.AddVideo, callInfo)
-	mock.lockAddVideo.Unlock()
-	return mock.AddVideoFunc(ctx, video, channelID, isDiscarded)
+	mock.lockAddChannels.Lock()
+	mock.calls.AddChannels = append(mock.calls.AddChannels, callInfo)
+	mock.lockAddChannels.Unlock()
+	return mock.AddChannelsFunc(ctx, channels)
 }
 
-// AddVideoCalls gets all the calls that were made to AddVideo.
+// AddChannelsCalls gets all the calls that were made to AddChannels.
 // Check the length with:
 //
-//	len(mockedDB.AddVideoCalls())
-func (mock *DBMock) AddVideoCalls() []struct {
-	Ctx         context.Context
-	Video       models.Video
-	ChannelID   string
-	IsDiscarded bool
+//	len(mockedDB.AddChannelsCalls())
+func (mock *DBMock) AddChannelsCalls() []struct {
+	Ctx      context.Context
+	Channels []models.Channel
 } {
 	var calls []struct {
-		Ctx         context.Context
-		Video       models.Video
-		ChannelID   string
-		IsDiscarded bool
+		Ctx      context.Context
+		Channels []models.Channel
 	}
-	mock.lockAddVideo.RLock()
-	calls = mock.calls.AddVideo
-	mock.lockAddVideo.RUnlock()
+	mock.lockAddChannels.RLock()
+	calls = mock.calls.AddChannels
+	mock.lockAddChannels.RUnlock()
 	return calls
 }
 
-// Close calls CloseFunc.
-func (mock *DBMock) Close() {
-	if mock.CloseFunc == nil {
-		panic("DBMock.CloseFunc: method is nil but DB.Close was just called")
+// AddPlaylistVideos calls AddPlaylistVideosFunc.
+func (mock *DBMock) AddPlaylistVideos(ctx context.Context, playlistID string, videoIDs []string) error {
+	if mock.AddPlaylistVideosFunc == nil {
+		panic("DBMock.AddPlaylistVideosFunc: method is nil but DB.AddPlaylistVideos was just called")
 	}
 	callInfo := struct {
-	}{}
-	mock.lockClose.Lock()
-	mock.calls.Close = append(mock.calls.Close, callInfo)
-	mock.lockClose.Unlock()
-	mock.CloseFunc()
+		Ctx        context.Context
+		PlaylistID string
+		VideoIDs   []string
+	}{
+		Ctx:        ctx,
+		PlaylistID: playlistID,
+		VideoIDs:   videoIDs,
+	}
+	mock.lockAddPlaylistVideos.Lock()
+	mock.calls.AddPlaylistVideos = append(mock.calls.AddPlaylistVideos, callInfo)
+	mock.lockAddPlaylistVideos.Unlock()
+	return mock.AddPlaylistVideosFunc(ctx, playlistID, videoIDs)
 }
 
-// CloseCalls gets all the calls that were made to Close.
+// AddPlaylistVideosCalls gets all the calls that were made to AddPlaylistVideos.
 // Check the length with:
 //
-//	len(mockedDB.CloseCalls())
-func (mock *DBMock) CloseCalls() []struct {
+//	len(mockedDB.AddPlaylistVideosCalls())
+func (mock *DBMock) AddPlaylistVideosCalls() []struct {
+	Ctx        context.Context
+	PlaylistID string
+	VideoIDs   []string
 } {
 	var calls []struct {
+		Ctx        context.Context
+		PlaylistID string
+		VideoIDs   []string
 	}
-	mock.lockClose.RLock()
-	calls = mock.calls.Close
-	mock.lockClose.RUnlock()
+	mock.lockAddPlaylistVideos.RLock()
+	calls = mock.calls.AddPlaylistVideos
+	mock.lockAddPlaylistVideos.RUnlock()
 	return calls
 }
 
-// DeleteVideoFile calls DeleteVideoFileFunc.
-func (mock *DBMock) DeleteVideoFile(ctx context.Context, videoID string) error {
-	if mock.DeleteVideoFileFunc == nil {
-		panic("DBMock.DeleteVideoFileFunc: method is nil but DB.DeleteVideoFile was just called")
+// AddQuotaUsage calls AddQuotaUsageFunc.
+func (mock *DBMock) AddQuotaUsage(ctx context.Context, day string, call string, units int) error {
+	if mock.AddQuotaUsageFunc == nil {
+		panic("DBMock.AddQuotaUsageFunc: method is nil but DB.AddQuotaUsage was just called")
 	}
 	callInfo := struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx   context.Context
+		Day   string
+		Call  string
+		Units int
 	}{
-		Ctx:     ctx,
-		VideoID: videoID,
+		Ctx:   ctx,
+		Day:   day,
+		Call:  call,
+		Units: units,
 	}
-	mock.lockDeleteVideoFile.Lock()
-	mock.calls.DeleteVideoFile = append(mock.calls.DeleteVideoFile, callInfo)
-	mock.lockDeleteVideoFile.Unlock()
-	return mock.DeleteVideoFileFunc(ctx, videoID)
+	mock.lockAddQuotaUsage.Lock()
+	mock.calls.AddQuotaUsage = append(mock.calls.AddQuotaUsage, callInfo)
+	mock.lockAddQuotaUsage.Unlock()
+	return mock.AddQuotaUsageFunc(ctx, day, call, units)
 }
 
-// DeleteVideoFileCalls gets all the calls that were made to DeleteVideoFile.
+// AddQuotaUsageCalls gets all the calls that were made to AddQuotaUsage.
 // Check the length with:
 //
-//	len(mockedDB.DeleteVideoFileCalls())
-func (mock *DBMock) DeleteVideoFileCalls() []struct {
-	Ctx     context.Context
-	VideoID string
+//	len(mockedDB.AddQuotaUsageCalls())
+func (mock *DBMock) AddQuotaUsageCalls() []struct {
+	Ctx   context.Context
+	Day   string
+	Call  string
+	Units int
 } {
 	var calls []struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx   context.Context
+		Day   string
+		Call  string
+		Units int
 	}
-	mock.lockDeleteVideoFile.RLock()
-	calls = mock.calls.DeleteVideoFile
-	mock.lockDeleteVideoFile.RUnlock()
+	mock.lockAddQuotaUsage.RLock()
+	calls = mock.calls.AddQuotaUsage
+	mock.lockAddQuotaUsage.RUnlock()
 	return calls
 }
 
-// DiscardVideo calls DiscardVideoFunc.
-func (mock *DBMock) DiscardVideo(ctx context.Context, videoID string) error {
-	if mock.DiscardVideoFunc == nil {
-		panic("DBMock.DiscardVideoFunc: method is nil but DB.DiscardVideo was just called")
-	}
+// AddVideo calls AddVideoFunc.
+func (mock *DBMock) AddVideo(ctx context.Context, video models.Video, channelID string, discardReason models.DiscardReason) error {
+	if mock.AddVideoFunc == nil {
+		panic("DBMock.AddVideoFunc: method is nil but DB.AddVideo was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		Video         models.Video
+		ChannelID     string
+		DiscardReason models.DiscardReason
+	}{
+		Ctx:           ctx,
+		Video:         video,
+		ChannelID:     channelID,
+		DiscardReason: discardReason,
+	}
+	mock.lockAddVideo.Lock()
+	mock.calls.AddVideo = append(mock.calls.AddVideo, callInfo)
+	mock.lockAddVideo.Unlock()
+	return mock.AddVideoFunc(ctx, video, channelID, discardReason)
+}
+
+// AddVideoCalls gets all the calls that were made to AddVideo.
+// Check the length with:
+//
+//	len(mockedDB.AddVideoCalls())
+func (mock *DBMock) AddVideoCalls() []struct {
+	Ctx           context.Context
+	Video         models.Video
+	ChannelID     string
+	DiscardReason models.DiscardReason
+} {
+	var calls []struct {
+		Ctx           context.Context
+		Video         models.Video
+		ChannelID     string
+		DiscardReason models.DiscardReason
+	}
+	mock.lockAddVideo.RLock()
+	calls = mock.calls.AddVideo
+	mock.lockAddVideo.RUnlock()
+	return calls
+}
+
+// AddVideos calls AddVideosFunc.
+func (mock *DBMock) AddVideos(ctx context.Context, videos []models.Video) ([]string, error) {
+	if mock.AddVideosFunc == nil {
+		panic("DBMock.AddVideosFunc: method is nil but DB.AddVideos was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Videos []models.Video
+	}{
+		Ctx:    ctx,
+		Videos: videos,
+	}
+	mock.lockAddVideos.Lock()
+	mock.calls.AddVideos = append(mock.calls.AddVideos, callInfo)
+	mock.lockAddVideos.Unlock()
+	return mock.AddVideosFunc(ctx, videos)
+}
+
+// AddVideosCalls gets all the calls that were made to AddVideos.
+// Check the length with:
+//
+//	len(mockedDB.AddVideosCalls())
+func (mock *DBMock) AddVideosCalls() []struct {
+	Ctx    context.Context
+	Videos []models.Video
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Videos []models.Video
+	}
+	mock.lockAddVideos.RLock()
+	calls = mock.calls.AddVideos
+	mock.lockAddVideos.RUnlock()
+	return calls
+}
+
+// Close calls CloseFunc.
+func (mock *DBMock) Close() {
+	if mock.CloseFunc == nil {
+		panic("DBMock.CloseFunc: method is nil but DB.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+//	len(mockedDB.CloseCalls())
+func (mock *DBMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
+// DeleteVideoFile calls DeleteVideoFileFunc.
+func (mock *DBMock) DeleteVideoFile(ctx context.Context, videoID string) error {
+	if mock.DeleteVideoFileFunc == nil {
+		panic("DBMock.DeleteVideoFileFunc: method is nil but DB.DeleteVideoFile was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		VideoID string
+	}{
+		Ctx:     ctx,
+		VideoID: videoID,
+	}
+	mock.lockDeleteVideoFile.Lock()
+	mock.calls.DeleteVideoFile = append(mock.calls.DeleteVideoFile, callInfo)
+	mock.lockDeleteVideoFile.Unlock()
+	return mock.DeleteVideoFileFunc(ctx, videoID)
+}
+
+// DeleteVideoFileCalls gets all the calls that were made to DeleteVideoFile.
+// Check the length with:
+//
+//	len(mockedDB.DeleteVideoFileCalls())
+func (mock *DBMock) DeleteVideoFileCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		VideoID string
+	}
+	mock.lockDeleteVideoFile.RLock()
+	calls = mock.calls.DeleteVideoFile
+	mock.lockDeleteVideoFile.RUnlock()
+	return calls
+}
+
+// DiscardVideo calls DiscardVideoFunc.
+func (mock *DBMock) DiscardVideo(ctx context.Context, videoID string, reason models.DiscardReason) error {
+	if mock.DiscardVideoFunc == nil {
+		panic("DBMock.DiscardVideoFunc: method is nil but DB.DiscardVideo was just called")
+	}
 	callInfo := struct {
 		Ctx     context.Context
 		VideoID string
+		Reason  models.DiscardReason
 	}{
 		Ctx:     ctx,
 		VideoID: videoID,
+		Reason:  reason,
 	}
 	mock.lockDiscardVideo.Lock()
 	mock.calls.DiscardVideo = append(mock.calls.DiscardVideo, callInfo)
 	mock.lockDiscardVideo.Unlock()
-	return mock.DiscardVideoFunc(ctx, videoID)
+	return mock.DiscardVideoFunc(ctx, videoID, reason)
 }
 
 // DiscardVideoCalls gets all the calls that were made to DiscardVideo.
@@ -475,10 +1101,12 @@ func (mock *DBMock) DiscardVideo(ctx context.Context, videoID string) error {
 func (mock *DBMock) DiscardVideoCalls() []struct {
 	Ctx     context.Context
 	VideoID string
+	Reason  models.DiscardReason
 } {
 	var calls []struct {
 		Ctx     context.Context
 		VideoID string
+		Reason  models.DiscardReason
 	}
 	mock.lockDiscardVideo.RLock()
 	calls = mock.calls.DiscardVideo
@@ -486,6 +1114,42 @@ func (mock *DBMock) DiscardVideoCalls() []struct {
 	return calls
 }
 
+// ExpireUnwatchedVideos calls ExpireUnwatchedVideosFunc.
+func (mock *DBMock) ExpireUnwatchedVideos(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	if mock.ExpireUnwatchedVideosFunc == nil {
+		panic("DBMock.ExpireUnwatchedVideosFunc: method is nil but DB.ExpireUnwatchedVideos was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		PublishedBefore time.Time
+	}{
+		Ctx:             ctx,
+		PublishedBefore: publishedBefore,
+	}
+	mock.lockExpireUnwatchedVideos.Lock()
+	mock.calls.ExpireUnwatchedVideos = append(mock.calls.ExpireUnwatchedVideos, callInfo)
+	mock.lockExpireUnwatchedVideos.Unlock()
+	return mock.ExpireUnwatchedVideosFunc(ctx, publishedBefore)
+}
+
+// ExpireUnwatchedVideosCalls gets all the calls that were made to ExpireUnwatchedVideos.
+// Check the length with:
+//
+//	len(mockedDB.ExpireUnwatchedVideosCalls())
+func (mock *DBMock) ExpireUnwatchedVideosCalls() []struct {
+	Ctx             context.Context
+	PublishedBefore time.Time
+} {
+	var calls []struct {
+		Ctx             context.Context
+		PublishedBefore time.Time
+	}
+	mock.lockExpireUnwatchedVideos.RLock()
+	calls = mock.calls.ExpireUnwatchedVideos
+	mock.lockExpireUnwatchedVideos.RUnlock()
+	return calls
+}
+
 // GetChannelByID calls GetChannelByIDFunc.
 func (mock *DBMock) GetChannelByID(ctx context.Context, channelID string) (*models.Channel, error) {
 	if mock.GetChannelByIDFunc == nil {
@@ -522,6 +1186,122 @@ func (mock *DBMock) GetChannelByIDCalls() []struct {
 	return calls
 }
 
+// GetDiscardedVideos calls GetDiscardedVideosFunc.
+func (mock *DBMock) GetDiscardedVideos(ctx context.Context, limit int, offset int) ([]models.Video, error) {
+	if mock.GetDiscardedVideosFunc == nil {
+		panic("DBMock.GetDiscardedVideosFunc: method is nil but DB.GetDiscardedVideos was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Limit  int
+		Offset int
+	}{
+		Ctx:    ctx,
+		Limit:  limit,
+		Offset: offset,
+	}
+	mock.lockGetDiscardedVideos.Lock()
+	mock.calls.GetDiscardedVideos = append(mock.calls.GetDiscardedVideos, callInfo)
+	mock.lockGetDiscardedVideos.Unlock()
+	return mock.GetDiscardedVideosFunc(ctx, limit, offset)
+}
+
+// GetDiscardedVideosCalls gets all the calls that were made to GetDiscardedVideos.
+// Check the length with:
+//
+//	len(mockedDB.GetDiscardedVideosCalls())
+func (mock *DBMock) GetDiscardedVideosCalls() []struct {
+	Ctx    context.Context
+	Limit  int
+	Offset int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Limit  int
+		Offset int
+	}
+	mock.lockGetDiscardedVideos.RLock()
+	calls = mock.calls.GetDiscardedVideos
+	mock.lockGetDiscardedVideos.RUnlock()
+	return calls
+}
+
+// GetKnownVideoIDs calls GetKnownVideoIDsFunc.
+func (mock *DBMock) GetKnownVideoIDs(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+	if mock.GetKnownVideoIDsFunc == nil {
+		panic("DBMock.GetKnownVideoIDsFunc: method is nil but DB.GetKnownVideoIDs was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		VideoIDs []string
+	}{
+		Ctx:      ctx,
+		VideoIDs: videoIDs,
+	}
+	mock.lockGetKnownVideoIDs.Lock()
+	mock.calls.GetKnownVideoIDs = append(mock.calls.GetKnownVideoIDs, callInfo)
+	mock.lockGetKnownVideoIDs.Unlock()
+	return mock.GetKnownVideoIDsFunc(ctx, videoIDs)
+}
+
+// GetKnownVideoIDsCalls gets all the calls that were made to GetKnownVideoIDs.
+// Check the length with:
+//
+//	len(mockedDB.GetKnownVideoIDsCalls())
+func (mock *DBMock) GetKnownVideoIDsCalls() []struct {
+	Ctx      context.Context
+	VideoIDs []string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		VideoIDs []string
+	}
+	mock.lockGetKnownVideoIDs.RLock()
+	calls = mock.calls.GetKnownVideoIDs
+	mock.lockGetKnownVideoIDs.RUnlock()
+	return calls
+}
+
+// GetLastPublishedBefore calls GetLastPublishedBeforeFunc.
+func (mock *DBMock) GetLastPublishedBefore(ctx context.Context, channelID string, before time.Time) (*time.Time, error) {
+	if mock.GetLastPublishedBeforeFunc == nil {
+		panic("DBMock.GetLastPublishedBeforeFunc: method is nil but DB.GetLastPublishedBefore was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		Before    time.Time
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Before:    before,
+	}
+	mock.lockGetLastPublishedBefore.Lock()
+	mock.calls.GetLastPublishedBefore = append(mock.calls.GetLastPublishedBefore, callInfo)
+	mock.lockGetLastPublishedBefore.Unlock()
+	return mock.GetLastPublishedBeforeFunc(ctx, channelID, before)
+}
+
+// GetLastPublishedBeforeCalls gets all the calls that were made to GetLastPublishedBefore.
+// Check the length with:
+//
+//	len(mockedDB.GetLastPublishedBeforeCalls())
+func (mock *DBMock) GetLastPublishedBeforeCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Before    time.Time
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		Before    time.Time
+	}
+	mock.lockGetLastPublishedBefore.RLock()
+	calls = mock.calls.GetLastPublishedBefore
+	mock.lockGetLastPublishedBefore.RUnlock()
+	return calls
+}
+
 // GetLiveVideos calls GetLiveVideosFunc.
 func (mock *DBMock) GetLiveVideos(ctx context.Context) ([]models.Video, error) {
 	if mock.GetLiveVideosFunc == nil {
@@ -546,231 +1326,927 @@ func (mock *DBMock) GetLiveVideosCalls() []struct {
 	Ctx context.Context
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx context.Context
+	}
+	mock.lockGetLiveVideos.RLock()
+	calls = mock.calls.GetLiveVideos
+	mock.lockGetLiveVideos.RUnlock()
+	return calls
+}
+
+// GetNewVideos calls GetNewVideosFunc.
+func (mock *DBMock) GetNewVideos(ctx context.Context, sortBy models.VideoSort, sortDesc bool) ([]models.Video, error) {
+	if mock.GetNewVideosFunc == nil {
+		panic("DBMock.GetNewVideosFunc: method is nil but DB.GetNewVideos was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SortBy   models.VideoSort
+		SortDesc bool
+	}{
+		Ctx:      ctx,
+		SortBy:   sortBy,
+		SortDesc: sortDesc,
+	}
+	mock.lockGetNewVideos.Lock()
+	mock.calls.GetNewVideos = append(mock.calls.GetNewVideos, callInfo)
+	mock.lockGetNewVideos.Unlock()
+	return mock.GetNewVideosFunc(ctx, sortBy, sortDesc)
+}
+
+// GetNewVideosCalls gets all the calls that were made to GetNewVideos.
+// Check the length with:
+//
+//	len(mockedDB.GetNewVideosCalls())
+func (mock *DBMock) GetNewVideosCalls() []struct {
+	Ctx      context.Context
+	SortBy   models.VideoSort
+	SortDesc bool
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SortBy   models.VideoSort
+		SortDesc bool
+	}
+	mock.lockGetNewVideos.RLock()
+	calls = mock.calls.GetNewVideos
+	mock.lockGetNewVideos.RUnlock()
+	return calls
+}
+
+// GetQuotaUsage calls GetQuotaUsageFunc.
+func (mock *DBMock) GetQuotaUsage(ctx context.Context, day string) ([]models.QuotaCallUsage, error) {
+	if mock.GetQuotaUsageFunc == nil {
+		panic("DBMock.GetQuotaUsageFunc: method is nil but DB.GetQuotaUsage was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Day string
+	}{
+		Ctx: ctx,
+		Day: day,
+	}
+	mock.lockGetQuotaUsage.Lock()
+	mock.calls.GetQuotaUsage = append(mock.calls.GetQuotaUsage, callInfo)
+	mock.lockGetQuotaUsage.Unlock()
+	return mock.GetQuotaUsageFunc(ctx, day)
+}
+
+// GetQuotaUsageCalls gets all the calls that were made to GetQuotaUsage.
+// Check the length with:
+//
+//	len(mockedDB.GetQuotaUsageCalls())
+func (mock *DBMock) GetQuotaUsageCalls() []struct {
+	Ctx context.Context
+	Day string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Day string
+	}
+	mock.lockGetQuotaUsage.RLock()
+	calls = mock.calls.GetQuotaUsage
+	mock.lockGetQuotaUsage.RUnlock()
+	return calls
+}
+
+// GetUnwatchedChannelVideos calls GetUnwatchedChannelVideosFunc.
+func (mock *DBMock) GetUnwatchedChannelVideos(ctx context.Context, channelID string, discarded bool) ([]models.Video, error) {
+	if mock.GetUnwatchedChannelVideosFunc == nil {
+		panic("DBMock.GetUnwatchedChannelVideosFunc: method is nil but DB.GetUnwatchedChannelVideos was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		Discarded bool
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Discarded: discarded,
+	}
+	mock.lockGetUnwatchedChannelVideos.Lock()
+	mock.calls.GetUnwatchedChannelVideos = append(mock.calls.GetUnwatchedChannelVideos, callInfo)
+	mock.lockGetUnwatchedChannelVideos.Unlock()
+	return mock.GetUnwatchedChannelVideosFunc(ctx, channelID, discarded)
+}
+
+// GetUnwatchedChannelVideosCalls gets all the calls that were made to GetUnwatchedChannelVideos.
+// Check the length with:
+//
+//	len(mockedDB.GetUnwatchedChannelVideosCalls())
+func (mock *DBMock) GetUnwatchedChannelVideosCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Discarded bool
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		Discarded bool
+	}
+	mock.lockGetUnwatchedChannelVideos.RLock()
+	calls = mock.calls.GetUnwatchedChannelVideos
+	mock.lockGetUnwatchedChannelVideos.RUnlock()
+	return calls
+}
+
+// GetVideo calls GetVideoFunc.
+func (mock *DBMock) GetVideo(ctx context.Context, videoID string) (*models.Video, error) {
+	if mock.GetVideoFunc == nil {
+		panic("DBMock.GetVideoFunc: method is nil but DB.GetVideo was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		VideoID string
+	}{
+		Ctx:     ctx,
+		VideoID: videoID,
+	}
+	mock.lockGetVideo.Lock()
+	mock.calls.GetVideo = append(mock.calls.GetVideo, callInfo)
+	mock.lockGetVideo.Unlock()
+	return mock.GetVideoFunc(ctx, videoID)
+}
+
+// GetVideoCalls gets all the calls that were made to GetVideo.
+// Check the length with:
+//
+//	len(mockedDB.GetVideoCalls())
+func (mock *DBMock) GetVideoCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		VideoID string
+	}
+	mock.lockGetVideo.RLock()
+	calls = mock.calls.GetVideo
+	mock.lockGetVideo.RUnlock()
+	return calls
+}
+
+// GetVideosForAvailabilityCheck calls GetVideosForAvailabilityCheckFunc.
+func (mock *DBMock) GetVideosForAvailabilityCheck(ctx context.Context) ([]models.Video, error) {
+	if mock.GetVideosForAvailabilityCheckFunc == nil {
+		panic("DBMock.GetVideosForAvailabilityCheckFunc: method is nil but DB.GetVideosForAvailabilityCheck was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetVideosForAvailabilityCheck.Lock()
+	mock.calls.GetVideosForAvailabilityCheck = append(mock.calls.GetVideosForAvailabilityCheck, callInfo)
+	mock.lockGetVideosForAvailabilityCheck.Unlock()
+	return mock.GetVideosForAvailabilityCheckFunc(ctx)
+}
+
+// GetVideosForAvailabilityCheckCalls gets all the calls that were made to GetVideosForAvailabilityCheck.
+// Check the length with:
+//
+//	len(mockedDB.GetVideosForAvailabilityCheckCalls())
+func (mock *DBMock) GetVideosForAvailabilityCheckCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetVideosForAvailabilityCheck.RLock()
+	calls = mock.calls.GetVideosForAvailabilityCheck
+	mock.lockGetVideosForAvailabilityCheck.RUnlock()
+	return calls
+}
+
+// GetVideosForCleanup calls GetVideosForCleanupFunc.
+func (mock *DBMock) GetVideosForCleanup(ctx context.Context, olderThan time.Duration) ([]models.Video, error) {
+	if mock.GetVideosForCleanupFunc == nil {
+		panic("DBMock.GetVideosForCleanupFunc: method is nil but DB.GetVideosForCleanup was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		OlderThan time.Duration
+	}{
+		Ctx:       ctx,
+		OlderThan: olderThan,
+	}
+	mock.lockGetVideosForCleanup.Lock()
+	mock.calls.GetVideosForCleanup = append(mock.calls.GetVideosForCleanup, callInfo)
+	mock.lockGetVideosForCleanup.Unlock()
+	return mock.GetVideosForCleanupFunc(ctx, olderThan)
+}
+
+// GetVideosForCleanupCalls gets all the calls that were made to GetVideosForCleanup.
+// Check the length with:
+//
+//	len(mockedDB.GetVideosForCleanupCalls())
+func (mock *DBMock) GetVideosForCleanupCalls() []struct {
+	Ctx       context.Context
+	OlderThan time.Duration
+} {
+	var calls []struct {
+		Ctx       context.Context
+		OlderThan time.Duration
+	}
+	mock.lockGetVideosForCleanup.RLock()
+	calls = mock.calls.GetVideosForCleanup
+	mock.lockGetVideosForCleanup.RUnlock()
+	return calls
+}
+
+// GetVideosPendingDuration calls GetVideosPendingDurationFunc.
+func (mock *DBMock) GetVideosPendingDuration(ctx context.Context) ([]models.Video, error) {
+	if mock.GetVideosPendingDurationFunc == nil {
+		panic("DBMock.GetVideosPendingDurationFunc: method is nil but DB.GetVideosPendingDuration was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetVideosPendingDuration.Lock()
+	mock.calls.GetVideosPendingDuration = append(mock.calls.GetVideosPendingDuration, callInfo)
+	mock.lockGetVideosPendingDuration.Unlock()
+	return mock.GetVideosPendingDurationFunc(ctx)
+}
+
+// GetVideosPendingDurationCalls gets all the calls that were made to GetVideosPendingDuration.
+// Check the length with:
+//
+//	len(mockedDB.GetVideosPendingDurationCalls())
+func (mock *DBMock) GetVideosPendingDurationCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetVideosPendingDuration.RLock()
+	calls = mock.calls.GetVideosPendingDuration
+	mock.lockGetVideosPendingDuration.RUnlock()
+	return calls
+}
+
+// GetWatchedVideos calls GetWatchedVideosFunc.
+func (mock *DBMock) GetWatchedVideos(ctx context.Context, sortDesc bool, limit int, offset int) ([]models.Video, error) {
+	if mock.GetWatchedVideosFunc == nil {
+		panic("DBMock.GetWatchedVideosFunc: method is nil but DB.GetWatchedVideos was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SortDesc bool
+		Limit    int
+		Offset   int
+	}{
+		Ctx:      ctx,
+		SortDesc: sortDesc,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	mock.lockGetWatchedVideos.Lock()
+	mock.calls.GetWatchedVideos = append(mock.calls.GetWatchedVideos, callInfo)
+	mock.lockGetWatchedVideos.Unlock()
+	return mock.GetWatchedVideosFunc(ctx, sortDesc, limit, offset)
+}
+
+// GetWatchedVideosCalls gets all the calls that were made to GetWatchedVideos.
+// Check the length with:
+//
+//	len(mockedDB.GetWatchedVideosCalls())
+func (mock *DBMock) GetWatchedVideosCalls() []struct {
+	Ctx      context.Context
+	SortDesc bool
+	Limit    int
+	Offset   int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SortDesc bool
+		Limit    int
+		Offset   int
+	}
+	mock.lockGetWatchedVideos.RLock()
+	calls = mock.calls.GetWatchedVideos
+	mock.lockGetWatchedVideos.RUnlock()
+	return calls
+}
+
+// HasVideo calls HasVideoFunc.
+func (mock *DBMock) HasVideo(ctx context.Context, videoID string) (bool, error) {
+	if mock.HasVideoFunc == nil {
+		panic("DBMock.HasVideoFunc: method is nil but DB.HasVideo was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		VideoID string
+	}{
+		Ctx:     ctx,
+		VideoID: videoID,
+	}
+	mock.lockHasVideo.Lock()
+	mock.calls.HasVideo = append(mock.calls.HasVideo, callInfo)
+	mock.lockHasVideo.Unlock()
+	return mock.HasVideoFunc(ctx, videoID)
+}
+
+// HasVideoCalls gets all the calls that were made to HasVideo.
+// Check the length with:
+//
+//	len(mockedDB.HasVideoCalls())
+func (mock *DBMock) HasVideoCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		VideoID string
+	}
+	mock.lockHasVideo.RLock()
+	calls = mock.calls.HasVideo
+	mock.lockHasVideo.RUnlock()
+	return calls
+}
+
+// ListChannelPollStates calls ListChannelPollStatesFunc.
+func (mock *DBMock) ListChannelPollStates(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+	if mock.ListChannelPollStatesFunc == nil {
+		panic("DBMock.ListChannelPollStatesFunc: method is nil but DB.ListChannelPollStates was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Now time.Time
+	}{
+		Ctx: ctx,
+		Now: now,
+	}
+	mock.lockListChannelPollStates.Lock()
+	mock.calls.ListChannelPollStates = append(mock.calls.ListChannelPollStates, callInfo)
+	mock.lockListChannelPollStates.Unlock()
+	return mock.ListChannelPollStatesFunc(ctx, now)
+}
+
+// ListChannelPollStatesCalls gets all the calls that were made to ListChannelPollStates.
+// Check the length with:
+//
+//	len(mockedDB.ListChannelPollStatesCalls())
+func (mock *DBMock) ListChannelPollStatesCalls() []struct {
+	Ctx context.Context
+	Now time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		Now time.Time
+	}
+	mock.lockListChannelPollStates.RLock()
+	calls = mock.calls.ListChannelPollStates
+	mock.lockListChannelPollStates.RUnlock()
+	return calls
+}
+
+// ListChannels calls ListChannelsFunc.
+func (mock *DBMock) ListChannels(ctx context.Context) ([]models.Channel, error) {
+	if mock.ListChannelsFunc == nil {
+		panic("DBMock.ListChannelsFunc: method is nil but DB.ListChannels was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListChannels.Lock()
+	mock.calls.ListChannels = append(mock.calls.ListChannels, callInfo)
+	mock.lockListChannels.Unlock()
+	return mock.ListChannelsFunc(ctx)
+}
+
+// ListChannelsCalls gets all the calls that were made to ListChannels.
+// Check the length with:
+//
+//	len(mockedDB.ListChannelsCalls())
+func (mock *DBMock) ListChannelsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListChannels.RLock()
+	calls = mock.calls.ListChannels
+	mock.lockListChannels.RUnlock()
+	return calls
+}
+
+// ListChannelsDueForPoll calls ListChannelsDueForPollFunc.
+func (mock *DBMock) ListChannelsDueForPoll(ctx context.Context, now time.Time) ([]models.ChannelPollState, error) {
+	if mock.ListChannelsDueForPollFunc == nil {
+		panic("DBMock.ListChannelsDueForPollFunc: method is nil but DB.ListChannelsDueForPoll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Now time.Time
+	}{
+		Ctx: ctx,
+		Now: now,
+	}
+	mock.lockListChannelsDueForPoll.Lock()
+	mock.calls.ListChannelsDueForPoll = append(mock.calls.ListChannelsDueForPoll, callInfo)
+	mock.lockListChannelsDueForPoll.Unlock()
+	return mock.ListChannelsDueForPollFunc(ctx, now)
+}
+
+// ListChannelsDueForPollCalls gets all the calls that were made to ListChannelsDueForPoll.
+// Check the length with:
+//
+//	len(mockedDB.ListChannelsDueForPollCalls())
+func (mock *DBMock) ListChannelsDueForPollCalls() []struct {
+	Ctx context.Context
+	Now time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		Now time.Time
+	}
+	mock.lockListChannelsDueForPoll.RLock()
+	calls = mock.calls.ListChannelsDueForPoll
+	mock.lockListChannelsDueForPoll.RUnlock()
+	return calls
+}
+
+// ListChannelsForMetadataRefresh calls ListChannelsForMetadataRefreshFunc.
+func (mock *DBMock) ListChannelsForMetadataRefresh(ctx context.Context, refreshedBefore time.Time) ([]models.Channel, error) {
+	if mock.ListChannelsForMetadataRefreshFunc == nil {
+		panic("DBMock.ListChannelsForMetadataRefreshFunc: method is nil but DB.ListChannelsForMetadataRefresh was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		RefreshedBefore time.Time
+	}{
+		Ctx:             ctx,
+		RefreshedBefore: refreshedBefore,
+	}
+	mock.lockListChannelsForMetadataRefresh.Lock()
+	mock.calls.ListChannelsForMetadataRefresh = append(mock.calls.ListChannelsForMetadataRefresh, callInfo)
+	mock.lockListChannelsForMetadataRefresh.Unlock()
+	return mock.ListChannelsForMetadataRefreshFunc(ctx, refreshedBefore)
+}
+
+// ListChannelsForMetadataRefreshCalls gets all the calls that were made to ListChannelsForMetadataRefresh.
+// Check the length with:
+//
+//	len(mockedDB.ListChannelsForMetadataRefreshCalls())
+func (mock *DBMock) ListChannelsForMetadataRefreshCalls() []struct {
+	Ctx             context.Context
+	RefreshedBefore time.Time
+} {
+	var calls []struct {
+		Ctx             context.Context
+		RefreshedBefore time.Time
+	}
+	mock.lockListChannelsForMetadataRefresh.RLock()
+	calls = mock.calls.ListChannelsForMetadataRefresh
+	mock.lockListChannelsForMetadataRefresh.RUnlock()
+	return calls
+}
+
+// ListChannelsForWebSubRenewal calls ListChannelsForWebSubRenewalFunc.
+func (mock *DBMock) ListChannelsForWebSubRenewal(ctx context.Context, expiringBefore time.Time, requestedBefore time.Time) ([]models.Channel, error) {
+	if mock.ListChannelsForWebSubRenewalFunc == nil {
+		panic("DBMock.ListChannelsForWebSubRenewalFunc: method is nil but DB.ListChannelsForWebSubRenewal was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		ExpiringBefore  time.Time
+		RequestedBefore time.Time
+	}{
+		Ctx:             ctx,
+		ExpiringBefore:  expiringBefore,
+		RequestedBefore: requestedBefore,
+	}
+	mock.lockListChannelsForWebSubRenewal.Lock()
+	mock.calls.ListChannelsForWebSubRenewal = append(mock.calls.ListChannelsForWebSubRenewal, callInfo)
+	mock.lockListChannelsForWebSubRenewal.Unlock()
+	return mock.ListChannelsForWebSubRenewalFunc(ctx, expiringBefore, requestedBefore)
+}
+
+// ListChannelsForWebSubRenewalCalls gets all the calls that were made to ListChannelsForWebSubRenewal.
+// Check the length with:
+//
+//	len(mockedDB.ListChannelsForWebSubRenewalCalls())
+func (mock *DBMock) ListChannelsForWebSubRenewalCalls() []struct {
+	Ctx             context.Context
+	ExpiringBefore  time.Time
+	RequestedBefore time.Time
+} {
+	var calls []struct {
+		Ctx             context.Context
+		ExpiringBefore  time.Time
+		RequestedBefore time.Time
+	}
+	mock.lockListChannelsForWebSubRenewal.RLock()
+	calls = mock.calls.ListChannelsForWebSubRenewal
+	mock.lockListChannelsForWebSubRenewal.RUnlock()
+	return calls
+}
+
+// RecordChannelFetch calls RecordChannelFetchFunc.
+func (mock *DBMock) RecordChannelFetch(ctx context.Context, channelID string, outcome models.FetchOutcome, goneAfter int) error {
+	if mock.RecordChannelFetchFunc == nil {
+		panic("DBMock.RecordChannelFetchFunc: method is nil but DB.RecordChannelFetch was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		Outcome   models.FetchOutcome
+		GoneAfter int
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Outcome:   outcome,
+		GoneAfter: goneAfter,
+	}
+	mock.lockRecordChannelFetch.Lock()
+	mock.calls.RecordChannelFetch = append(mock.calls.RecordChannelFetch, callInfo)
+	mock.lockRecordChannelFetch.Unlock()
+	return mock.RecordChannelFetchFunc(ctx, channelID, outcome, goneAfter)
+}
+
+// RecordChannelFetchCalls gets all the calls that were made to RecordChannelFetch.
+// Check the length with:
+//
+//	len(mockedDB.RecordChannelFetchCalls())
+func (mock *DBMock) RecordChannelFetchCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Outcome   models.FetchOutcome
+	GoneAfter int
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		Outcome   models.FetchOutcome
+		GoneAfter int
+	}
+	mock.lockRecordChannelFetch.RLock()
+	calls = mock.calls.RecordChannelFetch
+	mock.lockRecordChannelFetch.RUnlock()
+	return calls
+}
+
+// RestoreVideo calls RestoreVideoFunc.
+func (mock *DBMock) RestoreVideo(ctx context.Context, videoID string) error {
+	if mock.RestoreVideoFunc == nil {
+		panic("DBMock.RestoreVideoFunc: method is nil but DB.RestoreVideo was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		VideoID string
+	}{
+		Ctx:     ctx,
+		VideoID: videoID,
+	}
+	mock.lockRestoreVideo.Lock()
+	mock.calls.RestoreVideo = append(mock.calls.RestoreVideo, callInfo)
+	mock.lockRestoreVideo.Unlock()
+	return mock.RestoreVideoFunc(ctx, videoID)
+}
+
+// RestoreVideoCalls gets all the calls that were made to RestoreVideo.
+// Check the length with:
+//
+//	len(mockedDB.RestoreVideoCalls())
+func (mock *DBMock) RestoreVideoCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		VideoID string
+	}
+	mock.lockRestoreVideo.RLock()
+	calls = mock.calls.RestoreVideo
+	mock.lockRestoreVideo.RUnlock()
+	return calls
+}
+
+// SetChannelFeedValidators calls SetChannelFeedValidatorsFunc.
+func (mock *DBMock) SetChannelFeedValidators(ctx context.Context, channelID string, etag string, lastModified string) error {
+	if mock.SetChannelFeedValidatorsFunc == nil {
+		panic("DBMock.SetChannelFeedValidatorsFunc: method is nil but DB.SetChannelFeedValidators was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ChannelID    string
+		Etag         string
+		LastModified string
+	}{
+		Ctx:          ctx,
+		ChannelID:    channelID,
+		Etag:         etag,
+		LastModified: lastModified,
+	}
+	mock.lockSetChannelFeedValidators.Lock()
+	mock.calls.SetChannelFeedValidators = append(mock.calls.SetChannelFeedValidators, callInfo)
+	mock.lockSetChannelFeedValidators.Unlock()
+	return mock.SetChannelFeedValidatorsFunc(ctx, channelID, etag, lastModified)
+}
+
+// SetChannelFeedValidatorsCalls gets all the calls that were made to SetChannelFeedValidators.
+// Check the length with:
+//
+//	len(mockedDB.SetChannelFeedValidatorsCalls())
+func (mock *DBMock) SetChannelFeedValidatorsCalls() []struct {
+	Ctx          context.Context
+	ChannelID    string
+	Etag         string
+	LastModified string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ChannelID    string
+		Etag         string
+		LastModified string
+	}
+	mock.lockSetChannelFeedValidators.RLock()
+	calls = mock.calls.SetChannelFeedValidators
+	mock.lockSetChannelFeedValidators.RUnlock()
+	return calls
+}
+
+// SetChannelPollIntervals calls SetChannelPollIntervalsFunc.
+func (mock *DBMock) SetChannelPollIntervals(ctx context.Context, channelID string, minInterval time.Duration, maxInterval time.Duration) error {
+	if mock.SetChannelPollIntervalsFunc == nil {
+		panic("DBMock.SetChannelPollIntervalsFunc: method is nil but DB.SetChannelPollIntervals was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		ChannelID   string
+		MinInterval time.Duration
+		MaxInterval time.Duration
+	}{
+		Ctx:         ctx,
+		ChannelID:   channelID,
+		MinInterval: minInterval,
+		MaxInterval: maxInterval,
+	}
+	mock.lockSetChannelPollIntervals.Lock()
+	mock.calls.SetChannelPollIntervals = append(mock.calls.SetChannelPollIntervals, callInfo)
+	mock.lockSetChannelPollIntervals.Unlock()
+	return mock.SetChannelPollIntervalsFunc(ctx, channelID, minInterval, maxInterval)
+}
+
+// SetChannelPollIntervalsCalls gets all the calls that were made to SetChannelPollIntervals.
+// Check the length with:
+//
+//	len(mockedDB.SetChannelPollIntervalsCalls())
+func (mock *DBMock) SetChannelPollIntervalsCalls() []struct {
+	Ctx         context.Context
+	ChannelID   string
+	MinInterval time.Duration
+	MaxInterval time.Duration
+} {
+	var calls []struct {
+		Ctx         context.Context
+		ChannelID   string
+		MinInterval time.Duration
+		MaxInterval time.Duration
 	}
-	mock.lockGetLiveVideos.RLock()
-	calls = mock.calls.GetLiveVideos
-	mock.lockGetLiveVideos.RUnlock()
+	mock.lockSetChannelPollIntervals.RLock()
+	calls = mock.calls.SetChannelPollIntervals
+	mock.lockSetChannelPollIntervals.RUnlock()
 	return calls
 }
 
-// GetNewVideos calls GetNewVideosFunc.
-func (mock *DBMock) GetNewVideos(ctx context.Context, sortDesc bool) ([]models.Video, error) {
-	if mock.GetNewVideosFunc == nil {
-		panic("DBMock.GetNewVideosFunc: method is nil but DB.GetNewVideos was just called")
+// SetChannelPollResult calls SetChannelPollResultFunc.
+func (mock *DBMock) SetChannelPollResult(ctx context.Context, channelID string, nextPollAt time.Time) error {
+	if mock.SetChannelPollResultFunc == nil {
+		panic("DBMock.SetChannelPollResultFunc: method is nil but DB.SetChannelPollResult was just called")
 	}
 	callInfo := struct {
-		Ctx      context.Context
-		SortDesc bool
+		Ctx        context.Context
+		ChannelID  string
+		NextPollAt time.Time
 	}{
-		Ctx:      ctx,
-		SortDesc: sortDesc,
+		Ctx:        ctx,
+		ChannelID:  channelID,
+		NextPollAt: nextPollAt,
 	}
-	mock.lockGetNewVideos.Lock()
-	mock.calls.GetNewVideos = append(mock.calls.GetNewVideos, callInfo)
-	mock.lockGetNewVideos.Unlock()
-	return mock.GetNewVideosFunc(ctx, sortDesc)
+	mock.lockSetChannelPollResult.Lock()
+	mock.calls.SetChannelPollResult = append(mock.calls.SetChannelPollResult, callInfo)
+	mock.lockSetChannelPollResult.Unlock()
+	return mock.SetChannelPollResultFunc(ctx, channelID, nextPollAt)
 }
 
-// GetNewVideosCalls gets all the calls that were made to GetNewVideos.
+// SetChannelPollResultCalls gets all the calls that were made to SetChannelPollResult.
 // Check the length with:
 //
-//	len(mockedDB.GetNewVideosCalls())
-func (mock *DBMock) GetNewVideosCalls() []struct {
-	Ctx      context.Context
-	SortDesc bool
+//	len(mockedDB.SetChannelPollResultCalls())
+func (mock *DBMock) SetChannelPollResultCalls() []struct {
+	Ctx        context.Context
+	ChannelID  string
+	NextPollAt time.Time
 } {
 	var calls []struct {
-		Ctx      context.Context
-		SortDesc bool
+		Ctx        context.Context
+		ChannelID  string
+		NextPollAt time.Time
 	}
-	mock.lockGetNewVideos.RLock()
-	calls = mock.calls.GetNewVideos
-	mock.lockGetNewVideos.RUnlock()
+	mock.lockSetChannelPollResult.RLock()
+	calls = mock.calls.SetChannelPollResult
+	mock.lockSetChannelPollResult.RUnlock()
 	return calls
 }
 
-// GetVideo calls GetVideoFunc.
-func (mock *DBMock) GetVideo(ctx context.Context, videoID string) (*models.Video, error) {
-	if mock.GetVideoFunc == nil {
-		panic("DBMock.GetVideoFunc: method is nil but DB.GetVideo was just called")
+// SetChannelTitleFilters calls SetChannelTitleFiltersFunc.
+func (mock *DBMock) SetChannelTitleFilters(ctx context.Context, channelID string, include []string, exclude []string) error {
+	if mock.SetChannelTitleFiltersFunc == nil {
+		panic("DBMock.SetChannelTitleFiltersFunc: method is nil but DB.SetChannelTitleFilters was just called")
 	}
 	callInfo := struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx       context.Context
+		ChannelID string
+		Include   []string
+		Exclude   []string
 	}{
-		Ctx:     ctx,
-		VideoID: videoID,
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Include:   include,
+		Exclude:   exclude,
 	}
-	mock.lockGetVideo.Lock()
-	mock.calls.GetVideo = append(mock.calls.GetVideo, callInfo)
-	mock.lockGetVideo.Unlock()
-	return mock.GetVideoFunc(ctx, videoID)
+	mock.lockSetChannelTitleFilters.Lock()
+	mock.calls.SetChannelTitleFilters = append(mock.calls.SetChannelTitleFilters, callInfo)
+	mock.lockSetChannelTitleFilters.Unlock()
+	return mock.SetChannelTitleFiltersFunc(ctx, channelID, include, exclude)
 }
 
-// GetVideoCalls gets all the calls that were made to GetVideo.
+// SetChannelTitleFiltersCalls gets all the calls that were made to SetChannelTitleFilters.
 // Check the length with:
 //
-//	len(mockedDB.GetVideoCalls())
-func (mock *DBMock) GetVideoCalls() []struct {
-	Ctx     context.Context
-	VideoID string
+//	len(mockedDB.SetChannelTitleFiltersCalls())
+func (mock *DBMock) SetChannelTitleFiltersCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Include   []string
+	Exclude   []string
 } {
 	var calls []struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx       context.Context
+		ChannelID string
+		Include   []string
+		Exclude   []string
 	}
-	mock.lockGetVideo.RLock()
-	calls = mock.calls.GetVideo
-	mock.lockGetVideo.RUnlock()
+	mock.lockSetChannelTitleFilters.RLock()
+	calls = mock.calls.SetChannelTitleFilters
+	mock.lockSetChannelTitleFilters.RUnlock()
 	return calls
 }
 
-// GetVideosForCleanup calls GetVideosForCleanupFunc.
-func (mock *DBMock) GetVideosForCleanup(ctx context.Context, olderThan time.Duration) ([]models.Video, error) {
-	if mock.GetVideosForCleanupFunc == nil {
-		panic("DBMock.GetVideosForCleanupFunc: method is nil but DB.GetVideosForCleanup was just called")
+// SetChannelWebSubLease calls SetChannelWebSubLeaseFunc.
+func (mock *DBMock) SetChannelWebSubLease(ctx context.Context, channelID string, expiresAt *time.Time) error {
+	if mock.SetChannelWebSubLeaseFunc == nil {
+		panic("DBMock.SetChannelWebSubLeaseFunc: method is nil but DB.SetChannelWebSubLease was just called")
 	}
 	callInfo := struct {
 		Ctx       context.Context
-		OlderThan time.Duration
+		ChannelID string
+		ExpiresAt *time.Time
 	}{
 		Ctx:       ctx,
-		OlderThan: olderThan,
+		ChannelID: channelID,
+		ExpiresAt: expiresAt,
 	}
-	mock.lockGetVideosForCleanup.Lock()
-	mock.calls.GetVideosForCleanup = append(mock.calls.GetVideosForCleanup, callInfo)
-	mock.lockGetVideosForCleanup.Unlock()
-	return mock.GetVideosForCleanupFunc(ctx, olderThan)
+	mock.lockSetChannelWebSubLease.Lock()
+	mock.calls.SetChannelWebSubLease = append(mock.calls.SetChannelWebSubLease, callInfo)
+	mock.lockSetChannelWebSubLease.Unlock()
+	return mock.SetChannelWebSubLeaseFunc(ctx, channelID, expiresAt)
 }
 
-// GetVideosForCleanupCalls gets all the calls that were made to GetVideosForCleanup.
+// SetChannelWebSubLeaseCalls gets all the calls that were made to SetChannelWebSubLease.
 // Check the length with:
 //
-//	len(mockedDB.GetVideosForCleanupCalls())
-func (mock *DBMock) GetVideosForCleanupCalls() []struct {
+//	len(mockedDB.SetChannelWebSubLeaseCalls())
+func (mock *DBMock) SetChannelWebSubLeaseCalls() []struct {
 	Ctx       context.Context
-	OlderThan time.Duration
+	ChannelID string
+	ExpiresAt *time.Time
 } {
 	var calls []struct {
 		Ctx       context.Context
-		OlderThan time.Duration
+		ChannelID string
+		ExpiresAt *time.Time
 	}
-	mock.lockGetVideosForCleanup.RLock()
-	calls = mock.calls.GetVideosForCleanup
-	mock.lockGetVideosForCleanup.RUnlock()
+	mock.lockSetChannelWebSubLease.RLock()
+	calls = mock.calls.SetChannelWebSubLease
+	mock.lockSetChannelWebSubLease.RUnlock()
 	return calls
 }
 
-// GetWatchedVideos calls GetWatchedVideosFunc.
-func (mock *DBMock) GetWatchedVideos(ctx context.Context, sortDesc bool, limit int, offset int) ([]models.Video, error) {
-	if mock.GetWatchedVideosFunc == nil {
-		panic("DBMock.GetWatchedVideosFunc: method is nil but DB.GetWatchedVideos was just called")
+// SetChannelWebSubRequested calls SetChannelWebSubRequestedFunc.
+func (mock *DBMock) SetChannelWebSubRequested(ctx context.Context, channelID string) error {
+	if mock.SetChannelWebSubRequestedFunc == nil {
+		panic("DBMock.SetChannelWebSubRequestedFunc: method is nil but DB.SetChannelWebSubRequested was just called")
 	}
 	callInfo := struct {
-		Ctx      context.Context
-		SortDesc bool
-		Limit    int
-		Offset   int
+		Ctx       context.Context
+		ChannelID string
 	}{
-		Ctx:      ctx,
-		SortDesc: sortDesc,
-		Limit:    limit,
-		Offset:   offset,
+		Ctx:       ctx,
+		ChannelID: channelID,
 	}
-	mock.lockGetWatchedVideos.Lock()
-	mock.calls.GetWatchedVideos = append(mock.calls.GetWatchedVideos, callInfo)
-	mock.lockGetWatchedVideos.Unlock()
-	return mock.GetWatchedVideosFunc(ctx, sortDesc, limit, offset)
+	mock.lockSetChannelWebSubRequested.Lock()
+	mock.calls.SetChannelWebSubRequested = append(mock.calls.SetChannelWebSubRequested, callInfo)
+	mock.lockSetChannelWebSubRequested.Unlock()
+	return mock.SetChannelWebSubRequestedFunc(ctx, channelID)
 }
 
-// GetWatchedVideosCalls gets all the calls that were made to GetWatchedVideos.
+// SetChannelWebSubRequestedCalls gets all the calls that were made to SetChannelWebSubRequested.
 // Check the length with:
 //
-//	len(mockedDB.GetWatchedVideosCalls())
-func (mock *DBMock) GetWatchedVideosCalls() []struct {
-	Ctx      context.Context
-	SortDesc bool
-	Limit    int
-	Offset   int
+//	len(mockedDB.SetChannelWebSubRequestedCalls())
+func (mock *DBMock) SetChannelWebSubRequestedCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
 } {
 	var calls []struct {
-		Ctx      context.Context
-		SortDesc bool
-		Limit    int
-		Offset   int
+		Ctx       context.Context
+		ChannelID string
 	}
-	mock.lockGetWatchedVideos.RLock()
-	calls = mock.calls.GetWatchedVideos
-	mock.lockGetWatchedVideos.RUnlock()
+	mock.lockSetChannelWebSubRequested.RLock()
+	calls = mock.calls.SetChannelWebSubRequested
+	mock.lockSetChannelWebSubRequested.RUnlock()
 	return calls
 }
 
-// HasVideo calls HasVideoFunc.
-func (mock *DBMock) HasVideo(ctx context.Context, videoID string) (bool, error) {
-	if mock.HasVideoFunc == nil {
-		panic("DBMock.HasVideoFunc: method is nil but DB.HasVideo was just called")
+// SetChannelsMetadataRefreshed calls SetChannelsMetadataRefreshedFunc.
+func (mock *DBMock) SetChannelsMetadataRefreshed(ctx context.Context, channelIDs []string) error {
+	if mock.SetChannelsMetadataRefreshedFunc == nil {
+		panic("DBMock.SetChannelsMetadataRefreshedFunc: method is nil but DB.SetChannelsMetadataRefreshed was just called")
 	}
 	callInfo := struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx        context.Context
+		ChannelIDs []string
 	}{
-		Ctx:     ctx,
-		VideoID: videoID,
+		Ctx:        ctx,
+		ChannelIDs: channelIDs,
 	}
-	mock.lockHasVideo.Lock()
-	mock.calls.HasVideo = append(mock.calls.HasVideo, callInfo)
-	mock.lockHasVideo.Unlock()
-	return mock.HasVideoFunc(ctx, videoID)
+	mock.lockSetChannelsMetadataRefreshed.Lock()
+	mock.calls.SetChannelsMetadataRefreshed = append(mock.calls.SetChannelsMetadataRefreshed, callInfo)
+	mock.lockSetChannelsMetadataRefreshed.Unlock()
+	return mock.SetChannelsMetadataRefreshedFunc(ctx, channelIDs)
 }
 
-// HasVideoCalls gets all the calls that were made to HasVideo.
+// SetChannelsMetadataRefreshedCalls gets all the calls that were made to SetChannelsMetadataRefreshed.
 // Check the length with:
 //
-//	len(mockedDB.HasVideoCalls())
-func (mock *DBMock) HasVideoCalls() []struct {
-	Ctx     context.Context
-	VideoID string
+//	len(mockedDB.SetChannelsMetadataRefreshedCalls())
+func (mock *DBMock) SetChannelsMetadataRefreshedCalls() []struct {
+	Ctx        context.Context
+	ChannelIDs []string
 } {
 	var calls []struct {
-		Ctx     context.Context
-		VideoID string
+		Ctx        context.Context
+		ChannelIDs []string
 	}
-	mock.lockHasVideo.RLock()
-	calls = mock.calls.HasVideo
-	mock.lockHasVideo.RUnlock()
+	mock.lockSetChannelsMetadataRefreshed.RLock()
+	calls = mock.calls.SetChannelsMetadataRefreshed
+	mock.lockSetChannelsMetadataRefreshed.RUnlock()
 	return calls
 }
 
-// ListChannels calls ListChannelsFunc.
-func (mock *DBMock) ListChannels(ctx context.Context) ([]models.Channel, error) {
-	if mock.ListChannelsFunc == nil {
-		panic("DBMock.ListChannelsFunc: method is nil but DB.ListChannels was just called")
+// SetVideoAvailability calls SetVideoAvailabilityFunc.
+func (mock *DBMock) SetVideoAvailability(ctx context.Context, videoID string, reason models.UnavailableReason) error {
+	if mock.SetVideoAvailabilityFunc == nil {
+		panic("DBMock.SetVideoAvailabilityFunc: method is nil but DB.SetVideoAvailability was just called")
 	}
 	callInfo := struct {
-		Ctx context.Context
+		Ctx     context.Context
+		VideoID string
+		Reason  models.UnavailableReason
 	}{
-		Ctx: ctx,
+		Ctx:     ctx,
+		VideoID: videoID,
+		Reason:  reason,
 	}
-	mock.lockListChannels.Lock()
-	mock.calls.ListChannels = append(mock.calls.ListChannels, callInfo)
-	mock.lockListChannels.Unlock()
-	return mock.ListChannelsFunc(ctx)
+	mock.lockSetVideoAvailability.Lock()
+	mock.calls.SetVideoAvailability = append(mock.calls.SetVideoAvailability, callInfo)
+	mock.lockSetVideoAvailability.Unlock()
+	return mock.SetVideoAvailabilityFunc(ctx, videoID, reason)
 }
 
-// ListChannelsCalls gets all the calls that were made to ListChannels.
+// SetVideoAvailabilityCalls gets all the calls that were made to SetVideoAvailability.
 // Check the length with:
 //
-//	len(mockedDB.ListChannelsCalls())
-func (mock *DBMock) ListChannelsCalls() []struct {
-	Ctx context.Context
+//	len(mockedDB.SetVideoAvailabilityCalls())
+func (mock *DBMock) SetVideoAvailabilityCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+	Reason  models.UnavailableReason
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx     context.Context
+		VideoID string
+		Reason  models.UnavailableReason
 	}
-	mock.lockListChannels.RLock()
-	calls = mock.calls.ListChannels
-	mock.lockListChannels.RUnlock()
+	mock.lockSetVideoAvailability.RLock()
+	calls = mock.calls.SetVideoAvailability
+	mock.lockSetVideoAvailability.RUnlock()
 	return calls
 }
 
@@ -894,6 +2370,42 @@ func (mock *DBMock) SetVideoDownloadStatusCalls() []struct {
 	return calls
 }
 
+// SetVideoDuration calls SetVideoDurationFunc.
+func (mock *DBMock) SetVideoDuration(ctx context.Context, video models.Video) error {
+	if mock.SetVideoDurationFunc == nil {
+		panic("DBMock.SetVideoDurationFunc: method is nil but DB.SetVideoDuration was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Video models.Video
+	}{
+		Ctx:   ctx,
+		Video: video,
+	}
+	mock.lockSetVideoDuration.Lock()
+	mock.calls.SetVideoDuration = append(mock.calls.SetVideoDuration, callInfo)
+	mock.lockSetVideoDuration.Unlock()
+	return mock.SetVideoDurationFunc(ctx, video)
+}
+
+// SetVideoDurationCalls gets all the calls that were made to SetVideoDuration.
+// Check the length with:
+//
+//	len(mockedDB.SetVideoDurationCalls())
+func (mock *DBMock) SetVideoDurationCalls() []struct {
+	Ctx   context.Context
+	Video models.Video
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Video models.Video
+	}
+	mock.lockSetVideoDuration.RLock()
+	calls = mock.calls.SetVideoDuration
+	mock.lockSetVideoDuration.RUnlock()
+	return calls
+}
+
 // SetVideoProgress calls SetVideoProgressFunc.
 func (mock *DBMock) SetVideoProgress(ctx context.Context, videoID string, progress int) (*models.Video, error) {
 	if mock.SetVideoProgressFunc == nil {
@@ -934,6 +2446,46 @@ func (mock *DBMock) SetVideoProgressCalls() []struct {
 	return calls
 }
 
+// SetVideoShortOverride calls SetVideoShortOverrideFunc.
+func (mock *DBMock) SetVideoShortOverride(ctx context.Context, videoID string, isShort bool) error {
+	if mock.SetVideoShortOverrideFunc == nil {
+		panic("DBMock.SetVideoShortOverrideFunc: method is nil but DB.SetVideoShortOverride was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		VideoID string
+		IsShort bool
+	}{
+		Ctx:     ctx,
+		VideoID: videoID,
+		IsShort: isShort,
+	}
+	mock.lockSetVideoShortOverride.Lock()
+	mock.calls.SetVideoShortOverride = append(mock.calls.SetVideoShortOverride, callInfo)
+	mock.lockSetVideoShortOverride.Unlock()
+	return mock.SetVideoShortOverrideFunc(ctx, videoID, isShort)
+}
+
+// SetVideoShortOverrideCalls gets all the calls that were made to SetVideoShortOverride.
+// Check the length with:
+//
+//	len(mockedDB.SetVideoShortOverrideCalls())
+func (mock *DBMock) SetVideoShortOverrideCalls() []struct {
+	Ctx     context.Context
+	VideoID string
+	IsShort bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		VideoID string
+		IsShort bool
+	}
+	mock.lockSetVideoShortOverride.RLock()
+	calls = mock.calls.SetVideoShortOverride
+	mock.lockSetVideoShortOverride.RUnlock()
+	return calls
+}
+
 // SetVideoWatchTime calls SetVideoWatchTimeFunc.
 func (mock *DBMock) SetVideoWatchTime(ctx context.Context, videoID string, watchTime *time.Time) error {
 	if mock.SetVideoWatchTimeFunc == nil {
@@ -974,6 +2526,42 @@ func (mock *DBMock) SetVideoWatchTimeCalls() []struct {
 	return calls
 }
 
+// SetVideoWatchTimes calls SetVideoWatchTimesFunc.
+func (mock *DBMock) SetVideoWatchTimes(ctx context.Context, watchTimes map[string]time.Time) error {
+	if mock.SetVideoWatchTimesFunc == nil {
+		panic("DBMock.SetVideoWatchTimesFunc: method is nil but DB.SetVideoWatchTimes was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		WatchTimes map[string]time.Time
+	}{
+		Ctx:        ctx,
+		WatchTimes: watchTimes,
+	}
+	mock.lockSetVideoWatchTimes.Lock()
+	mock.calls.SetVideoWatchTimes = append(mock.calls.SetVideoWatchTimes, callInfo)
+	mock.lockSetVideoWatchTimes.Unlock()
+	return mock.SetVideoWatchTimesFunc(ctx, watchTimes)
+}
+
+// SetVideoWatchTimesCalls gets all the calls that were made to SetVideoWatchTimes.
+// Check the length with:
+//
+//	len(mockedDB.SetVideoWatchTimesCalls())
+func (mock *DBMock) SetVideoWatchTimesCalls() []struct {
+	Ctx        context.Context
+	WatchTimes map[string]time.Time
+} {
+	var calls []struct {
+		Ctx        context.Context
+		WatchTimes map[string]time.Time
+	}
+	mock.lockSetVideoWatchTimes.RLock()
+	calls = mock.calls.SetVideoWatchTimes
+	mock.lockSetVideoWatchTimes.RUnlock()
+	return calls
+}
+
 // SubscribeToChannel calls SubscribeToChannelFunc.
 func (mock *DBMock) SubscribeToChannel(ctx context.Context, channel models.Channel) error {
 	if mock.SubscribeToChannelFunc == nil {
@@ -1086,26 +2674,94 @@ func (mock *DBMock) UnsubscribeFromChannelCalls() []struct {
 	return calls
 }
 
+// UpdateChannelMetadata calls UpdateChannelMetadataFunc.
+func (mock *DBMock) UpdateChannelMetadata(ctx context.Context, metadata []models.ChannelMetadata) ([]string, error) {
+	if mock.UpdateChannelMetadataFunc == nil {
+		panic("DBMock.UpdateChannelMetadataFunc: method is nil but DB.UpdateChannelMetadata was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Metadata []models.ChannelMetadata
+	}{
+		Ctx:      ctx,
+		Metadata: metadata,
+	}
+	mock.lockUpdateChannelMetadata.Lock()
+	mock.calls.UpdateChannelMetadata = append(mock.calls.UpdateChannelMetadata, callInfo)
+	mock.lockUpdateChannelMetadata.Unlock()
+	return mock.UpdateChannelMetadataFunc(ctx, metadata)
+}
+
+// UpdateChannelMetadataCalls gets all the calls that were made to UpdateChannelMetadata.
+// Check the length with:
+//
+//	len(mockedDB.UpdateChannelMetadataCalls())
+func (mock *DBMock) UpdateChannelMetadataCalls() []struct {
+	Ctx      context.Context
+	Metadata []models.ChannelMetadata
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Metadata []models.ChannelMetadata
+	}
+	mock.lockUpdateChannelMetadata.RLock()
+	calls = mock.calls.UpdateChannelMetadata
+	mock.lockUpdateChannelMetadata.RUnlock()
+	return calls
+}
+
+// UpdateKnownVideos calls UpdateKnownVideosFunc.
+func (mock *DBMock) UpdateKnownVideos(ctx context.Context, videos []models.Video) ([]string, error) {
+	if mock.UpdateKnownVideosFunc == nil {
+		panic("DBMock.UpdateKnownVideosFunc: method is nil but DB.UpdateKnownVideos was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Videos []models.Video
+	}{
+		Ctx:    ctx,
+		Videos: videos,
+	}
+	mock.lockUpdateKnownVideos.Lock()
+	mock.calls.UpdateKnownVideos = append(mock.calls.UpdateKnownVideos, callInfo)
+	mock.lockUpdateKnownVideos.Unlock()
+	return mock.UpdateKnownVideosFunc(ctx, videos)
+}
+
+// UpdateKnownVideosCalls gets all the calls that were made to UpdateKnownVideos.
+// Check the length with:
+//
+//	len(mockedDB.UpdateKnownVideosCalls())
+func (mock *DBMock) UpdateKnownVideosCalls() []struct {
+	Ctx    context.Context
+	Videos []models.Video
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Videos []models.Video
+	}
+	mock.lockUpdateKnownVideos.RLock()
+	calls = mock.calls.UpdateKnownVideos
+	mock.lockUpdateKnownVideos.RUnlock()
+	return calls
+}
+
 // UpdateVideoLiveStatus calls UpdateVideoLiveStatusFunc.
-func (mock *DBMock) UpdateVideoLiveStatus(ctx context.Context, videoID string, isLive bool, duration int) error {
+func (mock *DBMock) UpdateVideoLiveStatus(ctx context.Context, video models.Video) error {
 	if mock.UpdateVideoLiveStatusFunc == nil {
 		panic("DBMock.UpdateVideoLiveStatusFunc: method is nil but DB.UpdateVideoLiveStatus was just called")
 	}
 	callInfo := struct {
-		Ctx      context.Context
-		VideoID  string
-		IsLive   bool
-		Duration int
+		Ctx   context.Context
+		Video models.Video
 	}{
-		Ctx:      ctx,
-		VideoID:  videoID,
-		IsLive:   isLive,
-		Duration: duration,
+		Ctx:   ctx,
+		Video: video,
 	}
 	mock.lockUpdateVideoLiveStatus.Lock()
 	mock.calls.UpdateVideoLiveStatus = append(mock.calls.UpdateVideoLiveStatus, callInfo)
 	mock.lockUpdateVideoLiveStatus.Unlock()
-	return mock.UpdateVideoLiveStatusFunc(ctx, videoID, isLive, duration)
+	return mock.UpdateVideoLiveStatusFunc(ctx, video)
 }
 
 // UpdateVideoLiveStatusCalls gets all the calls that were made to UpdateVideoLiveStatus.
@@ -1113,16 +2769,12 @@ func (mock *DBMock) UpdateVideoLiveStatus(ctx context.Context, videoID string, i
 //
 //	len(mockedDB.UpdateVideoLiveStatusCalls())
 func (mock *DBMock) UpdateVideoLiveStatusCalls() []struct {
-	Ctx      context.Context
-	VideoID  string
-	IsLive   bool
-	Duration int
+	Ctx   context.Context
+	Video models.Video
 } {
 	var calls []struct {
-		Ctx      context.Context
-		VideoID  string
-		IsLive   bool
-		Duration int
+		Ctx   context.Context
+		Video models.Video
 	}
 	mock.lockUpdateVideoLiveStatus.RLock()
 	calls = mock.calls.UpdateVideoLiveStatus
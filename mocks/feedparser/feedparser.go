@@ -4,7 +4,10 @@
 package parser_mock
 
 import (
+	"context"
 	"github.com/TheEdgeOfRage/ytrssil-api/feedparser"
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+	"io"
 	"sync"
 )
 
@@ -18,9 +21,15 @@ var _ feedparser.Parser = &ParserMock{}
 //
 //		// make and configure a mocked feedparser.Parser
 //		mockedParser := &ParserMock{
-//			ParseFunc: func(channelID string) (*feedparser.Channel, error) {
+//			ParseFunc: func(ctx context.Context, channel models.Channel) (*feedparser.Channel, error) {
 //				panic("mock out the Parse method")
 //			},
+//			ParseIfModifiedFunc: func(ctx context.Context, channel models.Channel, validators feedparser.CacheValidators) (*feedparser.Channel, error) {
+//				panic("mock out the ParseIfModified method")
+//			},
+//			ParseReaderFunc: func(channel models.Channel, reader io.Reader) (*feedparser.Channel, error) {
+//				panic("mock out the ParseReader method")
+//			},
 //		}
 //
 //		// use mockedParser in code that requires feedparser.Parser
@@ -29,33 +38,61 @@ var _ feedparser.Parser = &ParserMock{}
 //	}
 type ParserMock struct {
 	// ParseFunc mocks the Parse method.
-	ParseFunc func(channelID string) (*feedparser.Channel, error)
+	ParseFunc func(ctx context.Context, channel models.Channel) (*feedparser.Channel, error)
+
+	// ParseIfModifiedFunc mocks the ParseIfModified method.
+	ParseIfModifiedFunc func(ctx context.Context, channel models.Channel, validators feedparser.CacheValidators) (*feedparser.Channel, error)
+
+	// ParseReaderFunc mocks the ParseReader method.
+	ParseReaderFunc func(channel models.Channel, reader io.Reader) (*feedparser.Channel, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// Parse holds details about calls to the Parse method.
 		Parse []struct {
-			// ChannelID is the channelID argument value.
-			ChannelID string
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel models.Channel
+		}
+		// ParseIfModified holds details about calls to the ParseIfModified method.
+		ParseIfModified []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel models.Channel
+			// Validators is the validators argument value.
+			Validators feedparser.CacheValidators
+		}
+		// ParseReader holds details about calls to the ParseReader method.
+		ParseReader []struct {
+			// Channel is the channel argument value.
+			Channel models.Channel
+			// Reader is the reader argument value.
+			Reader io.Reader
 		}
 	}
-	lockParse sync.RWMutex
+	lockParse           sync.RWMutex
+	lockParseIfModified sync.RWMutex
+	lockParseReader     sync.RWMutex
 }
 
 // Parse calls ParseFunc.
-func (mock *ParserMock) Parse(channelID string) (*feedparser.Channel, error) {
+func (mock *ParserMock) Parse(ctx context.Context, channel models.Channel) (*feedparser.Channel, error) {
 	if mock.ParseFunc == nil {
 		panic("ParserMock.ParseFunc: method is nil but Parser.Parse was just called")
 	}
 	callInfo := struct {
-		ChannelID string
+		Ctx     context.Context
+		Channel models.Channel
 	}{
-		ChannelID: channelID,
+		Ctx:     ctx,
+		Channel: channel,
 	}
 	mock.lockParse.Lock()
 	mock.calls.Parse = append(mock.calls.Parse, callInfo)
 	mock.lockParse.Unlock()
-	return mock.ParseFunc(channelID)
+	return mock.ParseFunc(ctx, channel)
 }
 
 // ParseCalls gets all the calls that were made to Parse.
@@ -63,13 +100,91 @@ func (mock *ParserMock) Parse(channelID string) (*feedparser.Channel, error) {
 //
 //	len(mockedParser.ParseCalls())
 func (mock *ParserMock) ParseCalls() []struct {
-	ChannelID string
+	Ctx     context.Context
+	Channel models.Channel
 } {
 	var calls []struct {
-		ChannelID string
+		Ctx     context.Context
+		Channel models.Channel
 	}
 	mock.lockParse.RLock()
 	calls = mock.calls.Parse
 	mock.lockParse.RUnlock()
 	return calls
 }
+
+// ParseIfModified calls ParseIfModifiedFunc.
+func (mock *ParserMock) ParseIfModified(ctx context.Context, channel models.Channel, validators feedparser.CacheValidators) (*feedparser.Channel, error) {
+	if mock.ParseIfModifiedFunc == nil {
+		panic("ParserMock.ParseIfModifiedFunc: method is nil but Parser.ParseIfModified was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		Channel    models.Channel
+		Validators feedparser.CacheValidators
+	}{
+		Ctx:        ctx,
+		Channel:    channel,
+		Validators: validators,
+	}
+	mock.lockParseIfModified.Lock()
+	mock.calls.ParseIfModified = append(mock.calls.ParseIfModified, callInfo)
+	mock.lockParseIfModified.Unlock()
+	return mock.ParseIfModifiedFunc(ctx, channel, validators)
+}
+
+// ParseIfModifiedCalls gets all the calls that were made to ParseIfModified.
+// Check the length with:
+//
+//	len(mockedParser.ParseIfModifiedCalls())
+func (mock *ParserMock) ParseIfModifiedCalls() []struct {
+	Ctx        context.Context
+	Channel    models.Channel
+	Validators feedparser.CacheValidators
+} {
+	var calls []struct {
+		Ctx        context.Context
+		Channel    models.Channel
+		Validators feedparser.CacheValidators
+	}
+	mock.lockParseIfModified.RLock()
+	calls = mock.calls.ParseIfModified
+	mock.lockParseIfModified.RUnlock()
+	return calls
+}
+
+// ParseReader calls ParseReaderFunc.
+func (mock *ParserMock) ParseReader(channel models.Channel, reader io.Reader) (*feedparser.Channel, error) {
+	if mock.ParseReaderFunc == nil {
+		panic("ParserMock.ParseReaderFunc: method is nil but Parser.ParseReader was just called")
+	}
+	callInfo := struct {
+		Channel models.Channel
+		Reader  io.Reader
+	}{
+		Channel: channel,
+		Reader:  reader,
+	}
+	mock.lockParseReader.Lock()
+	mock.calls.ParseReader = append(mock.calls.ParseReader, callInfo)
+	mock.lockParseReader.Unlock()
+	return mock.ParseReaderFunc(channel, reader)
+}
+
+// ParseReaderCalls gets all the calls that were made to ParseReader.
+// Check the length with:
+//
+//	len(mockedParser.ParseReaderCalls())
+func (mock *ParserMock) ParseReaderCalls() []struct {
+	Channel models.Channel
+	Reader  io.Reader
+} {
+	var calls []struct {
+		Channel models.Channel
+		Reader  io.Reader
+	}
+	mock.lockParseReader.RLock()
+	calls = mock.calls.ParseReader
+	mock.lockParseReader.RUnlock()
+	return calls
+}
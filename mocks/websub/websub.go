@@ -0,0 +1,157 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package websub_mock
+
+import (
+	"context"
+	"github.com/TheEdgeOfRage/ytrssil-api/lib/websub"
+	"sync"
+	"time"
+)
+
+// Ensure, that ClientMock does implement websub.Client.
+// If this is not the case, regenerate this file with moq.
+var _ websub.Client = &ClientMock{}
+
+// ClientMock is a mock implementation of websub.Client.
+//
+//	func TestSomethingThatUsesClient(t *testing.T) {
+//
+//		// make and configure a mocked websub.Client
+//		mockedClient := &ClientMock{
+//			SubscribeFunc: func(ctx context.Context, topic string, callback string, secret string, lease time.Duration) error {
+//				panic("mock out the Subscribe method")
+//			},
+//			UnsubscribeFunc: func(ctx context.Context, topic string, callback string) error {
+//				panic("mock out the Unsubscribe method")
+//			},
+//		}
+//
+//		// use mockedClient in code that requires websub.Client
+//		// and then make assertions.
+//
+//	}
+type ClientMock struct {
+	// SubscribeFunc mocks the Subscribe method.
+	SubscribeFunc func(ctx context.Context, topic string, callback string, secret string, lease time.Duration) error
+
+	// UnsubscribeFunc mocks the Unsubscribe method.
+	UnsubscribeFunc func(ctx context.Context, topic string, callback string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Subscribe holds details about calls to the Subscribe method.
+		Subscribe []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Topic is the topic argument value.
+			Topic string
+			// Callback is the callback argument value.
+			Callback string
+			// Secret is the secret argument value.
+			Secret string
+			// Lease is the lease argument value.
+			Lease time.Duration
+		}
+		// Unsubscribe holds details about calls to the Unsubscribe method.
+		Unsubscribe []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Topic is the topic argument value.
+			Topic string
+			// Callback is the callback argument value.
+			Callback string
+		}
+	}
+	lockSubscribe   sync.RWMutex
+	lockUnsubscribe sync.RWMutex
+}
+
+// Subscribe calls SubscribeFunc.
+func (mock *ClientMock) Subscribe(ctx context.Context, topic string, callback string, secret string, lease time.Duration) error {
+	if mock.SubscribeFunc == nil {
+		panic("ClientMock.SubscribeFunc: method is nil but Client.Subscribe was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Topic    string
+		Callback string
+		Secret   string
+		Lease    time.Duration
+	}{
+		Ctx:      ctx,
+		Topic:    topic,
+		Callback: callback,
+		Secret:   secret,
+		Lease:    lease,
+	}
+	mock.lockSubscribe.Lock()
+	mock.calls.Subscribe = append(mock.calls.Subscribe, callInfo)
+	mock.lockSubscribe.Unlock()
+	return mock.SubscribeFunc(ctx, topic, callback, secret, lease)
+}
+
+// SubscribeCalls gets all the calls that were made to Subscribe.
+// Check the length with:
+//
+//	len(mockedClient.SubscribeCalls())
+func (mock *ClientMock) SubscribeCalls() []struct {
+	Ctx      context.Context
+	Topic    string
+	Callback string
+	Secret   string
+	Lease    time.Duration
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Topic    string
+		Callback string
+		Secret   string
+		Lease    time.Duration
+	}
+	mock.lockSubscribe.RLock()
+	calls = mock.calls.Subscribe
+	mock.lockSubscribe.RUnlock()
+	return calls
+}
+
+// Unsubscribe calls UnsubscribeFunc.
+func (mock *ClientMock) Unsubscribe(ctx context.Context, topic string, callback string) error {
+	if mock.UnsubscribeFunc == nil {
+		panic("ClientMock.UnsubscribeFunc: method is nil but Client.Unsubscribe was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Topic    string
+		Callback string
+	}{
+		Ctx:      ctx,
+		Topic:    topic,
+		Callback: callback,
+	}
+	mock.lockUnsubscribe.Lock()
+	mock.calls.Unsubscribe = append(mock.calls.Unsubscribe, callInfo)
+	mock.lockUnsubscribe.Unlock()
+	return mock.UnsubscribeFunc(ctx, topic, callback)
+}
+
+// UnsubscribeCalls gets all the calls that were made to Unsubscribe.
+// Check the length with:
+//
+//	len(mockedClient.UnsubscribeCalls())
+func (mock *ClientMock) UnsubscribeCalls() []struct {
+	Ctx      context.Context
+	Topic    string
+	Callback string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Topic    string
+		Callback string
+	}
+	mock.lockUnsubscribe.RLock()
+	calls = mock.calls.Unsubscribe
+	mock.lockUnsubscribe.RUnlock()
+	return calls
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/TheEdgeOfRage/ytrssil-api/lib/clients/youtube"
 	"github.com/TheEdgeOfRage/ytrssil-api/models"
 	"sync"
+	"time"
 )
 
 // Ensure, that ClientMock does implement youtube.Client.
@@ -20,18 +21,39 @@ var _ youtube.Client = &ClientMock{}
 //
 //		// make and configure a mocked youtube.Client
 //		mockedClient := &ClientMock{
+//			ClassifyShortFunc: func(ctx context.Context, videoID string, feedHint bool, durationSeconds int) (bool, models.ShortSignal) {
+//				panic("mock out the ClassifyShort method")
+//			},
 //			GetChannelImageURLFunc: func(ctx context.Context, channelID string) (string, error) {
 //				panic("mock out the GetChannelImageURL method")
 //			},
+//			GetChannelsMetadataFunc: func(ctx context.Context, channelIDs []string) ([]models.ChannelMetadata, error) {
+//				panic("mock out the GetChannelsMetadata method")
+//			},
+//			GetPlaylistImageURLFunc: func(ctx context.Context, playlistID string) (string, error) {
+//				panic("mock out the GetPlaylistImageURL method")
+//			},
 //			GetVideoDurationsFunc: func(ctx context.Context, videos map[string]*models.Video) error {
 //				panic("mock out the GetVideoDurations method")
 //			},
 //			GetVideoMetadataFunc: func(ctx context.Context, videoID string) (*models.Video, error) {
 //				panic("mock out the GetVideoMetadata method")
 //			},
+//			ListUploadsFunc: func(ctx context.Context, channelID string, after time.Time, before time.Time) ([]models.Video, error) {
+//				panic("mock out the ListUploads method")
+//			},
+//			QuotaUsageFunc: func(ctx context.Context) models.QuotaUsage {
+//				panic("mock out the QuotaUsage method")
+//			},
 //			ResolveChannelIDFunc: func(ctx context.Context, handle string) (string, error) {
 //				panic("mock out the ResolveChannelID method")
 //			},
+//			ResolveUsernameFunc: func(ctx context.Context, username string) (string, error) {
+//				panic("mock out the ResolveUsername method")
+//			},
+//			SearchChannelsFunc: func(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+//				panic("mock out the SearchChannels method")
+//			},
 //		}
 //
 //		// use mockedClient in code that requires youtube.Client
@@ -39,20 +61,52 @@ var _ youtube.Client = &ClientMock{}
 //
 //	}
 type ClientMock struct {
+	// ClassifyShortFunc mocks the ClassifyShort method.
+	ClassifyShortFunc func(ctx context.Context, videoID string, feedHint bool, durationSeconds int) (bool, models.ShortSignal)
+
 	// GetChannelImageURLFunc mocks the GetChannelImageURL method.
 	GetChannelImageURLFunc func(ctx context.Context, channelID string) (string, error)
 
+	// GetChannelsMetadataFunc mocks the GetChannelsMetadata method.
+	GetChannelsMetadataFunc func(ctx context.Context, channelIDs []string) ([]models.ChannelMetadata, error)
+
+	// GetPlaylistImageURLFunc mocks the GetPlaylistImageURL method.
+	GetPlaylistImageURLFunc func(ctx context.Context, playlistID string) (string, error)
+
 	// GetVideoDurationsFunc mocks the GetVideoDurations method.
 	GetVideoDurationsFunc func(ctx context.Context, videos map[string]*models.Video) error
 
 	// GetVideoMetadataFunc mocks the GetVideoMetadata method.
 	GetVideoMetadataFunc func(ctx context.Context, videoID string) (*models.Video, error)
 
+	// ListUploadsFunc mocks the ListUploads method.
+	ListUploadsFunc func(ctx context.Context, channelID string, after time.Time, before time.Time) ([]models.Video, error)
+
+	// QuotaUsageFunc mocks the QuotaUsage method.
+	QuotaUsageFunc func(ctx context.Context) models.QuotaUsage
+
 	// ResolveChannelIDFunc mocks the ResolveChannelID method.
 	ResolveChannelIDFunc func(ctx context.Context, handle string) (string, error)
 
+	// ResolveUsernameFunc mocks the ResolveUsername method.
+	ResolveUsernameFunc func(ctx context.Context, username string) (string, error)
+
+	// SearchChannelsFunc mocks the SearchChannels method.
+	SearchChannelsFunc func(ctx context.Context, query string) ([]models.ChannelSearchResult, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// ClassifyShort holds details about calls to the ClassifyShort method.
+		ClassifyShort []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoID is the videoID argument value.
+			VideoID string
+			// FeedHint is the feedHint argument value.
+			FeedHint bool
+			// DurationSeconds is the durationSeconds argument value.
+			DurationSeconds int
+		}
 		// GetChannelImageURL holds details about calls to the GetChannelImageURL method.
 		GetChannelImageURL []struct {
 			// Ctx is the ctx argument value.
@@ -60,6 +114,20 @@ type ClientMock struct {
 			// ChannelID is the channelID argument value.
 			ChannelID string
 		}
+		// GetChannelsMetadata holds details about calls to the GetChannelsMetadata method.
+		GetChannelsMetadata []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelIDs is the channelIDs argument value.
+			ChannelIDs []string
+		}
+		// GetPlaylistImageURL holds details about calls to the GetPlaylistImageURL method.
+		GetPlaylistImageURL []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PlaylistID is the playlistID argument value.
+			PlaylistID string
+		}
 		// GetVideoDurations holds details about calls to the GetVideoDurations method.
 		GetVideoDurations []struct {
 			// Ctx is the ctx argument value.
@@ -74,6 +142,22 @@ type ClientMock struct {
 			// VideoID is the videoID argument value.
 			VideoID string
 		}
+		// ListUploads holds details about calls to the ListUploads method.
+		ListUploads []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// After is the after argument value.
+			After time.Time
+			// Before is the before argument value.
+			Before time.Time
+		}
+		// QuotaUsage holds details about calls to the QuotaUsage method.
+		QuotaUsage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// ResolveChannelID holds details about calls to the ResolveChannelID method.
 		ResolveChannelID []struct {
 			// Ctx is the ctx argument value.
@@ -81,11 +165,76 @@ type ClientMock struct {
 			// Handle is the handle argument value.
 			Handle string
 		}
+		// ResolveUsername holds details about calls to the ResolveUsername method.
+		ResolveUsername []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// SearchChannels holds details about calls to the SearchChannels method.
+		SearchChannels []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+		}
+	}
+	lockClassifyShort       sync.RWMutex
+	lockGetChannelImageURL  sync.RWMutex
+	lockGetChannelsMetadata sync.RWMutex
+	lockGetPlaylistImageURL sync.RWMutex
+	lockGetVideoDurations   sync.RWMutex
+	lockGetVideoMetadata    sync.RWMutex
+	lockListUploads         sync.RWMutex
+	lockQuotaUsage          sync.RWMutex
+	lockResolveChannelID    sync.RWMutex
+	lockResolveUsername     sync.RWMutex
+	lockSearchChannels      sync.RWMutex
+}
+
+// ClassifyShort calls ClassifyShortFunc.
+func (mock *ClientMock) ClassifyShort(ctx context.Context, videoID string, feedHint bool, durationSeconds int) (bool, models.ShortSignal) {
+	if mock.ClassifyShortFunc == nil {
+		panic("ClientMock.ClassifyShortFunc: method is nil but Client.ClassifyShort was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		VideoID         string
+		FeedHint        bool
+		DurationSeconds int
+	}{
+		Ctx:             ctx,
+		VideoID:         videoID,
+		FeedHint:        feedHint,
+		DurationSeconds: durationSeconds,
+	}
+	mock.lockClassifyShort.Lock()
+	mock.calls.ClassifyShort = append(mock.calls.ClassifyShort, callInfo)
+	mock.lockClassifyShort.Unlock()
+	return mock.ClassifyShortFunc(ctx, videoID, feedHint, durationSeconds)
+}
+
+// ClassifyShortCalls gets all the calls that were made to ClassifyShort.
+// Check the length with:
+//
+//	len(mockedClient.ClassifyShortCalls())
+func (mock *ClientMock) ClassifyShortCalls() []struct {
+	Ctx             context.Context
+	VideoID         string
+	FeedHint        bool
+	DurationSeconds int
+} {
+	var calls []struct {
+		Ctx             context.Context
+		VideoID         string
+		FeedHint        bool
+		DurationSeconds int
 	}
-	lockGetChannelImageURL sync.RWMutex
-	lockGetVideoDurations  sync.RWMutex
-	lockGetVideoMetadata   sync.RWMutex
-	lockResolveChannelID   sync.RWMutex
+	mock.lockClassifyShort.RLock()
+	calls = mock.calls.ClassifyShort
+	mock.lockClassifyShort.RUnlock()
+	return calls
 }
 
 // GetChannelImageURL calls GetChannelImageURLFunc.
@@ -124,6 +273,78 @@ func (mock *ClientMock) GetChannelImageURLCalls() []struct {
 	return calls
 }
 
+// GetChannelsMetadata calls GetChannelsMetadataFunc.
+func (mock *ClientMock) GetChannelsMetadata(ctx context.Context, channelIDs []string) ([]models.ChannelMetadata, error) {
+	if mock.GetChannelsMetadataFunc == nil {
+		panic("ClientMock.GetChannelsMetadataFunc: method is nil but Client.GetChannelsMetadata was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ChannelIDs []string
+	}{
+		Ctx:        ctx,
+		ChannelIDs: channelIDs,
+	}
+	mock.lockGetChannelsMetadata.Lock()
+	mock.calls.GetChannelsMetadata = append(mock.calls.GetChannelsMetadata, callInfo)
+	mock.lockGetChannelsMetadata.Unlock()
+	return mock.GetChannelsMetadataFunc(ctx, channelIDs)
+}
+
+// GetChannelsMetadataCalls gets all the calls that were made to GetChannelsMetadata.
+// Check the length with:
+//
+//	len(mockedClient.GetChannelsMetadataCalls())
+func (mock *ClientMock) GetChannelsMetadataCalls() []struct {
+	Ctx        context.Context
+	ChannelIDs []string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ChannelIDs []string
+	}
+	mock.lockGetChannelsMetadata.RLock()
+	calls = mock.calls.GetChannelsMetadata
+	mock.lockGetChannelsMetadata.RUnlock()
+	return calls
+}
+
+// GetPlaylistImageURL calls GetPlaylistImageURLFunc.
+func (mock *ClientMock) GetPlaylistImageURL(ctx context.Context, playlistID string) (string, error) {
+	if mock.GetPlaylistImageURLFunc == nil {
+		panic("ClientMock.GetPlaylistImageURLFunc: method is nil but Client.GetPlaylistImageURL was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		PlaylistID string
+	}{
+		Ctx:        ctx,
+		PlaylistID: playlistID,
+	}
+	mock.lockGetPlaylistImageURL.Lock()
+	mock.calls.GetPlaylistImageURL = append(mock.calls.GetPlaylistImageURL, callInfo)
+	mock.lockGetPlaylistImageURL.Unlock()
+	return mock.GetPlaylistImageURLFunc(ctx, playlistID)
+}
+
+// GetPlaylistImageURLCalls gets all the calls that were made to GetPlaylistImageURL.
+// Check the length with:
+//
+//	len(mockedClient.GetPlaylistImageURLCalls())
+func (mock *ClientMock) GetPlaylistImageURLCalls() []struct {
+	Ctx        context.Context
+	PlaylistID string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		PlaylistID string
+	}
+	mock.lockGetPlaylistImageURL.RLock()
+	calls = mock.calls.GetPlaylistImageURL
+	mock.lockGetPlaylistImageURL.RUnlock()
+	return calls
+}
+
 // GetVideoDurations calls GetVideoDurationsFunc.
 func (mock *ClientMock) GetVideoDurations(ctx context.Context, videos map[string]*models.Video) error {
 	if mock.GetVideoDurationsFunc == nil {
@@ -196,6 +417,82 @@ func (mock *ClientMock) GetVideoMetadataCalls() []struct {
 	return calls
 }
 
+// ListUploads calls ListUploadsFunc.
+func (mock *ClientMock) ListUploads(ctx context.Context, channelID string, after time.Time, before time.Time) ([]models.Video, error) {
+	if mock.ListUploadsFunc == nil {
+		panic("ClientMock.ListUploadsFunc: method is nil but Client.ListUploads was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		After     time.Time
+		Before    time.Time
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		After:     after,
+		Before:    before,
+	}
+	mock.lockListUploads.Lock()
+	mock.calls.ListUploads = append(mock.calls.ListUploads, callInfo)
+	mock.lockListUploads.Unlock()
+	return mock.ListUploadsFunc(ctx, channelID, after, before)
+}
+
+// ListUploadsCalls gets all the calls that were made to ListUploads.
+// Check the length with:
+//
+//	len(mockedClient.ListUploadsCalls())
+func (mock *ClientMock) ListUploadsCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	After     time.Time
+	Before    time.Time
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		After     time.Time
+		Before    time.Time
+	}
+	mock.lockListUploads.RLock()
+	calls = mock.calls.ListUploads
+	mock.lockListUploads.RUnlock()
+	return calls
+}
+
+// QuotaUsage calls QuotaUsageFunc.
+func (mock *ClientMock) QuotaUsage(ctx context.Context) models.QuotaUsage {
+	if mock.QuotaUsageFunc == nil {
+		panic("ClientMock.QuotaUsageFunc: method is nil but Client.QuotaUsage was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockQuotaUsage.Lock()
+	mock.calls.QuotaUsage = append(mock.calls.QuotaUsage, callInfo)
+	mock.lockQuotaUsage.Unlock()
+	return mock.QuotaUsageFunc(ctx)
+}
+
+// QuotaUsageCalls gets all the calls that were made to QuotaUsage.
+// Check the length with:
+//
+//	len(mockedClient.QuotaUsageCalls())
+func (mock *ClientMock) QuotaUsageCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockQuotaUsage.RLock()
+	calls = mock.calls.QuotaUsage
+	mock.lockQuotaUsage.RUnlock()
+	return calls
+}
+
 // ResolveChannelID calls ResolveChannelIDFunc.
 func (mock *ClientMock) ResolveChannelID(ctx context.Context, handle string) (string, error) {
 	if mock.ResolveChannelIDFunc == nil {
@@ -231,3 +528,75 @@ func (mock *ClientMock) ResolveChannelIDCalls() []struct {
 	mock.lockResolveChannelID.RUnlock()
 	return calls
 }
+
+// ResolveUsername calls ResolveUsernameFunc.
+func (mock *ClientMock) ResolveUsername(ctx context.Context, username string) (string, error) {
+	if mock.ResolveUsernameFunc == nil {
+		panic("ClientMock.ResolveUsernameFunc: method is nil but Client.ResolveUsername was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockResolveUsername.Lock()
+	mock.calls.ResolveUsername = append(mock.calls.ResolveUsername, callInfo)
+	mock.lockResolveUsername.Unlock()
+	return mock.ResolveUsernameFunc(ctx, username)
+}
+
+// ResolveUsernameCalls gets all the calls that were made to ResolveUsername.
+// Check the length with:
+//
+//	len(mockedClient.ResolveUsernameCalls())
+func (mock *ClientMock) ResolveUsernameCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockResolveUsername.RLock()
+	calls = mock.calls.ResolveUsername
+	mock.lockResolveUsername.RUnlock()
+	return calls
+}
+
+// SearchChannels calls SearchChannelsFunc.
+func (mock *ClientMock) SearchChannels(ctx context.Context, query string) ([]models.ChannelSearchResult, error) {
+	if mock.SearchChannelsFunc == nil {
+		panic("ClientMock.SearchChannelsFunc: method is nil but Client.SearchChannels was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockSearchChannels.Lock()
+	mock.calls.SearchChannels = append(mock.calls.SearchChannels, callInfo)
+	mock.lockSearchChannels.Unlock()
+	return mock.SearchChannelsFunc(ctx, query)
+}
+
+// SearchChannelsCalls gets all the calls that were made to SearchChannels.
+// Check the length with:
+//
+//	len(mockedClient.SearchChannelsCalls())
+func (mock *ClientMock) SearchChannelsCalls() []struct {
+	Ctx   context.Context
+	Query string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+	}
+	mock.lockSearchChannels.RLock()
+	calls = mock.calls.SearchChannels
+	mock.lockSearchChannels.RUnlock()
+	return calls
+}
@@ -1,10 +1,39 @@
 package models
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Source types of channels
+const (
+	SourceYouTube         = "youtube"
+	SourceYouTubePlaylist = "youtube-playlist"
+	SourcePeerTube        = "peertube"
+	SourceGenericRSS      = "generic-rss"
+)
+
 type Channel struct {
-	// YouTube ID of the channel
+	// YouTube ID of the channel or playlist, or an ID derived from the feed URL for other sources
 	ID string `json:"channel_id"`
+	// SourceType is the kind of site the channel's feed comes from
+	SourceType string `json:"source_type"`
+	// FeedURL is the URL of the channel's feed for sources other than YouTube
+	FeedURL string `json:"feed_url,omitempty"`
+	// SiteURL is the URL of the channel's page for sources other than YouTube
+	SiteURL string `json:"site_url,omitempty"`
 	// Name of the channel
 	Name string `json:"name"`
+	// FormerName is the name the channel had before it was last renamed, if it was
+	FormerName string `json:"former_name,omitempty"`
+	// Handle is the @handle of a YouTube channel
+	Handle string `json:"handle,omitempty"`
+	// Description is the about text of a YouTube channel
+	Description string `json:"description,omitempty"`
+	// BannerURL is the URL of a YouTube channel's banner image
+	BannerURL string `json:"banner_url,omitempty"`
 	// Subscribed indicates if the user is subscribed to this channel
 	Subscribed bool `json:"subscribed"`
 	// UnwatchedCount is the number of unwatched videos from this channel
@@ -13,4 +42,121 @@ type Channel struct {
 	ImageURL string `json:"image_url"`
 	// EnableShorts indicates if shorts should be shown for this channel
 	EnableShorts bool `json:"enable_shorts"`
+	// TitleInclude are patterns of which a title must match one for new videos to be kept, if there are any
+	TitleInclude []string `json:"title_include"`
+	// TitleExclude are patterns that discard new videos whose titles match any of them
+	TitleExclude []string `json:"title_exclude"`
+	// FeedETag is the ETag returned with the last successfully processed feed
+	FeedETag string `json:"-"`
+	// FeedLastModified is the Last-Modified header returned with the last successfully processed feed
+	FeedLastModified string `json:"-"`
+	// WebSubLeaseExpiresAt is when the hub stops pushing updates for this channel unless renewed
+	WebSubLeaseExpiresAt *time.Time `json:"websub_lease_expires_at"`
+	// WebSubRequestedAt is when a WebSub subscription was requested that the hub hasn't verified yet
+	WebSubRequestedAt *time.Time `json:"-"`
+	// Health describes how fetching the channel's feed has been going
+	Health FetchHealth `json:"health"`
+}
+
+// ChannelMetadata is the profile of a YouTube channel as its API returns it
+type ChannelMetadata struct {
+	ChannelID   string
+	Name        string
+	Handle      string
+	Description string
+	AvatarURL   string
+	BannerURL   string
+}
+
+// FetchHealth tracks the outcome of recent fetches of a channel's feed
+type FetchHealth struct {
+	// LastSuccessAt is when the feed was last fetched successfully
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	// LastError is the error of the last failed fetch, kept after the channel recovers
+	LastError string `json:"last_error,omitempty"`
+	// LastErrorAt is when the last failed fetch happened
+	LastErrorAt *time.Time `json:"last_error_at"`
+	// ConsecutiveFailures is the number of fetches in a row that failed
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// LastNewVideoAt is when a new video was last found in the feed
+	LastNewVideoAt *time.Time `json:"last_new_video_at"`
+	// GoneAt is when the channel was flagged as gone after its feed repeatedly returned 404
+	GoneAt *time.Time `json:"gone_at"`
+}
+
+// FetchOutcome is the result of a single fetch of a channel's feed
+type FetchOutcome struct {
+	// Error is the error message if the fetch failed, empty on success
+	Error string
+	// NotFound indicates that the feed returned 404
+	NotFound bool
+	// NewVideos is the number of videos added by the fetch
+	NewVideos int
+}
+
+// IsGone reports whether the channel was flagged as gone
+func (h FetchHealth) IsGone() bool {
+	return h.GoneAt != nil
+}
+
+// IsFailing reports whether the last fetch of the channel failed
+func (h FetchHealth) IsFailing() bool {
+	return h.ConsecutiveFailures > 0
+}
+
+// HumanizedLastSuccess returns the time since the last successful fetch in a human readable format
+func (h FetchHealth) HumanizedLastSuccess() string {
+	if h.LastSuccessAt == nil {
+		return "never"
+	}
+	return humanize.Time(*h.LastSuccessAt)
+}
+
+// HumanizedLastNewVideo returns the time since a new video was last found in a human readable format
+func (h FetchHealth) HumanizedLastNewVideo() string {
+	if h.LastNewVideoAt == nil {
+		return "never"
+	}
+	return humanize.Time(*h.LastNewVideoAt)
+}
+
+// IsYouTube reports whether the channel is a YouTube channel or playlist
+func (c Channel) IsYouTube() bool {
+	return c.SourceType == "" || c.SourceType == SourceYouTube || c.SourceType == SourceYouTubePlaylist
+}
+
+// IsPlaylist reports whether the subscription is a YouTube playlist rather than a channel
+func (c Channel) IsPlaylist() bool {
+	return c.SourceType == SourceYouTubePlaylist
+}
+
+// URL returns the URL of the channel's page on its original site
+func (c Channel) URL() string {
+	if c.IsPlaylist() {
+		return fmt.Sprintf("https://www.youtube.com/playlist?list=%s", c.ID)
+	}
+	if c.IsYouTube() {
+		return fmt.Sprintf("https://www.youtube.com/channel/%s", c.ID)
+	}
+	if c.SiteURL != "" {
+		return c.SiteURL
+	}
+	return c.FeedURL
+}
+
+// ChannelPollState holds everything the scheduler needs to decide when a channel should be polled next
+type ChannelPollState struct {
+	Channel
+	// LastPublished is the publish time of the newest known video of the channel
+	LastPublished *time.Time
+	// AverageUploadInterval is the mean time between the channel's recent uploads, 0 if unknown
+	AverageUploadInterval time.Duration
+	// ConsecutiveFailures is the number of polls in a row that failed for this channel
+	ConsecutiveFailures int
+	// MinPollInterval overrides the global minimum poll interval for this channel if non-zero
+	MinPollInterval time.Duration
+	// MaxPollInterval overrides the global maximum poll interval for this channel if non-zero
+	MaxPollInterval time.Duration
+	// HasWebSubLease indicates that new videos are pushed by a WebSub hub, so polling is only a fallback
+	HasWebSubLease bool
 }
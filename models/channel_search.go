@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+)
+
+// ChannelSearchResult is a YouTube channel found by searching for its name
+type ChannelSearchResult struct {
+	// ID is the YouTube ID of the channel
+	ID string `json:"channel_id"`
+	// Name of the channel
+	Name string `json:"name"`
+	// Handle is the @handle of the channel, if it has one
+	Handle string `json:"handle,omitempty"`
+	// Description is the about text of the channel
+	Description string `json:"description,omitempty"`
+	// ImageURL is the URL of the channel's profile image
+	ImageURL string `json:"image_url"`
+	// SubscriberCount is the number of subscribers, rounded by YouTube to three significant digits
+	SubscriberCount int64 `json:"subscriber_count"`
+	// SubscribersHidden indicates that the channel doesn't show its subscriber count
+	SubscribersHidden bool `json:"subscribers_hidden"`
+	// Subscribed indicates that the user is already subscribed to the channel
+	Subscribed bool `json:"subscribed"`
+}
+
+// HumanizedSubscribers returns the subscriber count the way YouTube shows it, like 1.2K or 3.45M
+func (r ChannelSearchResult) HumanizedSubscribers() string {
+	if r.SubscribersHidden {
+		return "hidden"
+	}
+
+	count := float64(r.SubscriberCount)
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "K"}} {
+		if count >= unit.size {
+			value := count / unit.size
+			switch {
+			case value >= 100:
+				return fmt.Sprintf("%.0f%s", value, unit.suffix)
+			case value >= 10:
+				return trimZeros(fmt.Sprintf("%.1f", value)) + unit.suffix
+			default:
+				return trimZeros(fmt.Sprintf("%.2f", value)) + unit.suffix
+			}
+		}
+	}
+
+	return fmt.Sprint(r.SubscriberCount)
+}
+
+// trimZeros drops trailing zeros after the decimal point, and the point itself if nothing is left after it
+func trimZeros(number string) string {
+	for number[len(number)-1] == '0' {
+		number = number[:len(number)-1]
+	}
+	if number[len(number)-1] == '.' {
+		number = number[:len(number)-1]
+	}
+	return number
+}
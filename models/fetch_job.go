@@ -0,0 +1,65 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// FetchJob tracks a fetch of the feeds of all channels or a back catalog import running in the background
+type FetchJob struct {
+	// ID identifies the job
+	ID string `json:"job_id"`
+	// StartedAt is when the job was started
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the job finished, nil while it's running
+	FinishedAt *time.Time `json:"finished_at"`
+	// Channels is the number of channels to fetch, 0 until they're listed
+	Channels int `json:"channels"`
+	// ChannelsDone is the number of channels fetched so far, whether that succeeded or not
+	ChannelsDone int `json:"channels_done"`
+	// NewVideos is the number of new videos found so far
+	NewVideos int `json:"new_videos"`
+	// Errors lists the channels that failed to fetch
+	Errors []FetchJobError `json:"errors"`
+	// Error is set when the job couldn't run at all
+	Error string `json:"error,omitempty"`
+	// Import is the progress of a back catalog import, nil for fetches of all channels
+	Import *ImportReport `json:"import,omitempty"`
+}
+
+// FetchJobError is the failure of a single channel during a fetch job
+type FetchJobError struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	Error       string `json:"error"`
+}
+
+// IsFinished reports whether the job is done
+func (j FetchJob) IsFinished() bool {
+	return j.FinishedAt != nil
+}
+
+// ProgressPercentage returns the share of channels fetched so far as an integer from 0-100
+func (j FetchJob) ProgressPercentage() int {
+	if j.IsFinished() {
+		return 100
+	}
+	if j.Channels == 0 {
+		return 0
+	}
+	return 100 * j.ChannelsDone / j.Channels
+}
+
+// Snapshot returns a copy of the job that doesn't share any state with it
+func (j FetchJob) Snapshot() FetchJob {
+	j.Errors = slices.Clone(j.Errors)
+	if j.Import != nil {
+		report := *j.Import
+		j.Import = &report
+	}
+	if j.FinishedAt != nil {
+		finishedAt := *j.FinishedAt
+		j.FinishedAt = &finishedAt
+	}
+	return j
+}
@@ -0,0 +1,15 @@
+package models
+
+// ImportReport summarizes a back-catalog import of a channel
+type ImportReport struct {
+	// ChannelID is the ID of the imported channel
+	ChannelID string `json:"channel_id"`
+	// Found is the number of uploads listed in the requested date range
+	Found int `json:"found"`
+	// Imported is the number of videos that were added
+	Imported int `json:"imported"`
+	// Skipped is the number of uploads that were already known
+	Skipped int `json:"skipped"`
+	// MarkedWatched indicates whether the imported videos were marked as watched
+	MarkedWatched bool `json:"marked_watched"`
+}
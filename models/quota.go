@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// QuotaUsage is how much of the YouTube Data API quota was spent on a day. Days start at midnight Pacific time,
+// when YouTube resets the quota.
+type QuotaUsage struct {
+	// Day is the Pacific date the usage is for, as YYYY-MM-DD
+	Day string `json:"day"`
+	// Budget is the number of units that may be spent per day, 0 for no limit
+	Budget int `json:"budget"`
+	// Used is the number of units spent so far
+	Used int `json:"used"`
+	// Exhausted indicates that no more calls are made until the reset, because the budget was reached or the
+	// API reported the quota as exceeded
+	Exhausted bool `json:"exhausted"`
+	// ResetsAt is when the next day starts
+	ResetsAt time.Time `json:"resets_at"`
+	// Calls breaks the usage down by API method
+	Calls []QuotaCallUsage `json:"calls"`
+}
+
+// QuotaCallUsage is the quota spent on one API method
+type QuotaCallUsage struct {
+	// Call is the API method, like videos.list
+	Call string `json:"call"`
+	// Requests is the number of requests sent
+	Requests int `json:"requests"`
+	// Units is the quota spent by those requests
+	Units int `json:"units"`
+}
+
+// UsedPercentage returns the share of the budget used so far as an integer from 0-100, 0 without a budget
+func (u QuotaUsage) UsedPercentage() int {
+	if u.Exhausted {
+		return 100
+	}
+	if u.Budget <= 0 {
+		return 0
+	}
+	return min(100, 100*u.Used/u.Budget)
+}
+
+// HumanizedReset returns the time until the quota resets in a human readable format
+func (u QuotaUsage) HumanizedReset() string {
+	return humanize.Time(u.ResetsAt)
+}
@@ -7,6 +7,98 @@ import (
 	"github.com/dustin/go-humanize"
 )
 
+// DiscardReason records why a video was taken out of the feed without being watched
+type DiscardReason string
+
+const (
+	// NotDiscarded is the reason of videos that are still in the feed
+	NotDiscarded DiscardReason = ""
+	// DiscardShorts is for shorts of channels that have shorts disabled
+	DiscardShorts DiscardReason = "shorts"
+	// DiscardTitleFilter is for videos that didn't pass the title filters of their channel
+	DiscardTitleFilter DiscardReason = "title_filter"
+	// DiscardDismissed is for videos dismissed by hand
+	DiscardDismissed DiscardReason = "dismissed"
+	// DiscardExpired is for videos that stayed unwatched for longer than the configured expiry
+	DiscardExpired DiscardReason = "expired"
+	// DiscardUnavailable is for videos that can no longer be watched on YouTube
+	DiscardUnavailable DiscardReason = "unavailable"
+)
+
+// Label returns a human readable description of the reason
+func (r DiscardReason) Label() string {
+	switch r {
+	case DiscardShorts:
+		return "Shorts filter"
+	case DiscardTitleFilter:
+		return "Title filter"
+	case DiscardDismissed:
+		return "Dismissed"
+	case DiscardExpired:
+		return "Expired"
+	case DiscardUnavailable:
+		return "Unavailable"
+	default:
+		return string(r)
+	}
+}
+
+// ShortSignal names what decided whether a video is a short
+type ShortSignal string
+
+const (
+	// ShortSignalFeed is for videos linked through their shorts page in the feed
+	ShortSignalFeed ShortSignal = "feed"
+	// ShortSignalProbe is for videos classified by asking YouTube whether their shorts page plays them
+	ShortSignalProbe ShortSignal = "probe"
+	// ShortSignalDuration is for videos classified by their duration when the other signals weren't available
+	ShortSignalDuration ShortSignal = "duration"
+	// ShortSignalOverride is for videos classified by hand
+	ShortSignalOverride ShortSignal = "override"
+)
+
+// UnavailableReason records why a video can no longer be watched on YouTube
+type UnavailableReason string
+
+const (
+	// Available is the reason of videos that can be watched
+	Available UnavailableReason = ""
+	// UnavailableRemoved is for videos that were deleted or made private, the API doesn't tell them apart
+	UnavailableRemoved UnavailableReason = "removed"
+	// UnavailableRegionBlocked is for videos that can't be watched in the configured region
+	UnavailableRegionBlocked UnavailableReason = "region_blocked"
+)
+
+// Label returns a human readable description of the reason
+func (r UnavailableReason) Label() string {
+	switch r {
+	case UnavailableRemoved:
+		return "Deleted or private"
+	case UnavailableRegionBlocked:
+		return "Blocked in your region"
+	default:
+		return string(r)
+	}
+}
+
+// VideoSort is the order in which lists of videos are returned
+type VideoSort string
+
+const (
+	// SortPublished orders videos by when they were published
+	SortPublished VideoSort = ""
+	// SortViews orders the most viewed videos first, videos with unknown view counts last
+	SortViews VideoSort = "views"
+)
+
+// ParseVideoSort returns the sort order with the given name, falling back to publish order for unknown names
+func ParseVideoSort(name string) VideoSort {
+	if VideoSort(name) == SortViews {
+		return SortViews
+	}
+	return SortPublished
+}
+
 type Video struct {
 	// YouTube ID of the video
 	ID string `json:"video_id"`
@@ -14,8 +106,24 @@ type Video struct {
 	ChannelName string `json:"channel_name"`
 	// ID of the channel the video belongs to
 	ChannelID string `json:"-"`
+	// ChannelSiteURL is the URL of the channel's page (or feed) for sources other than YouTube
+	ChannelSiteURL string `json:"-"`
 	// Title of the video
 	Title string `json:"title"`
+	// OriginalTitle is the title the video was published with, if it was changed since
+	OriginalTitle string `json:"original_title,omitempty"`
+	// URL is the link to the video on its original site for sources other than YouTube
+	URL string `json:"url,omitempty"`
+	// Thumbnail is the URL of the video's thumbnail image, if known
+	Thumbnail string `json:"thumbnail_url,omitempty"`
+	// Description of the video as given by its feed
+	Description string `json:"description,omitempty"`
+	// Views is the view count as of the last fetch of the feed, 0 if unknown
+	Views int64 `json:"views"`
+	// RatingAverage is the average star rating from 1 to 5 as of the last fetch of the feed, 0 if unknown
+	RatingAverage float64 `json:"rating_average"`
+	// RatingCount is the number of ratings behind RatingAverage
+	RatingCount int64 `json:"rating_count"`
 	// Video publish timestamp
 	PublishedTime time.Time `json:"published_timestamp"`
 	// Video watch timestamp
@@ -26,11 +134,22 @@ type Video struct {
 	ProgressSeconds int `json:"progress"`
 	// IsShort indicates if a video is a YouTube short
 	IsShort bool `json:"short"`
+	// ShortSignal records what decided whether the video is a short
+	ShortSignal ShortSignal `json:"short_signal,omitempty"`
 	// IsLive indicates if the video is a currently active live stream
 	IsLive bool `json:"is_live"`
+	// IsUpcoming indicates if the video is a premiere or live stream that hasn't started yet
+	IsUpcoming bool `json:"is_upcoming"`
+	// ScheduledStartTime is when an upcoming premiere or live stream is scheduled to start
+	ScheduledStartTime *time.Time `json:"scheduled_start_time"`
 	// IsDiscarded indicates if the video was discarded from the feed without watching
-	// This happens to shorts videos for channels that have shorts disabled
 	IsDiscarded bool `json:"is_discarded"`
+	// DiscardReason records why the video was discarded
+	DiscardReason DiscardReason `json:"discard_reason,omitempty"`
+	// UnavailableReason is set when the video can no longer be watched on YouTube
+	UnavailableReason UnavailableReason `json:"unavailable_reason,omitempty"`
+	// DurationPending indicates that the duration wasn't looked up yet, because the YouTube API couldn't be used
+	DurationPending bool `json:"-"`
 	// DownloadedAt is the timestamp when the video was downloaded to the server
 	DownloadedAt *time.Time `json:"downloaded_at"`
 	// FilePath is the path to the downloaded video file on the server
@@ -41,16 +160,59 @@ type Video struct {
 	DownloadError *string `json:"download_error"`
 }
 
+// IsUnavailable reports whether the video can no longer be watched on YouTube
+func (v Video) IsUnavailable() bool {
+	return v.UnavailableReason != Available
+}
+
+// IsPreserved reports whether an unavailable video is kept around because it was downloaded
+func (v Video) IsPreserved() bool {
+	return v.IsUnavailable() && v.IsDownloaded()
+}
+
 // ProgressPercentage returns the current progress of the video as an integer from 0-100
 func (v Video) ProgressPercentage() int {
 	return int(100 * float64(v.ProgressSeconds) / float64(v.DurationSeconds))
 }
 
-// WatchURL returns the formatted YouTube watch URL including the timestamp pointing to  the current progress
+// IsYouTube reports whether the video is hosted on YouTube
+func (v Video) IsYouTube() bool {
+	return v.URL == ""
+}
+
+// WatchURL returns the formatted YouTube watch URL including the timestamp pointing to  the current progress,
+// or the link to the original site for other sources
 func (v Video) WatchURL() string {
+	if !v.IsYouTube() {
+		return v.URL
+	}
 	return fmt.Sprintf("https://youtube.com/watch?v=%s&t=%d", v.ID, v.ProgressSeconds)
 }
 
+// SourceURL returns the URL of the video on its original site, suitable for downloading with yt-dlp
+func (v Video) SourceURL() string {
+	if !v.IsYouTube() {
+		return v.URL
+	}
+	return fmt.Sprintf("https://www.youtube.com/watch?v=%s", v.ID)
+}
+
+// ThumbnailURL returns the URL of the video's thumbnail image, which may be empty for sources other than YouTube
+func (v Video) ThumbnailURL() string {
+	if v.Thumbnail != "" || !v.IsYouTube() {
+		return v.Thumbnail
+	}
+	return fmt.Sprintf("https://img.youtube.com/vi/%s/0.jpg", v.ID)
+}
+
+// ChannelURL returns the URL of the page of the channel the video belongs to
+func (v Video) ChannelURL() string {
+	if !v.IsYouTube() {
+		return v.ChannelSiteURL
+	}
+	return fmt.Sprintf("https://www.youtube.com/channel/%s", v.ChannelID)
+}
+
 // Duration returns the total duration of the video in the hh:mm:ss format
 func (v Video) Duration() string {
 	duration := time.Duration(v.DurationSeconds) * time.Second
@@ -71,6 +233,19 @@ func (v Video) HumanizedPublishTime() string {
 	return humanize.Time(v.PublishedTime)
 }
 
+// HumanizedScheduledStart returns the time until an upcoming video starts in a human readable format
+func (v Video) HumanizedScheduledStart() string {
+	if v.ScheduledStartTime == nil {
+		return "soon"
+	}
+	return humanize.Time(*v.ScheduledStartTime)
+}
+
+// HumanizedViews returns the view count with thousands separators
+func (v Video) HumanizedViews() string {
+	return humanize.Comma(v.Views)
+}
+
 func (v Video) IsDownloaded() bool {
 	return v.DownloadStatus != nil && *v.DownloadStatus == "completed"
 }
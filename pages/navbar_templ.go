@@ -20,15 +20,21 @@ func navButtons(route string) []*NavButton {
 	newVideos := &NavButton{"/", "New Videos", "collection-play"}
 	watched := &NavButton{"/watched", "Watched", "check-circle"}
 	channels := &NavButton{"/channels", "Channels", "people"}
+	discarded := &NavButton{"/discarded", "Discarded", "eye-slash"}
+	status := &NavButton{"/status", "Status", "speedometer2"}
 	switch route {
 	case "channels":
-		return []*NavButton{newVideos, watched}
+		return []*NavButton{newVideos, watched, discarded, status}
 	case "new":
-		return []*NavButton{channels, watched}
+		return []*NavButton{channels, watched, discarded, status}
 	case "watched":
-		return []*NavButton{channels, newVideos}
+		return []*NavButton{channels, newVideos, discarded, status}
+	case "discarded":
+		return []*NavButton{channels, newVideos, watched, status}
+	case "status":
+		return []*NavButton{channels, newVideos, watched, discarded}
 	default:
-		return []*NavButton{channels, newVideos, watched}
+		return []*NavButton{channels, newVideos, watched, discarded, status}
 	}
 }
 
@@ -70,7 +76,7 @@ func navbar(route string) templ.Component {
 				var templ_7745c5c3_Var2 templ.SafeURL
 				templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinURLErrs(button.link)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `pages/navbar.templ`, Line: 76, Col: 28}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `pages/navbar.templ`, Line: 82, Col: 28}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 				if templ_7745c5c3_Err != nil {
@@ -83,7 +89,7 @@ func navbar(route string) templ.Component {
 				var templ_7745c5c3_Var3 string
 				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(button.text)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `pages/navbar.templ`, Line: 77, Col: 54}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `pages/navbar.templ`, Line: 83, Col: 54}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 				if templ_7745c5c3_Err != nil {
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+const (
+	// pollsPerUpload is how many times a channel is polled within its average upload interval
+	pollsPerUpload = 4
+	// dormancyFactor is how many average upload intervals may pass without an upload before a channel is
+	// considered dormant and polled less often
+	dormancyFactor = 3
+	// maxBackoffExponent caps the exponential backoff applied after consecutive failures
+	maxBackoffExponent = 6
+	// jitterFraction is the maximum relative deviation applied to every computed interval
+	jitterFraction = 0.1
+)
+
+// Bounds are the global limits for poll intervals, which channels can override individually
+type Bounds struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// forChannel returns the bounds with the channel's overrides applied
+func (b Bounds) forChannel(state models.ChannelPollState) Bounds {
+	if state.MinPollInterval > 0 {
+		b.Min = state.MinPollInterval
+	}
+	if state.MaxPollInterval > 0 {
+		b.Max = state.MaxPollInterval
+	}
+	if b.Max < b.Min {
+		b.Max = b.Min
+	}
+
+	return b
+}
+
+// Interval returns how long to wait before polling the channel again, without jitter
+func Interval(state models.ChannelPollState, bounds Bounds, now time.Time) time.Duration {
+	bounds = bounds.forChannel(state)
+	if state.HasWebSubLease {
+		// New videos are pushed by the hub, polling only catches anything it missed
+		return bounds.Max
+	}
+	if state.Health.IsGone() {
+		// Keep checking occasionally in case the channel comes back
+		return bounds.Max
+	}
+
+	// Channels without enough history to estimate an upload rate are polled at the midpoint
+	interval := bounds.Min + (bounds.Max-bounds.Min)/2
+	if state.AverageUploadInterval > 0 {
+		interval = state.AverageUploadInterval / pollsPerUpload
+		if state.LastPublished != nil {
+			sinceLast := now.Sub(*state.LastPublished)
+			if sinceLast > dormancyFactor*state.AverageUploadInterval {
+				interval = sinceLast / pollsPerUpload
+			}
+		}
+	}
+
+	if state.ConsecutiveFailures > 0 {
+		exponent := min(state.ConsecutiveFailures, maxBackoffExponent)
+		interval = time.Duration(float64(interval) * math.Pow(2, float64(exponent)))
+	}
+
+	return min(max(interval, bounds.Min), bounds.Max)
+}
+
+// NextPoll returns the time at which the channel should be polled next. A small random jitter is added so
+// that channels with similar upload patterns drift apart instead of being polled in bursts.
+func NextPoll(state models.ChannelPollState, bounds Bounds, now time.Time) time.Time {
+	interval := Interval(state, bounds, now)
+	jitter := time.Duration((rand.Float64()*2 - 1) * jitterFraction * float64(interval))
+
+	return now.Add(interval + jitter)
+}
+
+// Stagger returns the delay before starting the i-th of n polls so that they are spread evenly over the
+// given window instead of all starting at once
+func Stagger(i int, n int, window time.Duration) time.Duration {
+	if n <= 1 {
+		return 0
+	}
+
+	return time.Duration(i) * (window / time.Duration(n))
+}
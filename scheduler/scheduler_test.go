@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TheEdgeOfRage/ytrssil-api/models"
+)
+
+func TestInterval(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	bounds := Bounds{Min: 5 * time.Minute, Max: 12 * time.Hour}
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		state    models.ChannelPollState
+		expected time.Duration
+	}{
+		{
+			name:     "no history",
+			state:    models.ChannelPollState{},
+			expected: 5*time.Minute + (12*time.Hour-5*time.Minute)/2,
+		},
+		{
+			name: "daily uploader",
+			state: models.ChannelPollState{
+				AverageUploadInterval: 24 * time.Hour,
+				LastPublished:         ago(12 * time.Hour),
+			},
+			expected: 6 * time.Hour,
+		},
+		{
+			name: "hourly uploader is clamped to min",
+			state: models.ChannelPollState{
+				AverageUploadInterval: time.Hour,
+				LastPublished:         ago(30 * time.Minute),
+			},
+			expected: 15 * time.Minute,
+		},
+		{
+			name: "very active uploader",
+			state: models.ChannelPollState{
+				AverageUploadInterval: 10 * time.Minute,
+				LastPublished:         ago(time.Minute),
+			},
+			expected: 5 * time.Minute,
+		},
+		{
+			name: "dormant channel is clamped to max",
+			state: models.ChannelPollState{
+				AverageUploadInterval: 24 * time.Hour,
+				LastPublished:         ago(365 * 24 * time.Hour),
+			},
+			expected: 12 * time.Hour,
+		},
+		{
+			name: "failures back off",
+			state: models.ChannelPollState{
+				AverageUploadInterval: 4 * time.Hour,
+				LastPublished:         ago(time.Hour),
+				ConsecutiveFailures:   2,
+			},
+			expected: 4 * time.Hour,
+		},
+		{
+			name: "channel overrides",
+			state: models.ChannelPollState{
+				AverageUploadInterval: 24 * time.Hour,
+				LastPublished:         ago(time.Hour),
+				MaxPollInterval:       time.Hour,
+			},
+			expected: time.Hour,
+		},
+		{
+			name: "channel with WebSub lease falls back to max",
+			state: models.ChannelPollState{
+				AverageUploadInterval: time.Hour,
+				LastPublished:         ago(time.Minute),
+				HasWebSubLease:        true,
+			},
+			expected: 12 * time.Hour,
+		},
+		{
+			name: "gone channel falls back to max",
+			state: models.ChannelPollState{
+				Channel: models.Channel{
+					Health: models.FetchHealth{GoneAt: ago(time.Hour)},
+				},
+				AverageUploadInterval: time.Hour,
+				LastPublished:         ago(time.Minute),
+			},
+			expected: 12 * time.Hour,
+		},
+		{
+			name: "min override above global max",
+			state: models.ChannelPollState{
+				MinPollInterval: 24 * time.Hour,
+			},
+			expected: 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Interval(tt.state, bounds, now))
+		})
+	}
+}
+
+func TestNextPollJitter(t *testing.T) {
+	now := time.Now()
+	bounds := Bounds{Min: time.Hour, Max: time.Hour}
+	for range 100 {
+		next := NextPoll(models.ChannelPollState{}, bounds, now)
+		assert.WithinRange(t, next, now.Add(54*time.Minute), now.Add(66*time.Minute))
+	}
+}
+
+func TestStagger(t *testing.T) {
+	assert.Equal(t, time.Duration(0), Stagger(0, 1, time.Minute))
+	assert.Equal(t, time.Duration(0), Stagger(0, 4, time.Minute))
+	assert.Equal(t, 30*time.Second, Stagger(2, 4, time.Minute))
+	assert.Equal(t, 45*time.Second, Stagger(3, 4, time.Minute))
+}
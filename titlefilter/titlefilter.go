@@ -0,0 +1,86 @@
+package titlefilter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidPattern = errors.New("invalid title filter pattern")
+
+// Filter decides whether videos are kept based on their titles. Patterns are case-insensitive regular
+// expressions.
+type Filter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidPattern, pattern, err.Error())
+		}
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+// New compiles the include and exclude patterns of a channel into a filter
+func New(include []string, exclude []string) (*Filter, error) {
+	includeRes, err := compile(include)
+	if err != nil {
+		return nil, err
+	}
+	excludeRes, err := compile(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{include: includeRes, exclude: excludeRes}, nil
+}
+
+// Normalize trims the patterns and drops empty ones
+func Normalize(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			normalized = append(normalized, pattern)
+		}
+	}
+
+	return normalized
+}
+
+// Allows reports whether a video with the given title is kept. A title must match one of the include
+// patterns, if there are any, and none of the exclude patterns.
+func (f *Filter) Allows(title string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.include) > 0 {
+		included := false
+		for _, re := range f.include {
+			if re.MatchString(title) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, re := range f.exclude {
+		if re.MatchString(title) {
+			return false
+		}
+	}
+
+	return true
+}
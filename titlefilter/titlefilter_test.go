@@ -0,0 +1,48 @@
+package titlefilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		title   string
+		want    bool
+	}{
+		{name: "no patterns", title: "Anything", want: true},
+		{name: "excluded", exclude: []string{"#podcast"}, title: "Episode 12 #Podcast", want: false},
+		{name: "not excluded", exclude: []string{"#podcast", `live q&a`}, title: "Building a shed", want: true},
+		{name: "included", include: []string{`^part \d+`}, title: "Part 3: the roof", want: true},
+		{name: "not included", include: []string{`^part \d+`}, title: "Channel update", want: false},
+		{
+			name:    "included but excluded",
+			include: []string{"shed"},
+			exclude: []string{"sponsored"},
+			title:   "Shed build (sponsored)",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.include, tt.exclude)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.Allows(tt.title))
+		})
+	}
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	_, err := New(nil, []string{"(unclosed"})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c"}, Normalize([]string{" a ", "", "  ", "b c"}))
+}